@@ -0,0 +1,74 @@
+package initial
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+// InitAdmin system.admin 中配置的用户不存在时创建该管理员, 已存在时不修改密码, 只保证其为管理员
+// 已存在但密码为空(user 表增加密码字段之前创建的用户)时为其设置密码
+// 没有配置密码时生成一次性密码并只在日志中打印一次, 首次登录后必须修改
+func InitAdmin() {
+	admin := global.CONF.System.Admin
+	if admin.Name == "" {
+		return
+	}
+	if password := os.Getenv(envAdminPassword); password != "" {
+		admin.Password = password
+	}
+	ctx := context.Background()
+	userDao := dao.NewUserDao(database.GetDB(), nil)
+	user, err := userDao.GetByName(ctx, admin.Name)
+	exists := err == nil
+	if err != nil && !errors.Is(err, database.ErrRecordNotFound) {
+		panic(err.Error())
+	}
+	if exists {
+		// 保证配置的用户始终是管理员, 避免所有管理员被降级后无法管理
+		if user.Type != model.UserTypeAdmin {
+			err = userDao.UpdateByID(ctx, &model.User{ID: user.ID, Type: model.UserTypeAdmin})
+			if err != nil {
+				panic(err.Error())
+			}
+		}
+		if user.Password != "" {
+			return
+		}
+	}
+
+	mustChange := admin.Password == ""
+	if mustChange {
+		admin.Password, err = auth.RandomPassword()
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+	password, err := auth.HashPassword(admin.Password)
+	if err != nil {
+		panic(err.Error())
+	}
+	action := "created"
+	if exists {
+		action = "given a password"
+		err = userDao.UpdateByID(ctx, &model.User{ID: user.ID, Password: password, MustChangePassword: mustChange})
+	} else {
+		err = userDao.Create(ctx, &model.User{Name: admin.Name, Password: password, Status: model.UserStatusActive, Type: model.UserTypeAdmin, MustChangePassword: mustChange})
+	}
+	if err != nil {
+		panic(err.Error())
+	}
+	if mustChange {
+		logger.Warnf("[admin] user %s was %s with one-time password %s, change it after the first login", admin.Name, action, admin.Password)
+		return
+	}
+	logger.Infof("[admin] user %s was %s", admin.Name, action)
+}
@@ -0,0 +1,36 @@
+package initial
+
+import (
+	"os"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+// 部署时通过环境变量传入的密钥, 优先于配置文件
+const (
+	envJwtSignKey    = "K8SADMIN_JWT_SIGN_KEY"
+	envAdminPassword = "K8SADMIN_ADMIN_PASSWORD"
+	envEncryptKey    = "K8SADMIN_ENCRYPT_KEY"
+)
+
+// InitAuth 校验令牌签名密钥, 为空、使用示例密钥或太短时拒绝启动
+func InitAuth() {
+	if key := os.Getenv(envJwtSignKey); key != "" {
+		global.CONF.System.Jwt.SignKey = key
+	}
+	if err := auth.ValidateSignKey(global.CONF.System.Jwt.SignKey); err != nil {
+		panic(err.Error() + ", 请设置至少32字节的随机密钥, 或通过环境变量 " + envJwtSignKey + " 传入")
+	}
+}
+
+// InitEncryptKey 校验集群凭证和镜像仓库密码的加密密钥, 需要在加载已注册的集群之前调用
+func InitEncryptKey() {
+	if key := os.Getenv(envEncryptKey); key != "" {
+		global.CONF.System.EncryptKey = key
+	}
+	if err := cluster.ValidateEncryptKey(global.CONF.System.EncryptKey); err != nil {
+		panic(err.Error() + ", 请设置至少32字节的随机密钥, 或通过环境变量 " + envEncryptKey + " 传入")
+	}
+}
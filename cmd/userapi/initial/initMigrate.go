@@ -0,0 +1,32 @@
+package initial
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+// migrateModels 启动时自动建表的模型, 已存在的表只补充缺少的字段和索引, 不会删除字段
+// 已存在的 user 表需要增加的字段见 deployments/sql/user_auth.sql
+var migrateModels = []interface{}{
+	&model.User{},
+	&model.Cluster{},
+	&model.ExecSession{},
+	&model.Registry{},
+	&model.AppRole{},
+	&model.Team{},
+	&model.TeamMember{},
+	&model.AppRoleBinding{},
+	&model.Impersonation{},
+	&model.AuditLog{},
+}
+
+// InitMigrate 创建服务依赖的数据表, 需要在读写这些表之前调用
+func InitMigrate() {
+	err := database.GetDB().AutoMigrate(migrateModels...)
+	if err != nil {
+		panic("migrate tables error: " + err.Error())
+	}
+	logger.Infof("[database] %d tables were migrated", len(migrateModels))
+}
@@ -1,11 +1,17 @@
 package initial
 
 import (
+	"context"
+
 	"github.com/go-dev-frame/sponge/pkg/conf"
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/configs"
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/pullsecret"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
-	"k8s.io/client-go/kubernetes"
+	"github.com/xiaofan193/k8sadmin/pkg/harbor"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -15,12 +21,39 @@ func InitKubeConfigSet() {
 	if err != nil {
 		panic(err.Error())
 	}
-	clientset, err := kubernetes.NewForConfig(config)
+	defaultCluster, err := cluster.Register(cluster.DefaultName, config)
 	if err != nil {
 		panic(err.Error())
 	}
-	global.GlobalKubeConfigSet = clientset
+	global.GlobalKubeConfigSet = defaultCluster.KubeConfigSet
 	logger.Info("[k8sconfig statistics] was initialized")
+
+	go func() {
+		if defaultCluster.Cache.WaitForSync() {
+			logger.Info("[informer cache] default cluster was synced")
+		}
+	}()
+}
+
+// InitClusters 加载数据库中已注册的集群, 读取失败或单个集群连接失败都不影响服务启动
+func InitClusters() {
+	clusterDao := dao.NewClusterDao(database.GetDB())
+	list, err := clusterDao.GetAll(context.Background())
+	if err != nil {
+		// 只影响已注册集群的加载, 默认集群仍然可用
+		logger.Error("load clusters error", logger.Err(err))
+		return
+	}
+	for _, item := range list {
+		config, err := cluster.RestConfig(item)
+		if err == nil {
+			_, err = cluster.Register(item.Name, config)
+		}
+		if err != nil {
+			logger.Warn("register cluster error", logger.Err(err), logger.String("cluster", item.Name))
+		}
+	}
+	logger.Infof("[clusters] was initialized, total %d", len(cluster.List()))
 }
 
 func GetConfigK8sFromLocal() {
@@ -32,3 +65,22 @@ func GetConfigK8sFromLocal() {
 		panic(err.Error())
 	}
 }
+
+// InitHarbor 根据 system.harbor 配置创建harbor客户端, 未启用时 global.HarborClient 为nil
+func InitHarbor() {
+	harborConf := global.CONF.System.Harbor
+	if !harborConf.Enable || harborConf.Host == "" {
+		return
+	}
+	global.HarborClient = harbor.NewHarbor(harborConf.Scheme, harborConf.Host, harborConf.Username, harborConf.Password)
+	logger.Infof("[harbor] was initialized, host %s", harborConf.Host)
+}
+
+// SyncPullSecrets 启动时在后台用最新的镜像仓库凭证更新所有集群中已创建的托管 imagePullSecret
+func SyncPullSecrets() {
+	go func() {
+		for name, err := range pullsecret.SyncClusters(context.Background()) {
+			logger.Warn("sync pull secret error", logger.Err(err), logger.String("cluster", name))
+		}
+	}()
+}
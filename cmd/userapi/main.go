@@ -18,8 +18,15 @@ import (
 // @description Type Bearer your-jwt-token to Value
 func main() {
 	initial.InitApp()
-	initial.InitKubeConfigSet()
 	initial.GetConfigK8sFromLocal()
+	initial.InitAuth()
+	initial.InitEncryptKey()
+	initial.InitMigrate()
+	initial.InitHarbor()
+	initial.InitAdmin()
+	initial.InitKubeConfigSet()
+	initial.InitClusters()
+	initial.SyncPullSecrets()
 
 	services := initial.CreateServices()
 	closes := initial.Close(services)
@@ -0,0 +1,15 @@
+-- user 表增加登录认证相关字段
+-- 服务启动时会自动执行等价的变更, 数据库账号没有 DDL 权限时需要先手动执行本文件
+-- 已有用户的 password 为空, 无法登录, 设置密码的方式见同目录下的 README.md
+
+ALTER TABLE `user`
+    ADD COLUMN `password` varchar(100) NOT NULL DEFAULT '' COMMENT 'bcrypt 哈希' AFTER `name`,
+    ADD COLUMN `status` tinyint(4) NOT NULL DEFAULT 1 COMMENT '1:启用 2:禁用' AFTER `password`,
+    ADD COLUMN `type` tinyint(4) NOT NULL DEFAULT 2 COMMENT '1:管理员 2:普通用户' AFTER `status`,
+    ADD COLUMN `must_change_password` tinyint(1) NOT NULL DEFAULT 0 COMMENT '为1时修改密码前只能修改密码和注销' AFTER `type`,
+    ADD COLUMN `password_changed_at` datetime DEFAULT NULL COMMENT '在此之前签发的令牌无效' AFTER `must_change_password`,
+    ADD COLUMN `last_login_at` datetime DEFAULT NULL AFTER `password_changed_at`,
+    ADD COLUMN `last_login_ip` varchar(64) DEFAULT NULL AFTER `last_login_at`;
+
+-- 用户名用于登录, 必须唯一, 存在重名用户时需要先处理
+ALTER TABLE `user` ADD UNIQUE INDEX `idx_user_name` (`name`);
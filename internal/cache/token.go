@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-dev-frame/sponge/pkg/cache"
+	"github.com/go-dev-frame/sponge/pkg/encoding"
+
+	"github.com/xiaofan193/k8sadmin/internal/database"
+)
+
+const (
+	// cache prefix key, must end with a colon
+	tokenRevokedCachePrefixKey = "token:revoked:"
+)
+
+var _ TokenCache = (*tokenCache)(nil)
+
+// TokenCache 已注销的令牌, access token 和 refresh token 共用同一个 jwt id
+type TokenCache interface {
+	Revoke(ctx context.Context, jwtID string, duration time.Duration) error
+	IsRevoked(ctx context.Context, jwtID string) (bool, error)
+}
+
+// tokenCache define a cache struct
+type tokenCache struct {
+	cache cache.Cache
+}
+
+// NewTokenCache new a cache, 没有配置缓存时使用内存缓存, 注销只在当前实例生效
+func NewTokenCache(cacheType *database.CacheType) TokenCache {
+	jsonEncoding := encoding.JSONEncoding{}
+	cachePrefix := ""
+	newObject := func() interface{} {
+		var revoked bool
+		return &revoked
+	}
+
+	if cacheType != nil && strings.ToLower(cacheType.CType) == "redis" {
+		return &tokenCache{cache: cache.NewRedisCache(cacheType.Rdb, cachePrefix, jsonEncoding, newObject)}
+	}
+	return &tokenCache{cache: cache.NewMemoryCache(cachePrefix, jsonEncoding, newObject)}
+}
+
+// GetTokenRevokedCacheKey cache key
+func (c *tokenCache) GetTokenRevokedCacheKey(jwtID string) string {
+	return tokenRevokedCachePrefixKey + jwtID
+}
+
+// Revoke 注销令牌, duration 不小于令牌的剩余有效期
+func (c *tokenCache) Revoke(ctx context.Context, jwtID string, duration time.Duration) error {
+	if jwtID == "" {
+		return nil
+	}
+	return c.cache.Set(ctx, c.GetTokenRevokedCacheKey(jwtID), true, duration)
+}
+
+// IsRevoked 令牌是否已注销
+func (c *tokenCache) IsRevoked(ctx context.Context, jwtID string) (bool, error) {
+	var revoked bool
+	err := c.cache.Get(ctx, c.GetTokenRevokedCacheKey(jwtID), &revoked)
+	if err == nil {
+		return revoked, nil
+	}
+	if errors.Is(err, database.ErrCacheNotFound) || errors.Is(err, cache.ErrPlaceholder) {
+		return false, nil
+	}
+	return false, err
+}
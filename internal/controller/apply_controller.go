@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+const (
+	// server-side apply 的 field manager
+	applyFieldManager     = "k8sadmin"
+	applyDefaultNamespace = metav1.NamespaceDefault
+)
+
+var (
+	// ErrInvalidManifest 无法解析的YAML或JSON
+	ErrInvalidManifest = errors.New("invalid manifest")
+	// ErrApplyForbidden 当前用户没有对象所在命名空间的编辑权限
+	ErrApplyForbidden = errors.New("permission denied")
+)
+
+// ApplyAllowed 检查是否可以修改对象, 集群级资源的 namespace 为空
+type ApplyAllowed func(namespace string, gvk schema.GroupVersionKind) bool
+
+// ApplyController 通过dynamic客户端以 server-side apply 方式创建或更新任意资源
+type ApplyController struct {
+	Dynamic dynamic.Interface
+	Mapper  meta.ResettableRESTMapper
+}
+
+func NewApplyController(client dynamic.Interface, mapper meta.ResettableRESTMapper) *ApplyController {
+	return &ApplyController{
+		Dynamic: client,
+		Mapper:  mapper,
+	}
+}
+
+// ParseManifests 解析多文档YAML或JSON, List 展开为其中的对象, 空文档会被忽略
+func ParseManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	objects := make([]*unstructured.Unstructured, 0)
+	for doc := 0; ; doc++ {
+		ext := runtime.RawExtension{}
+		err := decoder.Decode(&ext)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: document %d: %v", ErrInvalidManifest, doc, err)
+		}
+		raw := bytes.TrimSpace(ext.Raw)
+		if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+			continue
+		}
+
+		obj, _, err := unstructured.UnstructuredJSONScheme.Decode(raw, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: document %d: %v", ErrInvalidManifest, doc, err)
+		}
+		switch v := obj.(type) {
+		case *unstructured.Unstructured:
+			objects = append(objects, v)
+		case *unstructured.UnstructuredList:
+			for i := range v.Items {
+				objects = append(objects, &v.Items[i])
+			}
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("%w: no objects found", ErrInvalidManifest)
+	}
+	for i, obj := range objects {
+		if obj.GetName() == "" {
+			return nil, fmt.Errorf("%w: object %d (%s): metadata.name is required", ErrInvalidManifest, i, obj.GetKind())
+		}
+	}
+	return objects, nil
+}
+
+// Apply 按顺序逐个应用对象, 单个对象失败不影响其余对象
+func (a *ApplyController) Apply(ctx context.Context, objects []*unstructured.Unstructured, reqParam *types.ApplyRequest, allowed ApplyAllowed) []*types.ApplyResult {
+	results := make([]*types.ApplyResult, 0, len(objects))
+	for i, obj := range objects {
+		res := &types.ApplyResult{
+			Index:      i,
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Name:       obj.GetName(),
+		}
+		result, err := a.applyObject(ctx, obj, reqParam, allowed)
+		res.Namespace = obj.GetNamespace()
+		if err != nil {
+			res.Result = types.ApplyResultFailed
+			res.Error = err.Error()
+		} else {
+			res.Result = result
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func (a *ApplyController) applyObject(ctx context.Context, obj *unstructured.Unstructured, reqParam *types.ApplyRequest, allowed ApplyAllowed) (string, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := a.restMapping(gvk)
+	if err != nil {
+		return "", err
+	}
+
+	var resource dynamic.ResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			namespace := reqParam.Namespace
+			if namespace == "" {
+				namespace = applyDefaultNamespace
+			}
+			obj.SetNamespace(namespace)
+		}
+		resource = a.Dynamic.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	} else {
+		obj.SetNamespace("")
+		resource = a.Dynamic.Resource(mapping.Resource)
+	}
+	if !allowed(obj.GetNamespace(), gvk) {
+		return "", ErrApplyForbidden
+	}
+
+	existing, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return "", err
+		}
+		existing = nil
+	}
+
+	options := metav1.ApplyOptions{FieldManager: applyFieldManager, Force: reqParam.Force}
+	if reqParam.DryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	// 导出的对象中包含 managedFields, apply 时必须为空
+	obj.SetManagedFields(nil)
+	applied, err := resource.Apply(ctx, obj.GetName(), obj, options)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case existing == nil:
+		return types.ApplyResultCreated, nil
+	case !reqParam.DryRun && applied.GetResourceVersion() == existing.GetResourceVersion():
+		return types.ApplyResultUnchanged, nil
+	default:
+		return types.ApplyResultConfigured, nil
+	}
+}
+
+// restMapping 找不到资源类型时重新加载discovery, 兼容新安装的CRD
+func (a *ApplyController) restMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := a.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		a.Mapper.Reset()
+		mapping, err = a.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	return mapping, err
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+const applyManifests = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  replicas: "3"
+---
+# 空文档会被忽略
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: team-a
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: other
+    namespace: kube-system
+`
+
+func TestParseManifests(t *testing.T) {
+	objects, err := ParseManifests([]byte(applyManifests))
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+	assert.Equal(t, "app-config", objects[0].GetName())
+	assert.Equal(t, "Namespace", objects[1].GetKind())
+	assert.Equal(t, "kube-system", objects[2].GetNamespace())
+
+	objects, err = ParseManifests([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"json"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "json", objects[0].GetName())
+
+	_, err = ParseManifests([]byte("---\n"))
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+	_, err = ParseManifests([]byte("apiVersion: v1\nkind: ConfigMap\n"))
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+	_, err = ParseManifests([]byte("metadata:\n  name: x\n"))
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+}
+
+func TestApplyController_Apply(t *testing.T) {
+	objects, err := ParseManifests([]byte(applyManifests))
+	require.NoError(t, err)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	// fake 客户端不支持 server-side apply, 直接返回提交的对象
+	var patches []k8stesting.PatchAction
+	client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		patches = append(patches, patch)
+		obj := &unstructured.Unstructured{}
+		return true, obj, obj.UnmarshalJSON(patch.GetPatch())
+	})
+
+	allowed := func(namespace string, gvk schema.GroupVersionKind) bool {
+		return namespace != "kube-system"
+	}
+	results := NewApplyController(client, meta.MultiRESTMapper{mapper}).Apply(context.Background(), objects, &types.ApplyRequest{Namespace: "app"}, allowed)
+	require.Len(t, results, 3)
+	assert.Equal(t, "app", results[0].Namespace)
+	assert.Equal(t, types.ApplyResultCreated, results[0].Result, results[0].Error)
+	assert.Equal(t, "", results[1].Namespace)
+	assert.Equal(t, types.ApplyResultCreated, results[1].Result, results[1].Error)
+	assert.Equal(t, types.ApplyResultFailed, results[2].Result)
+	assert.Equal(t, ErrApplyForbidden.Error(), results[2].Error)
+
+	require.Len(t, patches, 2)
+	assert.Equal(t, k8stypes.ApplyPatchType, patches[0].GetPatchType())
+	assert.Equal(t, "app", patches[0].GetNamespace())
+}
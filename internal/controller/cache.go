@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"context"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+)
+
+// 列表查询统一入口: informer缓存同步完成时从lister读取, 否则回退到直接请求apiserver
+// 返回的对象可能与缓存共享, 只能读取
+
+func listPods(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]corev1.Pod, error) {
+	if lister, ok := cache.Pods(); ok {
+		return fromLister(lister.Pods(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listNamespaces(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) ([]corev1.Namespace, error) {
+	if lister, ok := cache.Namespaces(); ok {
+		return fromLister(lister.List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listNodes(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) ([]corev1.Node, error) {
+	if lister, ok := cache.Nodes(); ok {
+		return fromLister(lister.List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listConfigMaps(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]corev1.ConfigMap, error) {
+	if lister, ok := cache.ConfigMaps(); ok {
+		return fromLister(lister.ConfigMaps(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listSecrets(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]corev1.Secret, error) {
+	if lister, ok := cache.Secrets(); ok {
+		return fromLister(lister.Secrets(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listServices(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]corev1.Service, error) {
+	if lister, ok := cache.Services(); ok {
+		return fromLister(lister.Services(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listServiceAccounts(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]corev1.ServiceAccount, error) {
+	if lister, ok := cache.ServiceAccounts(); ok {
+		return fromLister(lister.ServiceAccounts(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listPersistentVolumes(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) ([]corev1.PersistentVolume, error) {
+	if lister, ok := cache.PersistentVolumes(); ok {
+		return fromLister(lister.List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listPersistentVolumeClaims(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]corev1.PersistentVolumeClaim, error) {
+	if lister, ok := cache.PersistentVolumeClaims(); ok {
+		return fromLister(lister.PersistentVolumeClaims(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listStorageClasses(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) ([]storagev1.StorageClass, error) {
+	if lister, ok := cache.StorageClasses(); ok {
+		return fromLister(lister.List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listDeployments(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]appsv1.Deployment, error) {
+	if lister, ok := cache.Deployments(); ok {
+		return fromLister(lister.Deployments(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listDaemonSets(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]appsv1.DaemonSet, error) {
+	if lister, ok := cache.DaemonSets(); ok {
+		return fromLister(lister.DaemonSets(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listStatefulSets(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]appsv1.StatefulSet, error) {
+	if lister, ok := cache.StatefulSets(); ok {
+		return fromLister(lister.StatefulSets(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listJobs(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]batchv1.Job, error) {
+	if lister, ok := cache.Jobs(); ok {
+		return fromLister(lister.Jobs(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listCronJobs(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]batchv1.CronJob, error) {
+	if lister, ok := cache.CronJobs(); ok {
+		return fromLister(lister.CronJobs(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listIngresses(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]networkingv1.Ingress, error) {
+	if lister, ok := cache.Ingresses(); ok {
+		return fromLister(lister.Ingresses(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listRoles(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]rbacv1.Role, error) {
+	if lister, ok := cache.Roles(); ok {
+		return fromLister(lister.Roles(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listClusterRoles(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) ([]rbacv1.ClusterRole, error) {
+	if lister, ok := cache.ClusterRoles(); ok {
+		return fromLister(lister.List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listRoleBindings(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) ([]rbacv1.RoleBinding, error) {
+	if lister, ok := cache.RoleBindings(); ok {
+		return fromLister(lister.RoleBindings(namespace).List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func listClusterRoleBindings(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) ([]rbacv1.ClusterRoleBinding, error) {
+	if lister, ok := cache.ClusterRoleBindings(); ok {
+		return fromLister(lister.List(labels.Everything()))
+	}
+	list, err := kubeConfigSet.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// fromLister lister返回的是无序的指针切片, 转为与apiserver List一致的按 namespace/name 排序的值切片
+func fromLister[T any, PT interface {
+	*T
+	metav1.Object
+}](items []PT, err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+	list := make([]T, 0, len(items))
+	for _, item := range items {
+		list = append(list, *item)
+	}
+	return list, nil
+}
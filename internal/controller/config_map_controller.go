@@ -3,31 +3,26 @@ package controller
 import (
 	"context"
 	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/configmap"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"sync"
-)
-
-var (
-	configMapInstance       *ConfigMapController
-	configMapControllerOnce sync.Once
 )
 
 type ConfigMapController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          global.Server
 }
 
-func NewConfigMapController() *ConfigMapController {
-	configMapControllerOnce.Do(func() {
-		configMapInstance = &ConfigMapController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-		}
-	})
-	return configMapInstance
+func NewConfigMapController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *ConfigMapController {
+	return &ConfigMapController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
 }
 
 func (c *ConfigMapController) CreateOrUpdateConfigMap(ctx context.Context, reqparam *types.CreateOrUpdateConfigMapRequest) error {
@@ -67,21 +62,22 @@ func (c *ConfigMapController) GetConfigMapDetail(ctx context.Context, reqparam *
 	return cm, nil
 }
 
-func (c *ConfigMapController) GetConfigMapList(ctx context.Context, reqparam *types.GetConfigMapDetailORListRequest) ([]*types.ConfigMapRes, error) {
-	list, err := c.KubeConfigSet.CoreV1().ConfigMaps(reqparam.Namespace).List(ctx, metav1.ListOptions{})
+func (c *ConfigMapController) GetConfigMapList(ctx context.Context, reqparam *types.GetConfigMapDetailORListRequest, query *types.ListQuery) ([]*types.ConfigMapRes, *types.ListMeta, error) {
+	items, err := listConfigMaps(ctx, c.KubeConfigSet, c.Cache, reqparam.Namespace)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 	k82Res := &configmap.K82Res{}
 	configMapList := make([]*types.ConfigMapRes, 0)
-	for _, cm := range list.Items {
-		//if !strings.Contains(cm.Name,reqparam.Keyword) {
-		//	continue
-		//}
+	for _, cm := range items {
 		cmRes := k82Res.GeCmReqDetail(&cm)
 		configMapList = append(configMapList, cmRes)
 	}
-	return configMapList, nil
+	return configMapList, listMeta, nil
 }
 
 func (c *ConfigMapController) DeleteConfigMap(ctx context.Context, reqparam *types.DeleteConfigMapRequest) error {
@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+const (
+	// 与 kubectl create job --from=cronjob/xxx 使用相同的注解
+	cronJobInstantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+	cronJobInstantiateManual     = "manual"
+	// job 名称会写入pod标签, 不能超过63个字符, 需要给 -manual-<时间戳> 留出空间
+	cronJobManualNameMaxLen = 45
+)
+
+type CronJobController struct {
+	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
+	CONF          global.Server
+}
+
+func NewCronJobController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *CronJobController {
+	return &CronJobController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
+}
+
+func (cj *CronJobController) CreateOrUpdateCronJob(ctx context.Context, reqParam *types.CronJobRequest) error {
+	suspend := reqParam.Base.Suspend
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      reqParam.Base.Name,
+			Namespace: reqParam.Base.Namespace,
+			Labels:    maputils.ToMap(reqParam.Base.Labels),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   reqParam.Base.Schedule,
+			ConcurrencyPolicy:          batchv1.ConcurrencyPolicy(reqParam.Base.ConcurrencyPolicy),
+			Suspend:                    &suspend,
+			StartingDeadlineSeconds:    reqParam.Base.StartingDeadlineSeconds,
+			SuccessfulJobsHistoryLimit: reqParam.Base.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     reqParam.Base.FailedJobsHistoryLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: jobSpecReq2K8s(&reqParam.Base.JobSpec, reqParam.Template),
+			},
+		},
+	}
+	if reqParam.Base.TimeZone != "" {
+		cronJob.Spec.TimeZone = &reqParam.Base.TimeZone
+	}
+
+	cronJobApi := cj.KubeConfigSet.BatchV1().CronJobs(cronJob.Namespace)
+	cronJobK8s, err := cronJobApi.Get(ctx, cronJob.Name, metav1.GetOptions{})
+	if err == nil {
+		err = applyImagePullSecrets(ctx, cj.KubeConfigSet, cronJob.Namespace, reqParam.Template.ImagePullSecrets, &cronJob.Spec.JobTemplate.Spec.Template.Spec, &cronJobK8s.Spec.JobTemplate.Spec.Template.Spec)
+		if err != nil {
+			return err
+		}
+		// 修改只影响之后创建的job
+		cronJobK8s.Labels = cronJob.Labels
+		cronJobK8s.Spec = cronJob.Spec
+		_, err = cronJobApi.Update(ctx, cronJobK8s, metav1.UpdateOptions{})
+	} else {
+		err = applyImagePullSecrets(ctx, cj.KubeConfigSet, cronJob.Namespace, reqParam.Template.ImagePullSecrets, &cronJob.Spec.JobTemplate.Spec.Template.Spec, nil)
+		if err != nil {
+			return err
+		}
+		_, err = cronJobApi.Create(ctx, cronJob, metav1.CreateOptions{})
+	}
+	return err
+}
+
+func (cj *CronJobController) GetCronJobDetail(ctx context.Context, namespace string, name string) (*types.CronJobResponse, error) {
+	cronJobK8s, err := cj.KubeConfigSet.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	jobSpec, podRes := jobSpecK8s2Req(&cronJobK8s.Spec.JobTemplate.Spec)
+	cronJobRes := &types.CronJobResponse{
+		Base: &types.CronJobBase{
+			Name:                       cronJobK8s.Name,
+			Namespace:                  cronJobK8s.Namespace,
+			Labels:                     maputils.ToList(cronJobK8s.Labels),
+			Schedule:                   cronJobK8s.Spec.Schedule,
+			ConcurrencyPolicy:          string(cronJobK8s.Spec.ConcurrencyPolicy),
+			Suspend:                    cronJobK8s.Spec.Suspend != nil && *cronJobK8s.Spec.Suspend,
+			StartingDeadlineSeconds:    cronJobK8s.Spec.StartingDeadlineSeconds,
+			SuccessfulJobsHistoryLimit: cronJobK8s.Spec.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     cronJobK8s.Spec.FailedJobsHistoryLimit,
+			JobSpec:                    jobSpec,
+		},
+		Template: podRes,
+	}
+	if cronJobK8s.Spec.TimeZone != nil {
+		cronJobRes.Base.TimeZone = *cronJobK8s.Spec.TimeZone
+	}
+	return cronJobRes, nil
+}
+
+func (cj *CronJobController) GetCronJobList(ctx context.Context, namespace string, query *types.ListQuery) ([]*types.CronJobRes, *types.ListMeta, error) {
+	cronJobList := make([]*types.CronJobRes, 0)
+	items, err := listCronJobs(ctx, cj.KubeConfigSet, cj.Cache, namespace)
+	if err != nil {
+		return cronJobList, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return cronJobList, nil, err
+	}
+	if len(items) == 0 {
+		return cronJobList, listMeta, nil
+	}
+
+	// 按owner分组, 统计保留的执行记录中成功和失败的次数
+	jobsByCronJob, err := cronJobJobsByOwner(ctx, cj.KubeConfigSet, cj.Cache, namespace)
+	if err != nil {
+		return cronJobList, nil, err
+	}
+	for i := range items {
+		cronJobList = append(cronJobList, cronJobListItem(&items[i], jobsByCronJob[items[i].UID]))
+	}
+	return cronJobList, listMeta, nil
+}
+
+func (cj *CronJobController) DeleteCronJob(ctx context.Context, namespace, name string) error {
+	// 同时删除创建的job和pod
+	propagation := metav1.DeletePropagationBackground
+	return cj.KubeConfigSet.BatchV1().CronJobs(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+}
+
+// SuspendCronJob 暂停或恢复调度, 已经在运行的job不受影响
+func (cj *CronJobController) SuspendCronJob(ctx context.Context, namespace, name string, suspend bool) error {
+	patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
+	_, err := cj.KubeConfigSet.BatchV1().CronJobs(namespace).Patch(ctx, name, k8stypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// TriggerCronJob 立即按cronjob的模板创建一个job, 返回job名称
+func (cj *CronJobController) TriggerCronJob(ctx context.Context, namespace, name string) (string, error) {
+	cronJobK8s, err := cj.KubeConfigSet.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	prefix := cronJobK8s.Name
+	if len(prefix) > cronJobManualNameMaxLen {
+		prefix = prefix[:cronJobManualNameMaxLen]
+	}
+	annotations := map[string]string{cronJobInstantiateAnnotation: cronJobInstantiateManual}
+	for k, v := range cronJobK8s.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	// ownerReference 指向cronjob, 手动触发的job也会计入执行记录并按 historyLimit 清理
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        prefix + "-manual-" + strconv.FormatInt(time.Now().Unix(), 10),
+			Namespace:   cronJobK8s.Namespace,
+			Labels:      cronJobK8s.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJobK8s, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronJobK8s.Spec.JobTemplate.Spec,
+	}
+	jobK8s, err := cj.KubeConfigSet.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return jobK8s.Name, nil
+}
+
+// GetCronJobHistory cronjob 保留的执行记录及每个pod的结果, 按创建时间倒序
+func (cj *CronJobController) GetCronJobHistory(ctx context.Context, namespace, name string) ([]*types.JobRes, error) {
+	cronJobK8s, err := cj.KubeConfigSet.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	jobsByCronJob, err := cronJobJobsByOwner(ctx, cj.KubeConfigSet, cj.Cache, namespace)
+	if err != nil {
+		return nil, err
+	}
+	jobs := jobsByCronJob[cronJobK8s.UID]
+	history := make([]*types.JobRes, 0, len(jobs))
+	if len(jobs) == 0 {
+		return history, nil
+	}
+
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[k].CreationTimestamp.Before(&jobs[i].CreationTimestamp)
+	})
+	podsByJob, err := jobPodsByOwner(ctx, cj.KubeConfigSet, cj.Cache, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		item := jobListItem(&jobs[i])
+		item.Pods = jobPodList(podsByJob[jobs[i].UID])
+		history = append(history, item)
+	}
+	return history, nil
+}
+
+func cronJobJobsByOwner(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) (map[k8stypes.UID][]batchv1.Job, error) {
+	jobs, err := listJobs(ctx, kubeConfigSet, cache, namespace)
+	if err != nil {
+		return nil, err
+	}
+	jobsByCronJob := make(map[k8stypes.UID][]batchv1.Job)
+	for _, item := range jobs {
+		if owner := metav1.GetControllerOf(&item); owner != nil && owner.Kind == "CronJob" {
+			jobsByCronJob[owner.UID] = append(jobsByCronJob[owner.UID], item)
+		}
+	}
+	return jobsByCronJob, nil
+}
+
+func cronJobListItem(item *batchv1.CronJob, jobs []batchv1.Job) *types.CronJobRes {
+	res := &types.CronJobRes{
+		Name:      item.Name,
+		Namespace: item.Namespace,
+		Schedule:  item.Spec.Schedule,
+		Suspend:   item.Spec.Suspend != nil && *item.Spec.Suspend,
+		Active:    len(item.Status.Active),
+		Age:       item.CreationTimestamp.Unix(),
+	}
+	if item.Status.LastScheduleTime != nil {
+		res.LastScheduleTime = item.Status.LastScheduleTime.Unix()
+	}
+	if item.Status.LastSuccessfulTime != nil {
+		res.LastSuccessfulTime = item.Status.LastSuccessfulTime.Unix()
+	}
+	for i := range jobs {
+		switch jobStatus(&jobs[i]) {
+		case types.JobStatusComplete:
+			res.Succeeded++
+		case types.JobStatusFailed:
+			res.Failed++
+		}
+	}
+	return res
+}
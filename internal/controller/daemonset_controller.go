@@ -2,6 +2,8 @@ package controller
 
 import (
 	"context"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/pod"
 	"github.com/xiaofan193/k8sadmin/internal/types"
@@ -10,26 +12,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"sync"
-)
-
-var (
-	daemonsetInstance       *Daemonsetontroller
-	daemonsetControllerOnce sync.Once
 )
 
 type Daemonsetontroller struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          global.Server
 }
 
-func NewDaemonsetController() *Daemonsetontroller {
-	daemonsetControllerOnce.Do(func() {
-		daemonsetInstance = &Daemonsetontroller{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-		}
-	})
-	return daemonsetInstance
+func NewDaemonsetController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *Daemonsetontroller {
+	return &Daemonsetontroller{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
 }
 
 func (s *Daemonsetontroller) CreateOrDaemonset(ctx context.Context, reqParam *types.DaemonsetReaqust) error {
@@ -57,9 +52,17 @@ func (s *Daemonsetontroller) CreateOrDaemonset(ctx context.Context, reqParam *ty
 	daemonsetApi := s.KubeConfigSet.AppsV1().DaemonSets(daemonset.Namespace)
 	daemonsetK8s, err := daemonsetApi.Get(ctx, daemonset.Name, metav1.GetOptions{})
 	if err == nil {
+		err = applyImagePullSecrets(ctx, s.KubeConfigSet, daemonset.Namespace, reqParam.Template.ImagePullSecrets, &daemonset.Spec.Template.Spec, &daemonsetK8s.Spec.Template.Spec)
+		if err != nil {
+			return err
+		}
 		daemonsetK8s.Spec = daemonset.Spec
 		_, err = daemonsetApi.Update(ctx, daemonsetK8s, metav1.UpdateOptions{})
 	} else {
+		err = applyImagePullSecrets(ctx, s.KubeConfigSet, daemonset.Namespace, reqParam.Template.ImagePullSecrets, &daemonset.Spec.Template.Spec, nil)
+		if err != nil {
+			return err
+		}
 		_, err = daemonsetApi.Create(ctx, daemonset, metav1.CreateOptions{})
 	}
 	return err
@@ -94,26 +97,35 @@ func (s *Daemonsetontroller) GetDaemonsetDetail(ctx context.Context, namespace s
 	return daemonsetRes, err
 }
 
-func (s *Daemonsetontroller) GetDaemonsetList(ctx context.Context, namespace string) ([]*types.DaemonSetRes, error) {
+func (s *Daemonsetontroller) GetDaemonsetList(ctx context.Context, namespace string, query *types.ListQuery) ([]*types.DaemonSetRes, *types.ListMeta, error) {
 	daemonsetList := make([]*types.DaemonSetRes, 0)
-	list, err := s.KubeConfigSet.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	items, err := listDaemonSets(ctx, s.KubeConfigSet, s.Cache, namespace)
+	if err != nil {
+		return daemonsetList, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
 	if err != nil {
-		return daemonsetList, nil
+		return daemonsetList, nil, err
 	}
 
-	for _, item := range list.Items {
-		daemonsetList = append(daemonsetList, &types.DaemonSetRes{
-			Name:      item.Name,
-			Namespace: namespace,
-			Age:       item.CreationTimestamp.Unix(),
-			Ready:     item.Status.NumberReady,
-			Available: item.Status.NumberAvailable,
-			UpToData:  item.Status.UpdatedNumberScheduled,
-			Current:   item.Status.CurrentNumberScheduled,
-		})
+	for i := range items {
+		daemonsetList = append(daemonsetList, daemonsetListItem(&items[i]))
 	}
 
-	return daemonsetList, err
+	return daemonsetList, listMeta, nil
+}
+
+// daemonsetListItem 列表与watch接口共用的转换
+func daemonsetListItem(item *appsv1.DaemonSet) *types.DaemonSetRes {
+	return &types.DaemonSetRes{
+		Name:      item.Name,
+		Namespace: item.Namespace,
+		Age:       item.CreationTimestamp.Unix(),
+		Ready:     item.Status.NumberReady,
+		Available: item.Status.NumberAvailable,
+		UpToData:  item.Status.UpdatedNumberScheduled,
+		Current:   item.Status.CurrentNumberScheduled,
+	}
 }
 
 func (s *Daemonsetontroller) DeleteDaemonset(ctx context.Context, namespace, name string) error {
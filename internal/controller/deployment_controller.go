@@ -2,34 +2,38 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/pod"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"sync"
-)
-
-var (
-	deploymentInstance       *DeploymentController
-	deploymentControllerOnce sync.Once
 )
 
 type DeploymentController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          global.Server
 }
 
-func NewDeploymentController() *DeploymentController {
-	deploymentControllerOnce.Do(func() {
-		deploymentInstance = &DeploymentController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-		}
-	})
-	return deploymentInstance
+func NewDeploymentController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *DeploymentController {
+	return &DeploymentController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
 }
 
 func (s *DeploymentController) CreateOrDeployment(ctx context.Context, reqParam *types.DeploymentRequest) error {
@@ -57,9 +61,17 @@ func (s *DeploymentController) CreateOrDeployment(ctx context.Context, reqParam
 	deploymentApi := s.KubeConfigSet.AppsV1().Deployments(deployment.Namespace)
 	deploymentK8s, err := deploymentApi.Get(ctx, deployment.Name, metav1.GetOptions{})
 	if err == nil {
+		err = applyImagePullSecrets(ctx, s.KubeConfigSet, deployment.Namespace, reqParam.Template.ImagePullSecrets, &deployment.Spec.Template.Spec, &deploymentK8s.Spec.Template.Spec)
+		if err != nil {
+			return err
+		}
 		deploymentK8s.Spec = deployment.Spec
 		_, err = deploymentApi.Update(ctx, deploymentK8s, metav1.UpdateOptions{})
 	} else {
+		err = applyImagePullSecrets(ctx, s.KubeConfigSet, deployment.Namespace, reqParam.Template.ImagePullSecrets, &deployment.Spec.Template.Spec, nil)
+		if err != nil {
+			return err
+		}
 		_, err = deploymentApi.Create(ctx, deployment, metav1.CreateOptions{})
 	}
 	return err
@@ -94,27 +106,275 @@ func (s *DeploymentController) GetDeploymentDetail(ctx context.Context, namespac
 	return deploymentRes, err
 }
 
-func (s *DeploymentController) GetDeploymentList(ctx context.Context, namespace string) ([]*types.DeploymentRes, error) {
+func (s *DeploymentController) GetDeploymentList(ctx context.Context, namespace string, query *types.ListQuery) ([]*types.DeploymentRes, *types.ListMeta, error) {
 	deploymentList := make([]*types.DeploymentRes, 0)
-	list, err := s.KubeConfigSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	items, err := listDeployments(ctx, s.KubeConfigSet, s.Cache, namespace)
+	if err != nil {
+		return deploymentList, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
 	if err != nil {
-		return deploymentList, nil
+		return deploymentList, nil, err
 	}
 
-	for _, item := range list.Items {
-		deploymentList = append(deploymentList, &types.DeploymentRes{
-			Name:       item.Name,
-			Namespace:  namespace,
-			Age:        item.CreationTimestamp.Unix(),
-			Replicas:   *item.Spec.Replicas,
-			Available:  item.Status.AvailableReplicas,
-			UpdateDate: item.Status.UpdatedReplicas,
-		})
+	for i := range items {
+		deploymentList = append(deploymentList, deploymentListItem(&items[i]))
 	}
 
-	return deploymentList, err
+	return deploymentList, listMeta, nil
+}
+
+// deploymentListItem 列表与watch接口共用的转换
+func deploymentListItem(item *appsv1.Deployment) *types.DeploymentRes {
+	res := &types.DeploymentRes{
+		Name:       item.Name,
+		Namespace:  item.Namespace,
+		Age:        item.CreationTimestamp.Unix(),
+		Available:  item.Status.AvailableReplicas,
+		UpdateDate: item.Status.UpdatedReplicas,
+	}
+	if item.Spec.Replicas != nil {
+		res.Replicas = *item.Spec.Replicas
+	}
+	return res
 }
 
 func (s *DeploymentController) DeleteDeployment(ctx context.Context, namespace, name string) error {
 	return s.KubeConfigSet.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 }
+
+const (
+	// 与 kubectl rollout restart 使用相同的注解
+	deploymentRestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	deploymentRevisionAnnotation    = "deployment.kubernetes.io/revision"
+	deploymentChangeCauseAnnotation = "kubernetes.io/change-cause"
+	// 超过 progressDeadlineSeconds 时 Progressing 条件的 reason
+	deploymentTimedOutReason = "ProgressDeadlineExceeded"
+)
+
+// ScaleDeployment 通过 scale 子资源修改副本数, 不影响其他字段
+func (s *DeploymentController) ScaleDeployment(ctx context.Context, namespace, name string, replicas int32) error {
+	deploymentApi := s.KubeConfigSet.AppsV1().Deployments(namespace)
+	scale, err := deploymentApi.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	scale.Spec.Replicas = replicas
+	_, err = deploymentApi.UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+	return err
+}
+
+// RestartDeployment 修改pod模板的注解触发滚动重启
+func (s *DeploymentController) RestartDeployment(ctx context.Context, namespace, name string) error {
+	deploymentApi := s.KubeConfigSet.AppsV1().Deployments(namespace)
+	deploymentK8s, err := deploymentApi.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if deploymentK8s.Spec.Paused {
+		return apierrors.NewBadRequest("deployment 已暂停, 需要先恢复发布才能重启")
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						deploymentRestartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = deploymentApi.Patch(ctx, name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// PauseDeployment 暂停或恢复发布, 暂停期间修改模板不会触发滚动更新
+func (s *DeploymentController) PauseDeployment(ctx context.Context, namespace, name string, paused bool) error {
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	_, err := s.KubeConfigSet.AppsV1().Deployments(namespace).Patch(ctx, name, k8stypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// GetDeploymentHistory 历史版本, 按版本号倒序, 每个版本包含相对上一个版本的模板变化
+func (s *DeploymentController) GetDeploymentHistory(ctx context.Context, namespace, name string) ([]*types.DeploymentRevision, error) {
+	deploymentK8s, err := s.KubeConfigSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	replicaSets, err := s.listDeploymentReplicaSets(ctx, deploymentK8s)
+	if err != nil {
+		return nil, err
+	}
+
+	current := deploymentK8s.Annotations[deploymentRevisionAnnotation]
+	history := make([]*types.DeploymentRevision, 0, len(replicaSets))
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		revision := &types.DeploymentRevision{
+			Revision:    replicaSetRevision(rs),
+			ReplicaSet:  rs.Name,
+			Images:      make([]string, 0, len(rs.Spec.Template.Spec.Containers)),
+			ChangeCause: rs.Annotations[deploymentChangeCauseAnnotation],
+			Replicas:    rs.Status.Replicas,
+			Current:     rs.Annotations[deploymentRevisionAnnotation] == current,
+			Changes:     make([]types.TemplateChange, 0),
+			Age:         rs.CreationTimestamp.Unix(),
+		}
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			revision.Images = append(revision.Images, container.Image)
+		}
+		if i > 0 {
+			revision.Changes = pod.TemplateDiff(&replicaSets[i-1].Spec.Template, &rs.Spec.Template)
+		}
+		history = append(history, revision)
+	}
+	slices.Reverse(history)
+	return history, nil
+}
+
+// RollbackDeployment 使用历史版本的pod模板更新deployment, 与 kubectl rollout undo 相同会生成一个新的版本号
+func (s *DeploymentController) RollbackDeployment(ctx context.Context, namespace, name string, revision int64) error {
+	deploymentApi := s.KubeConfigSet.AppsV1().Deployments(namespace)
+	deploymentK8s, err := deploymentApi.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if deploymentK8s.Spec.Paused {
+		return apierrors.NewBadRequest("deployment 已暂停, 需要先恢复发布才能回滚")
+	}
+	replicaSets, err := s.listDeploymentReplicaSets(ctx, deploymentK8s)
+	if err != nil {
+		return err
+	}
+
+	target := rollbackTarget(replicaSets, deploymentK8s.Annotations[deploymentRevisionAnnotation], revision)
+	if target == nil {
+		return apierrors.NewNotFound(appsv1.Resource("replicasets"), fmt.Sprintf("%s revision %d", name, revision))
+	}
+
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	deploymentK8s.Spec.Template = *template
+	if changeCause, ok := target.Annotations[deploymentChangeCauseAnnotation]; ok {
+		if deploymentK8s.Annotations == nil {
+			deploymentK8s.Annotations = make(map[string]string)
+		}
+		deploymentK8s.Annotations[deploymentChangeCauseAnnotation] = changeCause
+	}
+	_, err = deploymentApi.Update(ctx, deploymentK8s, metav1.UpdateOptions{})
+	return err
+}
+
+// GetDeploymentRolloutStatus 发布进度
+func (s *DeploymentController) GetDeploymentRolloutStatus(ctx context.Context, namespace, name string) (*types.DeploymentRolloutStatus, error) {
+	deploymentK8s, err := s.KubeConfigSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return deploymentRolloutStatus(deploymentK8s), nil
+}
+
+// listDeploymentReplicaSets deployment 创建的replicaset, 按版本号升序
+func (s *DeploymentController) listDeploymentReplicaSets(ctx context.Context, deploymentK8s *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploymentK8s.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	rsList, err := s.KubeConfigSet.AppsV1().ReplicaSets(deploymentK8s.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	replicaSets := make([]appsv1.ReplicaSet, 0, len(rsList.Items))
+	for _, item := range rsList.Items {
+		if owner := metav1.GetControllerOf(&item); owner != nil && owner.UID == deploymentK8s.UID {
+			replicaSets = append(replicaSets, item)
+		}
+	}
+	sort.Slice(replicaSets, func(i, j int) bool {
+		return replicaSetRevision(&replicaSets[i]) < replicaSetRevision(&replicaSets[j])
+	})
+	return replicaSets, nil
+}
+
+func replicaSetRevision(rs *appsv1.ReplicaSet) int64 {
+	revision, _ := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+	return revision
+}
+
+// rollbackTarget revision 为0时取当前版本之前最新的版本, replicaSets 需要按版本号升序
+func rollbackTarget(replicaSets []appsv1.ReplicaSet, current string, revision int64) *appsv1.ReplicaSet {
+	currentRevision, _ := strconv.ParseInt(current, 10, 64)
+	for i := len(replicaSets) - 1; i >= 0; i-- {
+		rsRevision := replicaSetRevision(&replicaSets[i])
+		if revision == 0 && rsRevision < currentRevision {
+			return &replicaSets[i]
+		}
+		if revision != 0 && rsRevision == revision {
+			return &replicaSets[i]
+		}
+	}
+	return nil
+}
+
+// deploymentRolloutStatus 与 kubectl rollout status 的判断顺序相同
+func deploymentRolloutStatus(item *appsv1.Deployment) *types.DeploymentRolloutStatus {
+	status := &types.DeploymentRolloutStatus{
+		Updated:     item.Status.UpdatedReplicas,
+		Ready:       item.Status.ReadyReplicas,
+		Available:   item.Status.AvailableReplicas,
+		Unavailable: item.Status.UnavailableReplicas,
+		Paused:      item.Spec.Paused,
+		Conditions:  make([]types.DeploymentCondition, 0, len(item.Status.Conditions)),
+	}
+	status.Revision, _ = strconv.ParseInt(item.Annotations[deploymentRevisionAnnotation], 10, 64)
+	if item.Spec.Replicas != nil {
+		status.Replicas = *item.Spec.Replicas
+	}
+	for _, condition := range item.Status.Conditions {
+		status.Conditions = append(status.Conditions, types.DeploymentCondition{
+			Type:           string(condition.Type),
+			Status:         string(condition.Status),
+			Reason:         condition.Reason,
+			Message:        condition.Message,
+			LastUpdateTime: condition.LastUpdateTime.Unix(),
+		})
+	}
+
+	switch {
+	case item.Generation > item.Status.ObservedGeneration:
+		status.Message = "等待控制器处理最新的配置"
+	case isDeploymentTimedOut(item):
+		status.Stuck = true
+		status.Message = "超过 progressDeadlineSeconds 仍未完成发布"
+	case item.Status.UpdatedReplicas < status.Replicas:
+		status.Message = fmt.Sprintf("%d/%d 个副本已更新", item.Status.UpdatedReplicas, status.Replicas)
+	case item.Status.Replicas > item.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d 个旧副本等待终止", item.Status.Replicas-item.Status.UpdatedReplicas)
+	case item.Status.AvailableReplicas < item.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d/%d 个已更新的副本可用", item.Status.AvailableReplicas, item.Status.UpdatedReplicas)
+	default:
+		status.Complete = true
+		status.Message = "发布完成"
+	}
+	if status.Paused && !status.Complete {
+		status.Message += ", 发布已暂停"
+	}
+	return status
+}
+
+func isDeploymentTimedOut(item *appsv1.Deployment) bool {
+	for _, condition := range item.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing {
+			return condition.Reason == deploymentTimedOutReason
+		}
+	}
+	return false
+}
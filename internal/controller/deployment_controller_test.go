@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeploymentRolloutStatus(t *testing.T) {
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2, Annotations: map[string]string{deploymentRevisionAnnotation: "4"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 2, AvailableReplicas: 3},
+	}
+	status := deploymentRolloutStatus(deployment)
+	assert.Equal(t, int64(4), status.Revision)
+	assert.False(t, status.Complete)
+	assert.Equal(t, "2/3 个副本已更新", status.Message)
+
+	deployment.Status.UpdatedReplicas = 3
+	assert.Equal(t, "1 个旧副本等待终止", deploymentRolloutStatus(deployment).Message)
+
+	deployment.Status.Replicas = 3
+	status = deploymentRolloutStatus(deployment)
+	assert.True(t, status.Complete)
+
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: deploymentTimedOutReason},
+	}
+	deployment.Status.UpdatedReplicas = 1
+	status = deploymentRolloutStatus(deployment)
+	assert.True(t, status.Stuck)
+	assert.False(t, status.Complete)
+	assert.Len(t, status.Conditions, 1)
+
+	deployment.Generation = 3
+	assert.False(t, deploymentRolloutStatus(deployment).Stuck)
+}
+
+func TestRollbackTarget(t *testing.T) {
+	newReplicaSet := func(name, revision string) appsv1.ReplicaSet {
+		return appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{deploymentRevisionAnnotation: revision}}}
+	}
+	// 回滚后旧版本会使用新的版本号, 版本号不连续
+	replicaSets := []appsv1.ReplicaSet{newReplicaSet("web-a", "1"), newReplicaSet("web-c", "3"), newReplicaSet("web-b", "4")}
+
+	assert.Equal(t, "web-c", rollbackTarget(replicaSets, "4", 0).Name)
+	assert.Equal(t, "web-a", rollbackTarget(replicaSets, "4", 1).Name)
+	assert.Nil(t, rollbackTarget(replicaSets, "4", 2))
+	assert.Nil(t, rollbackTarget(replicaSets, "1", 0))
+}
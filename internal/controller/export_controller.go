@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrUnsupportedExportKind 不支持导出的资源类型
+var ErrUnsupportedExportKind = errors.New("unsupported export kind")
+
+type exportKind struct {
+	resource schema.GroupVersionResource
+	// 命名空间为空时使用的集群级资源, 如 rolebinding 对应 ClusterRoleBinding
+	clusterResource *schema.GroupVersionResource
+	namespaced      bool
+}
+
+// 支持导出的资源, 名称与路由一致
+var exportKinds = map[string]exportKind{
+	"pod":         {resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, namespaced: true},
+	"configmap":   {resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, namespaced: true},
+	"secret":      {resource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, namespaced: true},
+	"svc":         {resource: schema.GroupVersionResource{Version: "v1", Resource: "services"}, namespaced: true},
+	"sa":          {resource: schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, namespaced: true},
+	"pvc":         {resource: schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, namespaced: true},
+	"deployment":  {resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, namespaced: true},
+	"daemonset":   {resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, namespaced: true},
+	"statefulset": {resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, namespaced: true},
+	"job":         {resource: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, namespaced: true},
+	"cronjob":     {resource: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, namespaced: true},
+	"ingress":     {resource: schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, namespaced: true},
+	"ingroute":    {resource: schema.GroupVersionResource{Group: "traefik.io", Version: "v1alpha1", Resource: "ingressroutes"}, namespaced: true},
+	"role": {
+		resource:        schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"},
+		clusterResource: &schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+		namespaced:      true,
+	},
+	"rolebinding": {
+		resource:        schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"},
+		clusterResource: &schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"},
+		namespaced:      true,
+	},
+	"node": {resource: schema.GroupVersionResource{Version: "v1", Resource: "nodes"}},
+	"pv":   {resource: schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumes"}},
+	"sc":   {resource: schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"}},
+}
+
+// 导出命名空间时的资源顺序, 依赖的资源在前
+var namespaceExportKinds = []string{"sa", "role", "rolebinding", "configmap", "secret", "pvc", "svc",
+	"deployment", "statefulset", "daemonset", "cronjob", "job", "pod", "ingress", "ingroute"}
+
+// 集群自动创建的对象, 导出命名空间时跳过
+var generatedObjects = map[string]string{
+	"configmap": "kube-root-ca.crt",
+	"sa":        "default",
+}
+
+// 由服务端填充的 metadata 字段
+var serverMetadataFields = []string{"managedFields", "uid", "resourceVersion", "generation", "creationTimestamp",
+	"selfLink", "deletionTimestamp", "deletionGracePeriodSeconds", "ownerReferences"}
+
+// 由服务端或控制器写入的注解
+var serverAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+	"pv.kubernetes.io/bind-completed",
+	"pv.kubernetes.io/bound-by-controller",
+	"pv.kubernetes.io/provisioned-by",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/selected-node",
+}
+
+// ExportController 导出去掉服务端字段后可以重新 apply 的YAML
+type ExportController struct {
+	Dynamic dynamic.Interface
+}
+
+func NewExportController(client dynamic.Interface) *ExportController {
+	return &ExportController{
+		Dynamic: client,
+	}
+}
+
+// GetYAML 导出单个对象, 集群级资源的 namespace 为空
+func (e *ExportController) GetYAML(ctx context.Context, kind, namespace, name string) ([]byte, error) {
+	resource, err := e.resource(kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	cleanObject(obj)
+	return yaml.Marshal(obj.Object)
+}
+
+// ExportNamespace 把命名空间中有权限的资源写入zip, 文件名为 <kind>/<name>.yaml
+// 跳过由控制器创建的对象(如deployment创建的pod)和集群自动创建的对象, 集群中没有的CRD和没有权限的资源类型也会跳过
+func (e *ExportController) ExportNamespace(ctx context.Context, namespace string, w io.Writer, allowed func(kind string) bool) error {
+	zw := zip.NewWriter(w)
+	for _, kind := range namespaceExportKinds {
+		if !allowed(kind) {
+			continue
+		}
+		list, err := e.Dynamic.Resource(exportKinds[kind].resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err) {
+				continue
+			}
+			return err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if skipExport(kind, obj) {
+				continue
+			}
+			cleanObject(obj)
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			f, err := zw.Create(path.Join(kind, obj.GetName()+".yaml"))
+			if err != nil {
+				return err
+			}
+			if _, err = f.Write(data); err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
+
+func (e *ExportController) resource(kind, namespace string) (dynamic.ResourceInterface, error) {
+	spec, ok := exportKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExportKind, kind)
+	}
+	switch {
+	case !spec.namespaced:
+		return e.Dynamic.Resource(spec.resource), nil
+	case namespace == "" && spec.clusterResource != nil:
+		return e.Dynamic.Resource(*spec.clusterResource), nil
+	default:
+		return e.Dynamic.Resource(spec.resource).Namespace(namespace), nil
+	}
+}
+
+func skipExport(kind string, obj *unstructured.Unstructured) bool {
+	if metav1.GetControllerOf(obj) != nil || generatedObjects[kind] == obj.GetName() {
+		return true
+	}
+	secretType, _, _ := unstructured.NestedString(obj.Object, "type")
+	return kind == "secret" && secretType == "kubernetes.io/service-account-token"
+}
+
+// cleanObject 去掉服务端填充的字段, 导出的对象可以直接 apply 到其他命名空间或集群
+func cleanObject(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range serverMetadataFields {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		for _, key := range serverAnnotations {
+			delete(annotations, key)
+		}
+		obj.SetAnnotations(annotations)
+	}
+
+	switch obj.GetKind() {
+	case "Pod":
+		// 调度结果
+		unstructured.RemoveNestedField(obj.Object, "spec", "nodeName")
+	case "Service":
+		if clusterIP, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP"); clusterIP != "None" {
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+		}
+	case "PersistentVolumeClaim":
+		unstructured.RemoveNestedField(obj.Object, "spec", "volumeName")
+	case "PersistentVolume":
+		for _, field := range []string{"uid", "resourceVersion"} {
+			unstructured.RemoveNestedField(obj.Object, "spec", "claimRef", field)
+		}
+	case "Job":
+		// 没有设置 manualSelector 时 selector 和模板标签由apiserver生成
+		if manual, _, _ := unstructured.NestedBool(obj.Object, "spec", "manualSelector"); !manual {
+			unstructured.RemoveNestedField(obj.Object, "spec", "selector")
+			for _, label := range jobGeneratedLabels {
+				unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "labels", label)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+func newExportClient() *dynamicfake.FakeDynamicClient {
+	deploy := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web", Namespace: "app", UID: "uid-1", ResourceVersion: "42", Generation: 3,
+			Annotations:   map[string]string{"deployment.kubernetes.io/revision": "3", "team": "a"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "k8sadmin"}},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 2},
+	}
+	svc := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "app"},
+		Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.1", ClusterIPs: []string{"10.0.0.1"}},
+	}
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "app", OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-1"}}, appsv1.SchemeGroupVersion.WithKind("ReplicaSet")),
+		}},
+	}
+	rootCA := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: "app"},
+	}
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "app"},
+	}
+	listKinds := map[schema.GroupVersionResource]string{exportKinds["ingroute"].resource: "IngressRouteList"}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, listKinds, deploy, svc, pod, rootCA, secret)
+}
+
+func TestExportController_GetYAML(t *testing.T) {
+	e := NewExportController(newExportClient())
+	data, err := e.GetYAML(context.Background(), "deployment", "app", "web")
+	require.NoError(t, err)
+
+	obj := map[string]any{}
+	require.NoError(t, yaml.Unmarshal(data, &obj))
+	assert.NotContains(t, obj, "status")
+	metadata := obj["metadata"].(map[string]any)
+	for _, field := range []string{"uid", "resourceVersion", "generation", "managedFields", "creationTimestamp"} {
+		assert.NotContains(t, metadata, field)
+	}
+	assert.Equal(t, map[string]any{"team": "a"}, metadata["annotations"])
+
+	data, err = e.GetYAML(context.Background(), "svc", "app", "web")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "clusterIP")
+
+	_, err = e.GetYAML(context.Background(), "unknown", "app", "web")
+	assert.ErrorIs(t, err, ErrUnsupportedExportKind)
+}
+
+func TestExportController_ExportNamespace(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := NewExportController(newExportClient()).ExportNamespace(context.Background(), "app", buf, func(kind string) bool {
+		return kind != "secret"
+	})
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	// 跳过控制器创建的pod、自动创建的configmap和没有权限的secret
+	assert.Equal(t, []string{"deployment/web.yaml", "svc/web.yaml"}, names)
+}
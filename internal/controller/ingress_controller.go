@@ -3,7 +3,10 @@ package controller
 import (
 	"context"
 	"fmt"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
+	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/internal/types/ingress"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,29 +14,21 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"strings"
 
-	"sync"
-
 	networkingv1 "k8s.io/api/networking/v1"
 )
 
-var (
-	ingressInstance       *IngressController
-	ingressControllerOnce sync.Once
-)
-
 type IngressController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          *global.Server
 }
 
-func NewIngressController() *IngressController {
-	ingressControllerOnce.Do(func() {
-		ingressInstance = &IngressController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-			CONF:          global.CONF,
-		}
-	})
-	return ingressInstance
+func NewIngressController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *IngressController {
+	return &IngressController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+		CONF:          global.CONF,
+	}
 }
 
 func (s *IngressController) CreateOrUpdateIngress(ctx context.Context, reqParam *ingress.CreateOrUpdateIngressRequest) error {
@@ -89,27 +84,36 @@ func (s *IngressController) GetIngressDetail(ctx context.Context, namespace stri
 	return ingressRes, nil
 }
 
-func (s *IngressController) GetIngressList(ctx context.Context, namespace string) ([]*ingress.IngressRes, error) {
-	list, err := s.KubeConfigSet.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+func (s *IngressController) GetIngressList(ctx context.Context, namespace string, query *types.ListQuery) ([]*ingress.IngressRes, *types.ListMeta, error) {
+	items, err := listIngresses(ctx, s.KubeConfigSet, s.Cache, namespace)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	ingressList := make([]*ingress.IngressRes, 0)
-	for _, item := range list.Items {
-		hosts := make([]string, 0)
-		for _, rule := range item.Spec.Rules {
-			hosts = append(hosts, rule.Host)
-		}
-		ingressList = append(ingressList, &ingress.IngressRes{
-			Name:      item.Name,
-			Namespace: item.Namespace,
-			Hosts:     strings.Join(hosts, ","),
-			Age:       item.CreationTimestamp.Unix(),
-		})
+	for i := range items {
+		ingressList = append(ingressList, ingressListItem(&items[i]))
+	}
+	return ingressList, listMeta, nil
+}
+
+// ingressListItem 列表与watch接口共用的转换
+func ingressListItem(item *networkingv1.Ingress) *ingress.IngressRes {
+	hosts := make([]string, 0)
+	for _, rule := range item.Spec.Rules {
+		hosts = append(hosts, rule.Host)
+	}
+	return &ingress.IngressRes{
+		Name:      item.Name,
+		Namespace: item.Namespace,
+		Hosts:     strings.Join(hosts, ","),
+		Age:       item.CreationTimestamp.Unix(),
 	}
-	return ingressList, nil
 }
 
 func (s *IngressController) DetIngress(ctx context.Context, namespace string, name string) error {
@@ -117,7 +121,7 @@ func (s *IngressController) DetIngress(ctx context.Context, namespace string, na
 }
 
 func (s *IngressController) CreateOrUpdateRoute(ctx context.Context, reqParam *ingress.IngressRouteRequest) error {
-	url := fmt.Sprint("apis/treafix.io/v1alpha1/namespaces/%s/ingressroutes", reqParam.Namespace)
+	url := fmt.Sprintf("apis/treafix.io/v1alpha1/namespaces/%s/ingressroutes", reqParam.Namespace)
 	ingressRoute := ingress.IngressRoute{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "traefix.io/v1alpha1",
@@ -185,24 +189,24 @@ func (s *IngressController) GetIngRouteDetail(ctx context.Context, namesapce, na
 	return ingRouteRes, nil
 }
 
-func (s *IngressController) GetIngRouteList(ctx context.Context, namespace string, keyword string) ([]*ingress.IngressRouteRes, error) {
+func (s *IngressController) GetIngRouteList(ctx context.Context, namespace string, query *types.ListQuery) ([]*ingress.IngressRouteRes, *types.ListMeta, error) {
 	ingressList := make([]*ingress.IngressRouteRes, 0)
 	url := fmt.Sprintf("/apis/traefix.io/v1alpha1/namespace/%s/ingressroutes", namespace)
 	raw, err := s.KubeConfigSet.RESTClient().Get().AbsPath(url).DoRaw(ctx)
 	if err != nil {
-		return ingressList, err
+		return ingressList, nil, err
 	}
 	var ingRouteList ingress.IngressRouteList
 	err = json.Unmarshal(raw, &ingRouteList)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(ingRouteList.Items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	for _, item := range ingRouteList.Items {
-		if !strings.Contains(item.Metadata.Name, keyword) {
-			continue
-		}
-
+	for _, item := range items {
 		ingressList = append(ingressList, &ingress.IngressRouteRes{
 			Name:      item.Metadata.Name,
 			Namespace: item.Metadata.Namespace,
@@ -210,25 +214,29 @@ func (s *IngressController) GetIngRouteList(ctx context.Context, namespace strin
 		})
 	}
 
-	return ingressList, nil
+	return ingressList, listMeta, nil
 }
 
-func (s *IngressController) GetIngRouteMiddlewareList(ctx context.Context, namespace string) ([]string, error) {
+func (s *IngressController) GetIngRouteMiddlewareList(ctx context.Context, namespace string, query *types.ListQuery) ([]string, *types.ListMeta, error) {
 	url := fmt.Sprintf("/apis/traefix.io/v1alpha1/namespace/%s/middleware", namespace)
 	raw, err := s.KubeConfigSet.RESTClient().Get().AbsPath(url).DoRaw(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var middlewareList ingress.MiddlewareList
 	err = json.Unmarshal(raw, &middlewareList)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(middlewareList.Items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 	mwList := make([]string, 0)
-	for _, item := range middlewareList.Items {
+	for _, item := range items {
 		mwList = append(mwList, item.Metadata.Name)
 	}
-	return mwList, nil
+	return mwList, listMeta, nil
 }
 
 func (s *IngressController) DeleteIngRoute(ctx context.Context, namespace string, name string) error {
@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/pod"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+// job 创建时由 apiserver 自动添加到pod模板的标签, 详情中不返回
+var jobGeneratedLabels = []string{
+	batchv1.ControllerUidLabel,
+	batchv1.JobNameLabel,
+	"controller-uid",
+	"job-name",
+}
+
+type JobController struct {
+	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
+	CONF          global.Server
+}
+
+func NewJobController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *JobController {
+	return &JobController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
+}
+
+func (j *JobController) CreateOrUpdateJob(ctx context.Context, reqParam *types.JobRequest) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      reqParam.Base.Name,
+			Namespace: reqParam.Base.Namespace,
+			Labels:    maputils.ToMap(reqParam.Base.Labels),
+		},
+		Spec: jobSpecReq2K8s(&reqParam.Base.JobSpec, reqParam.Template),
+	}
+
+	jobApi := j.KubeConfigSet.BatchV1().Jobs(job.Namespace)
+	jobK8s, err := jobApi.Get(ctx, job.Name, metav1.GetOptions{})
+	if err == nil {
+		// completions、backoffLimit、template 创建后不能修改
+		jobK8s.Labels = job.Labels
+		jobK8s.Spec.Parallelism = job.Spec.Parallelism
+		jobK8s.Spec.ActiveDeadlineSeconds = job.Spec.ActiveDeadlineSeconds
+		jobK8s.Spec.TTLSecondsAfterFinished = job.Spec.TTLSecondsAfterFinished
+		_, err = jobApi.Update(ctx, jobK8s, metav1.UpdateOptions{})
+	} else {
+		err = applyImagePullSecrets(ctx, j.KubeConfigSet, job.Namespace, reqParam.Template.ImagePullSecrets, &job.Spec.Template.Spec, nil)
+		if err != nil {
+			return err
+		}
+		_, err = jobApi.Create(ctx, job, metav1.CreateOptions{})
+	}
+	return err
+}
+
+func (j *JobController) GetJobDetail(ctx context.Context, namespace string, name string) (*types.JobResponse, error) {
+	jobK8s, err := j.KubeConfigSet.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	jobSpec, podRes := jobSpecK8s2Req(&jobK8s.Spec)
+	return &types.JobResponse{
+		Base: &types.JobBase{
+			Name:      jobK8s.Name,
+			Namespace: jobK8s.Namespace,
+			Labels:    maputils.ToList(jobK8s.Labels),
+			JobSpec:   jobSpec,
+		},
+		Template: podRes,
+	}, nil
+}
+
+func (j *JobController) GetJobList(ctx context.Context, namespace string, query *types.ListQuery) ([]*types.JobRes, *types.ListMeta, error) {
+	jobList := make([]*types.JobRes, 0)
+	items, err := listJobs(ctx, j.KubeConfigSet, j.Cache, namespace)
+	if err != nil {
+		return jobList, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return jobList, nil, err
+	}
+	for i := range items {
+		jobList = append(jobList, jobListItem(&items[i]))
+	}
+	return jobList, listMeta, nil
+}
+
+// GetJobPods job 中每个pod的执行结果
+func (j *JobController) GetJobPods(ctx context.Context, namespace string, name string) ([]*types.JobPodRes, error) {
+	jobK8s, err := j.KubeConfigSet.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podsByJob, err := jobPodsByOwner(ctx, j.KubeConfigSet, j.Cache, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return jobPodList(podsByJob[jobK8s.UID]), nil
+}
+
+func (j *JobController) DeleteJob(ctx context.Context, namespace, name string) error {
+	// 默认的删除策略会保留job创建的pod
+	propagation := metav1.DeletePropagationBackground
+	return j.KubeConfigSet.BatchV1().Jobs(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+}
+
+// jobSpecReq2K8s job 和 cronjob 共用, restartPolicy 只能为 Never 或 OnFailure
+func jobSpecReq2K8s(spec *types.JobSpec, template *types.Pod) batchv1.JobSpec {
+	podK8sConvert := pod.Req2K8sConvert{}
+	podK8s := podK8sConvert.PodReq2K8s(template)
+	if podK8s.Spec.RestartPolicy != corev1.RestartPolicyOnFailure {
+		podK8s.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	jobSpec := batchv1.JobSpec{
+		BackoffLimit:            spec.BackoffLimit,
+		ActiveDeadlineSeconds:   spec.ActiveDeadlineSeconds,
+		TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: podK8s.Labels,
+			},
+			Spec: podK8s.Spec,
+		},
+	}
+	if spec.Completions > 0 {
+		jobSpec.Completions = &spec.Completions
+	}
+	if spec.Parallelism > 0 {
+		jobSpec.Parallelism = &spec.Parallelism
+	}
+	return jobSpec
+}
+
+func jobSpecK8s2Req(spec *batchv1.JobSpec) (types.JobSpec, *types.Pod) {
+	jobSpec := types.JobSpec{
+		BackoffLimit:            spec.BackoffLimit,
+		ActiveDeadlineSeconds:   spec.ActiveDeadlineSeconds,
+		TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
+	}
+	if spec.Completions != nil {
+		jobSpec.Completions = *spec.Completions
+	}
+	if spec.Parallelism != nil {
+		jobSpec.Parallelism = *spec.Parallelism
+	}
+
+	podLabels := make(map[string]string)
+	for k, v := range spec.Template.Labels {
+		podLabels[k] = v
+	}
+	for _, key := range jobGeneratedLabels {
+		delete(podLabels, key)
+	}
+	podResConvert := pod.K8s2ReqConvert{}
+	podRes := podResConvert.PodK8s2Req(corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: podLabels,
+		},
+		Spec: spec.Template.Spec,
+	})
+	return jobSpec, &podRes
+}
+
+func jobListItem(item *batchv1.Job) *types.JobRes {
+	res := &types.JobRes{
+		Name:      item.Name,
+		Namespace: item.Namespace,
+		Status:    jobStatus(item),
+		Active:    item.Status.Active,
+		Succeeded: item.Status.Succeeded,
+		Failed:    item.Status.Failed,
+		Manual:    item.Annotations[cronJobInstantiateAnnotation] == cronJobInstantiateManual,
+		Age:       item.CreationTimestamp.Unix(),
+	}
+	if item.Spec.Completions != nil {
+		res.Completions = *item.Spec.Completions
+	}
+	if owner := metav1.GetControllerOf(item); owner != nil && owner.Kind == "CronJob" {
+		res.CronJob = owner.Name
+	}
+	if item.Status.StartTime != nil {
+		res.StartTime = item.Status.StartTime.Unix()
+	}
+	if item.Status.CompletionTime != nil {
+		res.CompletionTime = item.Status.CompletionTime.Unix()
+	}
+	return res
+}
+
+func jobStatus(item *batchv1.Job) string {
+	for _, condition := range item.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return types.JobStatusComplete
+		case batchv1.JobFailed:
+			return types.JobStatusFailed
+		}
+	}
+	if item.Spec.Suspend != nil && *item.Spec.Suspend {
+		return types.JobStatusSuspended
+	}
+	return types.JobStatusRunning
+}
+
+// jobPodsByOwner 按所属job分组
+func jobPodsByOwner(ctx context.Context, kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache, namespace string) (map[k8stypes.UID][]corev1.Pod, error) {
+	pods, err := listPods(ctx, kubeConfigSet, cache, namespace)
+	if err != nil {
+		return nil, err
+	}
+	podsByJob := make(map[k8stypes.UID][]corev1.Pod)
+	for _, item := range pods {
+		if owner := metav1.GetControllerOf(&item); owner != nil && owner.Kind == "Job" {
+			podsByJob[owner.UID] = append(podsByJob[owner.UID], item)
+		}
+	}
+	return podsByJob, nil
+}
+
+func jobPodList(pods []corev1.Pod) []*types.JobPodRes {
+	podList := make([]*types.JobPodRes, 0, len(pods))
+	for i := range pods {
+		podList = append(podList, jobPodItem(&pods[i]))
+	}
+	return podList
+}
+
+// jobPodItem 取退出码非0的容器作为pod的结果, 都为0时取第一个已退出的容器
+func jobPodItem(k8sPod *corev1.Pod) *types.JobPodRes {
+	res := &types.JobPodRes{
+		Name:  k8sPod.Name,
+		Phase: string(k8sPod.Status.Phase),
+		Node:  k8sPod.Spec.NodeName,
+		Age:   k8sPod.CreationTimestamp.Unix(),
+	}
+	var result *corev1.ContainerStateTerminated
+	for _, status := range k8sPod.Status.ContainerStatuses {
+		terminated := status.State.Terminated
+		if terminated == nil {
+			continue
+		}
+		if result == nil || (result.ExitCode == 0 && terminated.ExitCode != 0) {
+			result = terminated
+		}
+	}
+	if result != nil {
+		res.ExitCode = result.ExitCode
+		res.Reason = result.Reason
+	} else if k8sPod.Status.Reason != "" {
+		// 如被驱逐或超过 activeDeadlineSeconds
+		res.Reason = k8sPod.Status.Reason
+	}
+	return res
+}
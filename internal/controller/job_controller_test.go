@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+func TestCronJobListItem(t *testing.T) {
+	newJob := func(conditionType batchv1.JobConditionType) batchv1.Job {
+		job := batchv1.Job{}
+		if conditionType != "" {
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobSuspended, Status: corev1.ConditionFalse},
+				{Type: conditionType, Status: corev1.ConditionTrue},
+			}
+		}
+		return job
+	}
+	suspend := true
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "db"},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *", Suspend: &suspend},
+		Status:     batchv1.CronJobStatus{Active: []corev1.ObjectReference{{Name: "backup-1"}}},
+	}
+	jobs := []batchv1.Job{newJob(batchv1.JobComplete), newJob(batchv1.JobComplete), newJob(batchv1.JobFailed), newJob("")}
+
+	res := cronJobListItem(cronJob, jobs)
+	assert.True(t, res.Suspend)
+	assert.Equal(t, 1, res.Active)
+	assert.Equal(t, 2, res.Succeeded)
+	assert.Equal(t, 1, res.Failed)
+	assert.Equal(t, types.JobStatusRunning, jobStatus(&jobs[3]))
+}
+
+func TestJobPodItem(t *testing.T) {
+	k8sPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-1-abcde"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "sidecar", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}}},
+				{Name: "backup", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}}},
+			},
+		},
+	}
+	res := jobPodItem(k8sPod)
+	assert.Equal(t, int32(137), res.ExitCode)
+	assert.Equal(t, "OOMKilled", res.Reason)
+
+	// 未启动容器就被终止
+	k8sPod.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "DeadlineExceeded"}
+	res = jobPodItem(k8sPod)
+	assert.Equal(t, int32(0), res.ExitCode)
+	assert.Equal(t, "DeadlineExceeded", res.Reason)
+}
+
+func TestJobSpecReq2K8s(t *testing.T) {
+	template := &types.Pod{Base: types.Base{Name: "backup", Labels: []types.ListMapItem{{Key: "app", Value: "backup"}}, RestartPolicy: "Always"}}
+	spec := jobSpecReq2K8s(&types.JobSpec{Completions: 3}, template)
+	assert.Equal(t, corev1.RestartPolicyNever, spec.Template.Spec.RestartPolicy)
+	assert.Equal(t, int32(3), *spec.Completions)
+	assert.Nil(t, spec.Parallelism)
+
+	spec.Template.Labels[batchv1.JobNameLabel] = "backup"
+	jobSpec, podRes := jobSpecK8s2Req(&spec)
+	assert.Equal(t, int32(3), jobSpec.Completions)
+	assert.Equal(t, []types.ListMapItem{{Key: "app", Value: "backup"}}, podRes.Base.Labels)
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/prometheus"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+const (
+	metricsDefaultRange = time.Hour
+	metricsMinStep      = 15 * time.Second
+	// 默认采样点数
+	metricsDefaultPoints = 120
+	// prometheus 单次查询最多返回11000个点
+	metricsMaxPoints = 11000
+)
+
+var (
+	// ErrMetricsDisabled 没有配置prometheus
+	ErrMetricsDisabled = prometheus.ErrDisabled
+	// ErrInvalidMetricsRange 时间范围或采样间隔不合法
+	ErrInvalidMetricsRange = errors.New("invalid metrics range")
+)
+
+// 返回的指标及单位, 按该顺序返回
+var metricsUnits = []struct {
+	name string
+	unit string
+}{
+	{prometheus.MetricCpu, "core"},
+	{prometheus.MetricMemory, "byte"},
+	{prometheus.MetricNetworkReceive, "byte/s"},
+	{prometheus.MetricNetworkTransmit, "byte/s"},
+}
+
+// MetricsController 通过prometheus查询 cAdvisor 指标, 按 Cluster 选择 system.prometheus 中的配置
+type MetricsController struct {
+	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
+	CONF          *global.Server
+	// 当前集群名称
+	Cluster string
+}
+
+func NewMetricsController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *MetricsController {
+	return &MetricsController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+		CONF:          global.CONF,
+	}
+}
+
+func (m *MetricsController) GetPodMetrics(ctx context.Context, namespace, name string, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+	return m.queryMetrics(ctx, prometheus.PodSelector(namespace, name), req)
+}
+
+// GetDeploymentMetrics deployment 所有pod的用量之和, 按 deployment 拥有的 replicaset 精确匹配pod
+func (m *MetricsController) GetDeploymentMetrics(ctx context.Context, namespace, name string, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+	deploymentController := NewDeploymentController(m.KubeConfigSet, m.Cache)
+	deploymentK8s, err := m.KubeConfigSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	replicaSets, err := deploymentController.listDeploymentReplicaSets(ctx, deploymentK8s)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(replicaSets))
+	for _, rs := range replicaSets {
+		names = append(names, rs.Name)
+	}
+	return m.queryMetrics(ctx, prometheus.DeploymentSelector(namespace, names), req)
+}
+
+func (m *MetricsController) GetNodeMetrics(ctx context.Context, name string, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+	return m.queryMetrics(ctx, prometheus.NodeSelector(name), req)
+}
+
+func (m *MetricsController) GetNamespaceMetrics(ctx context.Context, namespace string, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+	return m.queryMetrics(ctx, prometheus.NamespaceSelector(namespace), req)
+}
+
+func (m *MetricsController) queryMetrics(ctx context.Context, selector prometheus.Selector, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+	start, end, step, err := metricsRange(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if m.CONF == nil {
+		return nil, ErrMetricsDisabled
+	}
+	conf := prometheus.ClusterConfig(m.CONF.System.Prometheus, m.Cluster)
+	client, err := prometheus.NewClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := selector.WithCluster(conf.ClusterLabel, m.Cluster).Queries()
+	seriesList := make([]*types.MetricsSeries, len(metricsUnits))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, metric := range metricsUnits {
+		seriesList[i] = &types.MetricsSeries{Name: metric.name, Unit: metric.unit, Points: make([]types.MetricsPoint, 0)}
+		if selector.Empty() {
+			continue
+		}
+		query := queries[metric.name]
+		series := seriesList[i]
+		g.Go(func() error {
+			result, err := client.QueryRange(gctx, query, start, end, step)
+			if err != nil {
+				return err
+			}
+			// 查询语句使用 sum 聚合, 最多只有一条序列
+			if len(result) > 0 {
+				for _, point := range result[0].Points {
+					// NaN(如除零) 无法编码为json, 跳过
+					if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) {
+						continue
+					}
+					series.Points = append(series.Points, types.MetricsPoint{Timestamp: point.Timestamp, Value: point.Value})
+				}
+			}
+			return nil
+		})
+	}
+	if err = g.Wait(); err != nil {
+		return nil, err
+	}
+	return seriesList, nil
+}
+
+// metricsRange 补全默认的时间范围和采样间隔
+func metricsRange(req *types.MetricsRequest, now time.Time) (time.Time, time.Time, time.Duration, error) {
+	end := now
+	if req.End > 0 {
+		end = time.Unix(req.End, 0)
+	}
+	start := end.Add(-metricsDefaultRange)
+	if req.Start > 0 {
+		start = time.Unix(req.Start, 0)
+	}
+	if !start.Before(end) {
+		return start, end, 0, fmt.Errorf("%w: start must be before end", ErrInvalidMetricsRange)
+	}
+
+	step := time.Duration(req.Step) * time.Second
+	if step == 0 {
+		step = end.Sub(start) / metricsDefaultPoints
+	}
+	if step < metricsMinStep {
+		step = metricsMinStep
+	}
+	step = step.Truncate(time.Second)
+	if end.Sub(start)/step > metricsMaxPoints {
+		return start, end, step, fmt.Errorf("%w: too many points, increase step", ErrInvalidMetricsRange)
+	}
+	return start, end, step, nil
+}
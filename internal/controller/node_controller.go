@@ -2,34 +2,36 @@ package controller
 
 import (
 	"context"
+	"time"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/node"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/client-go/kubernetes"
-	"strings"
-	"sync"
 )
 
-var (
-	nodeInstance       *NodeController
-	nodeControllerOnce sync.Once
-)
+// ErrNodeDraining 节点已经有正在执行的驱逐任务
+var ErrNodeDraining = node.ErrDrainRunning
 
 type NodeController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          global.Server
 }
 
-func NewNodeController() *NodeController {
-	nodeControllerOnce.Do(func() {
-		nodeInstance = &NodeController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-		}
-	})
-	return nodeInstance
+func NewNodeController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *NodeController {
+	return &NodeController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
 }
 
 func (n *NodeController) GetNodeDetail(ctx context.Context, reqparm *types.NodeDetailRequest) (*types.Node, error) {
@@ -37,33 +39,77 @@ func (n *NodeController) GetNodeDetail(ctx context.Context, reqparm *types.NodeD
 	if err != nil {
 		return nil, err
 	}
+	podsByNode, err := n.podsByNode(ctx, nodeK8s.Name)
+	if err != nil {
+		return nil, err
+	}
 
 	// node 类型转换
 	nodeConvret := &node.NodeK8s2Res{}
 	detail := nodeConvret.GetNodeDetail(nodeK8s)
+	detail.Resources = nodeConvret.GetNodeResources(nodeK8s, podsByNode[nodeK8s.Name])
+	detail.Pods = nodeConvret.GetNodePods(podsByNode[nodeK8s.Name])
 	return detail, err
 }
 
-func (n *NodeController) GetNodeList(ctx context.Context, reqParam *types.NodeListRequest) ([]*types.Node, error) {
-	list, err := n.KubeConfigSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+func (n *NodeController) GetNodeList(ctx context.Context, query *types.ListQuery) ([]*types.Node, *types.ListMeta, error) {
+	items, err := listNodes(ctx, n.KubeConfigSet, n.Cache)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodeNames := make([]string, 0, len(items))
+	for _, item := range items {
+		nodeNames = append(nodeNames, item.Name)
+	}
+	podsByNode, err := n.podsByNode(ctx, nodeNames...)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	nodeConvret := &node.NodeK8s2Res{}
 	nodeResList := make([]*types.Node, 0)
-	for _, item := range list.Items {
+	for _, item := range items {
 		nodeRes := nodeConvret.GetNodeResItem(&item)
-		if reqParam.KeyWord != "" {
-			if strings.Contains(item.Name, reqParam.KeyWord) {
-				nodeResList = append(nodeResList, nodeRes)
+		nodeRes.Resources = nodeConvret.GetNodeResources(&item, podsByNode[item.Name])
+		nodeResList = append(nodeResList, nodeRes)
+	}
+	return nodeResList, listMeta, nil
+}
+
+// podsByNode 调度到指定节点上的pod, 按节点分组
+// 没有informer缓存时(如模拟用户身份)按节点使用 spec.nodeName 字段选择器查询, 避免每次都列出集群中的所有pod
+func (n *NodeController) podsByNode(ctx context.Context, nodeNames ...string) (map[string][]corev1.Pod, error) {
+	podsByNode := make(map[string][]corev1.Pod, len(nodeNames))
+	if lister, ok := n.Cache.Pods(); ok {
+		pods, err := fromLister(lister.List(labels.Everything()))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range nodeNames {
+			podsByNode[name] = nil
+		}
+		for _, item := range pods {
+			if _, ok := podsByNode[item.Spec.NodeName]; ok {
+				podsByNode[item.Spec.NodeName] = append(podsByNode[item.Spec.NodeName], item)
 			}
-		} else {
-			nodeResList = append(nodeResList, nodeRes)
 		}
+		return podsByNode, nil
+	}
 
+	for _, name := range nodeNames {
+		list, err := n.KubeConfigSet.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", name).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		podsByNode[name] = list.Items
 	}
-	return nodeResList, err
+	return podsByNode, nil
 }
 
 func (n *NodeController) UpdateNodeLabel(ctx context.Context, reqParam *types.UpdatedLabelRequest) error {
@@ -107,3 +153,46 @@ func (n *NodeController) UpdateNodeTaint(ctx context.Context, reqParm *types.Upd
 	)
 	return err
 }
+
+// CordonNode 设置节点是否可调度, 不影响已经运行的pod
+func (n *NodeController) CordonNode(ctx context.Context, name string, unschedulable bool) error {
+	return node.Cordon(ctx, n.KubeConfigSet, name, unschedulable)
+}
+
+// DrainNode 在后台驱逐节点上的pod, 通过 GetDrainTask 查询进度
+func (n *NodeController) DrainNode(ctx context.Context, clusterName, name string, reqParam *types.NodeDrainRequest) (*types.NodeDrainTask, error) {
+	// 节点不存在时不创建任务
+	if _, err := n.KubeConfigSet.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+	task, err := node.StartDrain(n.KubeConfigSet, clusterName, name, node.DrainOptions{
+		DeleteEmptyDirData: reqParam.DeleteEmptyDirData,
+		Force:              reqParam.Force,
+		GracePeriodSeconds: reqParam.GracePeriodSeconds,
+		Timeout:            time.Duration(reqParam.TimeoutSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task.Snapshot(), nil
+}
+
+// GetDrainTask 节点最近一次驱逐任务的进度
+func (n *NodeController) GetDrainTask(clusterName, name string) (*types.NodeDrainTask, bool) {
+	task, ok := node.GetDrainTask(clusterName, name)
+	if !ok {
+		return nil, false
+	}
+	return task.Snapshot(), true
+}
+
+// CancelDrain 取消正在执行的驱逐任务
+func (n *NodeController) CancelDrain(clusterName, name string) (*types.NodeDrainTask, bool) {
+	task, ok := node.GetDrainTask(clusterName, name)
+	if !ok {
+		return nil, false
+	}
+	task.Cancel()
+	<-task.Done()
+	return task.Snapshot(), true
+}
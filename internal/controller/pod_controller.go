@@ -2,156 +2,183 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/pod"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
+	"io"
 	corev1 "k8s.io/api/core/v1"
 	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
-	"strings"
-	"sync"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
 	"time"
 )
 
-var (
-	podInstance       *PodController
-	podControllerOnce sync.Once
-)
+// 重建pod时, 在 terminationGracePeriodSeconds 之外额外等待删除完成的时间
+const podDeleteTimeout = 30 * time.Second
 
 type PodController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          global.Server
 }
 
-func NewPodController() *PodController {
-	podControllerOnce.Do(func() {
-		podInstance = &PodController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-		}
-	})
-	return podInstance
+func NewPodController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *PodController {
+	return &PodController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
 }
 
-func (p *PodController) CreateOrUpdatePod(ctx context.Context, podReq *types.Pod) (string, error) {
+// ErrPodRecreateRequired 变更包含不可原地更新的字段, 需要确认重建
+var ErrPodRecreateRequired = errors.New("pod changes require recreate")
+
+// CreateOrUpdatePod pod不存在时创建; 存在时只变更可原地更新的字段, 包含不可原地更新的字段时需要 recreate=true 才会删除重建
+// 返回结果中包含所有变化的字段
+func (p *PodController) CreateOrUpdatePod(ctx context.Context, podReq *types.Pod, recreate bool) (*types.PodUpdateResult, error) {
 	// 把请求的pod结构数据转变为 k8s核心资源结构
 	req2K8s := pod.Req2K8sConvert{}
 	k8sPod := req2K8s.PodReq2K8s(podReq)
 	podApi := p.KubeConfigSet.CoreV1().Pods(k8sPod.Namespace)
 	k8sGetPod, err := podApi.Get(ctx, k8sPod.Name, metav1.GetOptions{})
-	if err != nil {
-		createdPod, err := podApi.Create(ctx, k8sPod, metav1.CreateOptions{})
-		if err != nil {
-			errMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]创建失败，detail：%s", k8sPod.Namespace, k8sPod.Name, err.Error())
-			return errMsg, err
+	if k8serror.IsNotFound(err) {
+		if err = applyImagePullSecrets(ctx, p.KubeConfigSet, k8sPod.Namespace, podReq.ImagePullSecrets, &k8sPod.Spec, nil); err != nil {
+			return nil, fmt.Errorf("Pod[namespace=%s,name=%s]创建失败，detail：%w", k8sPod.Namespace, k8sPod.Name, err)
 		}
-
-		successMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]创建成功", createdPod.Namespace, createdPod.Name)
-		return successMsg, err
-
+		if _, err = podApi.Create(ctx, k8sPod, metav1.CreateOptions{}); err != nil {
+			return nil, fmt.Errorf("Pod[namespace=%s,name=%s]创建失败，detail：%w", k8sPod.Namespace, k8sPod.Name, err)
+		}
+		return &types.PodUpdateResult{Action: types.PodActionCreated, Changes: []types.PodFieldChange{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = applyImagePullSecrets(ctx, p.KubeConfigSet, k8sPod.Namespace, podReq.ImagePullSecrets, &k8sPod.Spec, &k8sGetPod.Spec); err != nil {
+		return nil, fmt.Errorf("Pod[namespace=%s,name=%s]更新失败，detail：%w", k8sPod.Namespace, k8sPod.Name, err)
 	}
 
-	// 更新
-	// 先干运行尝试,确定参数合不合法
-	k8sPodCopy := *k8sPod
+	// 干运行创建一个副本, 校验参数并获得apiserver填充默认值后的对象, 与线上pod比较
+	k8sPodCopy := k8sPod.DeepCopy()
 	k8sPodCopy.Name = fmt.Sprintf("%s-validate", k8sPod.Name)
-	_, err = podApi.Create(ctx, &k8sPodCopy, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
-
+	desired, err := podApi.Create(ctx, k8sPodCopy, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
 	if err != nil {
-		errMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]更新失败，detail：%s", k8sPod.Namespace, k8sPod.Name, err.Error())
-		return errMsg, err
+		return nil, fmt.Errorf("Pod[namespace=%s,name=%s]更新失败，detail：%w", k8sPod.Namespace, k8sPod.Name, err)
 	}
+	desired.Name = k8sPod.Name
 
-	//比如pod处于terminating状态 监听pod删除完毕之后 才开始创建pod
-	var labelSelector []string
+	patched, changes := pod.Diff(k8sGetPod, desired, podReq.Base.Annotations != nil)
+	result := &types.PodUpdateResult{Action: types.PodActionUnchanged, Changes: changes}
+	if len(changes) == 0 {
+		return result, nil
+	}
 
-	for k, v := range k8sGetPod.Labels {
-		labelSelector = append(labelSelector, fmt.Sprintf("%s=%s", k, v))
+	if !pod.NeedRecreate(changes) {
+		if err = p.patchPod(ctx, k8sGetPod, patched); err != nil {
+			return nil, fmt.Errorf("Pod[namespace=%s,name=%s]更新失败，detail：%w", k8sPod.Namespace, k8sPod.Name, err)
+		}
+		result.Action = types.PodActionUpdated
+		return result, nil
 	}
 
-	// label 的格式 app=test,app2=test2
-	watcher, err := podApi.Watch(ctx, metav1.ListOptions{
-		LabelSelector: strings.Join(labelSelector, ","),
-	})
+	if !recreate {
+		return result, ErrPodRecreateRequired
+	}
+	if err = p.recreatePod(ctx, k8sGetPod, k8sPod); err != nil {
+		return nil, fmt.Errorf("Pod[namespace=%s,name=%s]重建失败，detail：%w", k8sPod.Namespace, k8sPod.Name, err)
+	}
+	result.Action = types.PodActionRecreated
+	return result, nil
+}
 
+// patchPod 以strategic merge patch提交可原地更新的字段
+func (p *PodController) patchPod(ctx context.Context, current, patched *corev1.Pod) error {
+	currentJSON, err := json.Marshal(current)
 	if err != nil {
-		errMsg := fmt.Sprintf("Pod[namespace = %s,name=%s]更新失败,desc:%s", k8sPod.Namespace, k8sPod.Name, err.Error())
-		return errMsg, err
+		return err
 	}
-	//删除 -- 强制删除
-	background := metav1.DeletePropagationBackground
-	var gracePeriodSeconds int64 = 0
-	err = podApi.Delete(ctx, k8sPod.Name, metav1.DeleteOptions{
-		GracePeriodSeconds: &gracePeriodSeconds,
-		PropagationPolicy:  &background,
-	})
+	patchedJSON, err := json.Marshal(patched)
 	if err != nil {
-		errMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]更新失败，detail：%s", k8sPod.Namespace, k8sPod.Name, err.Error())
-		return errMsg, err
-	}
-
-	for {
-		select {
-		case event := <-watcher.ResultChan():
-			k8sPodChan := event.Object.(*corev1.Pod)
-			//查询k8s 判断是否已经删除 那么就不用判断删除事件了
-			if _, err := podApi.Get(ctx, k8sPod.Name, metav1.GetOptions{}); k8serror.IsNotFound(err) {
-				//重新创建
-				if createdPod, err := podApi.Create(ctx, k8sPod, metav1.CreateOptions{}); err != nil {
-					errMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]更新失败，detail：%s", k8sPod.Namespace, k8sPod.Name, err.Error())
-					return errMsg, err
-				} else {
-					successMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]更新成功", createdPod.Namespace, createdPod.Name)
-					return successMsg, err
-				}
-			}
-			switch event.Type {
-			case watch.Deleted:
-				if k8sPodChan.Name != k8sPod.Name {
-					continue
-				}
-				//重新创建
-				if createdPod, err := podApi.Create(ctx, k8sPod, metav1.CreateOptions{}); err != nil {
-					errMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]更新失败，detail：%s", k8sPod.Namespace, k8sPod.Name, err.Error())
-					return errMsg, err
-				} else {
-					successMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]更新成功", createdPod.Namespace, createdPod.Name)
-					return successMsg, err
-				}
-			}
-		case <-time.After(5 * time.Second):
-			//重新创建
-			if createdPod, err := podApi.Create(ctx, k8sPod, metav1.CreateOptions{}); err != nil {
-				errMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]更新失败，detail：%s", k8sPod.Namespace, k8sPod.Name, err.Error())
-				return errMsg, err
-			} else {
-				successMsg := fmt.Sprintf("Pod[namespace=%s,name=%s]更新成功", createdPod.Namespace, createdPod.Name)
-				return successMsg, err
-			}
+		return err
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(currentJSON, patchedJSON, corev1.Pod{})
+	if err != nil {
+		return err
+	}
+	_, err = p.KubeConfigSet.CoreV1().Pods(current.Namespace).Patch(ctx, current.Name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// recreatePod 按pod自身的 terminationGracePeriodSeconds 优雅删除, 等待删除完成后重新创建
+func (p *PodController) recreatePod(ctx context.Context, current, k8sPod *corev1.Pod) error {
+	podApi := p.KubeConfigSet.CoreV1().Pods(current.Namespace)
+	uid := current.UID
+	err := podApi.Delete(ctx, current.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+
+	timeout := podDeleteTimeout
+	if current.Spec.TerminationGracePeriodSeconds != nil {
+		timeout += time.Duration(*current.Spec.TerminationGracePeriodSeconds) * time.Second
+	}
+	err = wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		k8sPod, err := podApi.Get(ctx, current.Name, metav1.GetOptions{})
+		if k8serror.IsNotFound(err) {
+			return true, nil
 		}
+		if err != nil {
+			return false, err
+		}
+		return k8sPod.UID != uid, nil
+	})
+	if err != nil {
+		return fmt.Errorf("等待pod删除失败: %w", err)
 	}
+
+	_, err = podApi.Create(ctx, k8sPod, metav1.CreateOptions{})
+	return err
 }
 
-func (p *PodController) GetPodList(ctx context.Context, reqParam *types.GetPodListRequest) ([]*types.PodListItem, error) {
-	list, err := p.KubeConfigSet.CoreV1().Pods(reqParam.Namespace).List(ctx, metav1.ListOptions{})
+func (p *PodController) GetPodList(ctx context.Context, reqParam *types.GetPodListRequest, query *types.ListQuery) ([]*types.PodListItem, *types.ListMeta, error) {
+	items, err := listPods(ctx, p.KubeConfigSet, p.Cache, reqParam.Namespace)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	if reqParam.NodeName != "" {
+		nodePods := make([]corev1.Pod, 0)
+		for _, item := range items {
+			if item.Spec.NodeName == reqParam.NodeName {
+				nodePods = append(nodePods, item)
+			}
+		}
+		items = nodePods
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	podList := make([]*types.PodListItem, 0)
 	k8s2req := &pod.K8s2ReqConvert{}
-	for _, item := range list.Items {
-		if reqParam.NodeName != "" && item.Spec.NodeName != reqParam.NodeName {
-			continue
-		}
-		if strings.Contains(item.Name, reqParam.Keyword) {
-			podItem := k8s2req.PodK8s2ItemRes(item)
-			podList = append(podList, &podItem)
-		}
+	for _, item := range items {
+		podItem := k8s2req.PodK8s2ItemRes(item)
+		podList = append(podList, &podItem)
 	}
-	return podList, err
+	return podList, listMeta, nil
 }
 
 func (p *PodController) GetPodDetail(ctx context.Context, reqParam *types.GetPodDetailRequest) (*types.Pod, error) {
@@ -177,20 +204,146 @@ func (p *PodController) DeletePod(ctx context.Context, reqParam *types.DeletedPo
 	})
 }
 
-func (p *PodController) GetNamespaceList(ctx context.Context) ([]*types.Namespace, error) {
-	list, err := p.KubeConfigSet.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+// GetPodLogs 返回日志流, 调用方负责关闭; follow 时直到ctx取消或容器退出才结束
+func (p *PodController) GetPodLogs(ctx context.Context, reqParam *types.GetPodLogsRequest) (io.ReadCloser, error) {
+	return streamPodLogs(ctx, p.KubeConfigSet, reqParam)
+}
 
+// DownloadPodLogs 依次写入各容器的日志, 以 "==> 容器名 <==" 分隔
+// 未指定容器时包含所有 initContainer 和 container, 单个容器读取失败时写入错误信息后继续
+func (p *PodController) DownloadPodLogs(ctx context.Context, reqParam *types.GetPodLogsRequest, w io.Writer) error {
+	return downloadPodLogs(ctx, p.KubeConfigSet, reqParam, w)
+}
+
+// PodLogsFilename 下载日志的文件名, 指定容器时包含容器名
+func PodLogsFilename(reqParam *types.GetPodLogsRequest) string {
+	filename := reqParam.Name
+	if reqParam.Container != "" {
+		filename += "-" + reqParam.Container
+	}
+	return filename + ".log"
+}
+
+func streamPodLogs(ctx context.Context, client kubernetes.Interface, reqParam *types.GetPodLogsRequest) (io.ReadCloser, error) {
+	return client.CoreV1().Pods(reqParam.Namespace).
+		GetLogs(reqParam.Name, podLogOptions(reqParam, reqParam.Container, reqParam.Follow)).Stream(ctx)
+}
+
+func downloadPodLogs(ctx context.Context, client kubernetes.Interface, reqParam *types.GetPodLogsRequest, w io.Writer) error {
+	podApi := client.CoreV1().Pods(reqParam.Namespace)
+	containers := []string{reqParam.Container}
+	if reqParam.Container == "" {
+		k8sPod, err := podApi.Get(ctx, reqParam.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		containers = containers[:0]
+		for _, container := range k8sPod.Spec.InitContainers {
+			containers = append(containers, container.Name)
+		}
+		for _, container := range k8sPod.Spec.Containers {
+			containers = append(containers, container.Name)
+		}
+	}
+
+	for _, container := range containers {
+		if _, err := fmt.Fprintf(w, "==> %s <==\n", container); err != nil {
+			return err
+		}
+		stream, err := podApi.GetLogs(reqParam.Name, podLogOptions(reqParam, container, false)).Stream(ctx)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "%s\n\n", err.Error())
+			continue
+		}
+		_, err = io.Copy(w, stream)
+		stream.Close()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// ExecPod 在容器中启动shell并连接到终端, 优先使用websocket协议, apiserver不支持时回退到SPDY
+func (p *PodController) ExecPod(ctx context.Context, config *rest.Config, reqParam *types.PodExecRequest, streams remotecommand.StreamOptions) error {
+	req := p.KubeConfigSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(reqParam.Namespace).
+		Name(reqParam.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: reqParam.Container,
+			Command:   ExecCommand(reqParam.Shell),
+			Stdin:     true,
+			Stdout:    true,
+			TTY:       true,
+		}, scheme.ParameterCodec)
+
+	spdyExec, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
 	if err != nil {
-		return nil, err
+		return err
+	}
+	wsExec, err := remotecommand.NewWebSocketExecutor(config, "GET", req.URL().String())
+	if err != nil {
+		return err
+	}
+	executor, err := remotecommand.NewFallbackExecutor(wsExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, streams)
+}
+
+// ExecCommand 终端启动的命令, 未指定shell时优先使用bash
+func ExecCommand(shell string) []string {
+	if shell != "" {
+		return []string{shell}
+	}
+	return []string{"/bin/sh", "-c", "export TERM=xterm-256color; if command -v bash >/dev/null 2>&1; then exec bash; else exec sh; fi"}
+}
+
+func podLogOptions(reqParam *types.GetPodLogsRequest, container string, follow bool) *corev1.PodLogOptions {
+	return &corev1.PodLogOptions{
+		Container:    container,
+		Follow:       follow,
+		TailLines:    reqParam.TailLines,
+		SinceSeconds: reqParam.SinceSeconds,
+		Previous:     reqParam.Previous,
+		Timestamps:   reqParam.Timestamps,
+	}
+}
+
+// GetNamespaceList visible 为nil时返回全部命名空间, 否则在分页前过滤掉不可见的命名空间
+func (p *PodController) GetNamespaceList(ctx context.Context, query *types.ListQuery, visible func(namespace string) bool) ([]*types.Namespace, *types.ListMeta, error) {
+	items, err := listNamespaces(ctx, p.KubeConfigSet, p.Cache)
+
+	if err != nil {
+		return nil, nil, err
+	}
+	if visible != nil {
+		filtered := make([]corev1.Namespace, 0, len(items))
+		for _, item := range items {
+			if visible(item.Name) {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	namespaceList := make([]*types.Namespace, 0)
-	for _, item := range list.Items {
+	for _, item := range items {
 		namespaceList = append(namespaceList, &types.Namespace{
 			Name:              item.Name,
 			CreationTimestamp: item.CreationTimestamp.Unix(),
 			Status:            string(item.Status.Phase),
 		})
 	}
-	return namespaceList, nil
+	return namespaceList, listMeta, nil
 }
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+func newLogsClient() *fake.Clientset {
+	return fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "app"}, {Name: "sidecar"}},
+		},
+	})
+}
+
+// logOptions 按调用顺序返回每次读取日志的参数
+func logOptions(client *fake.Clientset) []*corev1.PodLogOptions {
+	result := make([]*corev1.PodLogOptions, 0)
+	for _, action := range client.Actions() {
+		if action.GetSubresource() != "log" {
+			continue
+		}
+		result = append(result, action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions))
+	}
+	return result
+}
+
+func TestStreamPodLogs(t *testing.T) {
+	client := newLogsClient()
+	reqParam := &types.GetPodLogsRequest{
+		Namespace:    "default",
+		Name:         "web-0",
+		Container:    "app",
+		Follow:       true,
+		TailLines:    ptr.To[int64](100),
+		SinceSeconds: ptr.To[int64](60),
+		Previous:     true,
+		Timestamps:   true,
+	}
+	stream, err := streamPodLogs(context.Background(), client, reqParam)
+	require.NoError(t, err)
+	defer stream.Close()
+	data, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	assert.Equal(t, "fake logs", string(data))
+
+	assert.Equal(t, []*corev1.PodLogOptions{{
+		Container:    "app",
+		Follow:       true,
+		TailLines:    ptr.To[int64](100),
+		SinceSeconds: ptr.To[int64](60),
+		Previous:     true,
+		Timestamps:   true,
+	}}, logOptions(client))
+}
+
+func TestDownloadPodLogs(t *testing.T) {
+	// 未指定容器时依次下载 initContainer 和 container 的日志, 下载时不 follow
+	client := newLogsClient()
+	reqParam := &types.GetPodLogsRequest{Namespace: "default", Name: "web-0", Follow: true, TailLines: ptr.To[int64](10), Previous: true}
+	buf := &bytes.Buffer{}
+	require.NoError(t, downloadPodLogs(context.Background(), client, reqParam, buf))
+	assert.Equal(t, "==> init <==\nfake logs\n==> app <==\nfake logs\n==> sidecar <==\nfake logs\n", buf.String())
+
+	options := logOptions(client)
+	require.Len(t, options, 3)
+	for i, container := range []string{"init", "app", "sidecar"} {
+		assert.Equal(t, &corev1.PodLogOptions{Container: container, TailLines: ptr.To[int64](10), Previous: true}, options[i])
+	}
+
+	// 指定容器时不需要查询pod
+	client = newLogsClient()
+	reqParam.Container = "sidecar"
+	buf.Reset()
+	require.NoError(t, downloadPodLogs(context.Background(), client, reqParam, buf))
+	assert.Equal(t, "==> sidecar <==\nfake logs\n", buf.String())
+	require.Len(t, client.Actions(), 1)
+	assert.Equal(t, "sidecar", logOptions(client)[0].Container)
+
+	reqParam = &types.GetPodLogsRequest{Namespace: "default", Name: "missing"}
+	err := downloadPodLogs(context.Background(), newLogsClient(), reqParam, buf)
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestPodLogsFilename(t *testing.T) {
+	assert.Equal(t, "web-0.log", PodLogsFilename(&types.GetPodLogsRequest{Name: "web-0"}))
+	assert.Equal(t, "web-0-app.log", PodLogsFilename(&types.GetPodLogsRequest{Name: "web-0", Container: "app"}))
+}
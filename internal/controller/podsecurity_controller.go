@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/pod"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/podsecurity"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+type PodSecurityController struct {
+	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
+}
+
+func NewPodSecurityController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *PodSecurityController {
+	return &PodSecurityController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
+}
+
+// CheckPod 按pod安全标准检查pod模板, namespace 为实际创建的命名空间, level 为空时使用命名空间的标签
+func (p *PodSecurityController) CheckPod(ctx context.Context, podReq *types.Pod, namespace string, level string) (*types.PodSecurityResult, error) {
+	labels, err := p.namespaceLabels(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	k8sPod := (&pod.Req2K8sConvert{}).PodReq2K8s(podReq)
+	return podsecurity.Evaluate(k8sPod, labels, level), nil
+}
+
+// namespaceLabels 命名空间不存在时没有标签, 由后续的创建请求返回错误
+func (p *PodSecurityController) namespaceLabels(ctx context.Context, namespace string) (map[string]string, error) {
+	if namespace == "" {
+		return nil, nil
+	}
+	if lister, ok := p.Cache.Namespaces(); ok {
+		ns, err := lister.Get(namespace)
+		return nsLabels(ns, err)
+	}
+	ns, err := p.KubeConfigSet.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	return nsLabels(ns, err)
+}
+
+func nsLabels(ns *corev1.Namespace, err error) (map[string]string, error) {
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ns.Labels, nil
+}
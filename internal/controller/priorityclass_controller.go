@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+type PriorityClassController struct {
+	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
+}
+
+func NewPriorityClassController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *PriorityClassController {
+	return &PriorityClassController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
+}
+
+// GetPriorityClassList PriorityClass 数量很少且不在informer缓存中, 直接请求apiserver
+func (p *PriorityClassController) GetPriorityClassList(ctx context.Context, query *types.ListQuery) ([]*types.PriorityClassRes, *types.ListMeta, error) {
+	list, err := p.KubeConfigSet.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(list.Items, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	resList := make([]*types.PriorityClassRes, 0, len(items))
+	for _, item := range items {
+		preemptionPolicy := corev1.PreemptLowerPriority
+		if item.PreemptionPolicy != nil {
+			preemptionPolicy = *item.PreemptionPolicy
+		}
+		resList = append(resList, &types.PriorityClassRes{
+			Name:             item.Name,
+			Labels:           maputils.ToList(item.Labels),
+			Value:            item.Value,
+			GlobalDefault:    item.GlobalDefault,
+			PreemptionPolicy: preemptionPolicy,
+			Description:      item.Description,
+			Age:              item.CreationTimestamp.UnixMilli(),
+		})
+	}
+	return resList, listMeta, nil
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/pullsecret"
+)
+
+// 测试中替换, 避免访问数据库
+var loadPullSecretCredentials = pullsecret.LoadCredentials
+
+// applyImagePullSecrets 处理pod模板的 imagePullSecrets, current 为更新前的pod模板, 新建时为nil
+// 请求未指定时: 更新保持原有配置, 新建在配置了镜像仓库凭证时使用托管secret
+// 引用了托管secret时确保该命名空间下的secret存在且凭证是最新的
+func applyImagePullSecrets(ctx context.Context, client kubernetes.Interface, namespace string, requested []string, spec, current *corev1.PodSpec) error {
+	if requested == nil && current != nil {
+		spec.ImagePullSecrets = current.ImagePullSecrets
+	}
+	useDefault := requested == nil && current == nil
+	if !useDefault && !hasManagedPullSecret(spec.ImagePullSecrets) {
+		return nil
+	}
+
+	creds, err := loadPullSecretCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	if useDefault {
+		if len(creds) == 0 {
+			return nil
+		}
+		spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: pullsecret.ManagedSecretName}}
+	}
+	return pullsecret.Ensure(ctx, client, namespace, creds)
+}
+
+func hasManagedPullSecret(refs []corev1.LocalObjectReference) bool {
+	for _, ref := range refs {
+		if ref.Name == pullsecret.ManagedSecretName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/pullsecret"
+)
+
+func TestApplyImagePullSecrets(t *testing.T) {
+	ctx := context.Background()
+	creds := []pullsecret.Credential{{Server: "harbor.example.com", Username: "admin", Password: "secret"}}
+	defer func(fn func(context.Context) ([]pullsecret.Credential, error)) { loadPullSecretCredentials = fn }(loadPullSecretCredentials)
+	loadPullSecretCredentials = func(context.Context) ([]pullsecret.Credential, error) { return creds, nil }
+	managed := []corev1.LocalObjectReference{{Name: pullsecret.ManagedSecretName}}
+
+	// 新建时默认使用托管secret
+	client := fake.NewSimpleClientset()
+	spec := &corev1.PodSpec{}
+	require.NoError(t, applyImagePullSecrets(ctx, client, "default", nil, spec, nil))
+	assert.Equal(t, managed, spec.ImagePullSecrets)
+	_, err := client.CoreV1().Secrets("default").Get(ctx, pullsecret.ManagedSecretName, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// 更新时未指定则保持原有配置
+	current := &corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "mine"}}}
+	spec = &corev1.PodSpec{}
+	require.NoError(t, applyImagePullSecrets(ctx, client, "default", nil, spec, current))
+	assert.Equal(t, current.ImagePullSecrets, spec.ImagePullSecrets)
+
+	// 显式指定为空时不使用
+	spec = &corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{}}
+	require.NoError(t, applyImagePullSecrets(ctx, fake.NewSimpleClientset(), "default", []string{}, spec, nil))
+	assert.Empty(t, spec.ImagePullSecrets)
+
+	// 没有配置凭证时不设置默认值
+	creds = nil
+	spec = &corev1.PodSpec{}
+	require.NoError(t, applyImagePullSecrets(ctx, fake.NewSimpleClientset(), "default", nil, spec, nil))
+	assert.Nil(t, spec.ImagePullSecrets)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
@@ -14,27 +16,20 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"strconv"
 	"strings"
-	"sync"
-)
-
-var (
-	pvInstance       *PvController
-	pvControllerOnce sync.Once
 )
 
 type PvController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          *global.Server
 }
 
-func NewPvController() *PvController {
-	pvControllerOnce.Do(func() {
-		pvInstance = &PvController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-			CONF:          global.CONF,
-		}
-	})
-	return pvInstance
+func NewPvController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *PvController {
+	return &PvController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+		CONF:          global.CONF,
+	}
 }
 
 func (c *PvController) Createpv(ctx context.Context, reqParm *types.PersistentVolumeRequest) error {
@@ -68,18 +63,18 @@ func (c *PvController) Createpv(ctx context.Context, reqParm *types.PersistentVo
 	return err
 }
 
-func (c *PvController) GetPvList(ctx context.Context, keyword string) ([]*types.PersistentVolumeRes, error) {
-	pvList, err := c.KubeConfigSet.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+func (c *PvController) GetPvList(ctx context.Context, query *types.ListQuery) ([]*types.PersistentVolumeRes, *types.ListMeta, error) {
+	items, err := listPersistentVolumes(ctx, c.KubeConfigSet, c.Cache)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 	pvResList := make([]*types.PersistentVolumeRes, 0)
-	for _, item := range pvList.Items {
-		if !strings.Contains(item.Name, keyword) {
-			continue
-		}
-
+	for _, item := range items {
 		claim := ""
 
 		if item.Spec.ClaimRef != nil {
@@ -102,7 +97,7 @@ func (c *PvController) GetPvList(ctx context.Context, keyword string) ([]*types.
 		}
 		pvResList = append(pvResList, pvRes)
 	}
-	return pvResList, nil
+	return pvResList, listMeta, nil
 }
 
 func (c *PvController) DeletePV(ctx context.Context, name string) error {
@@ -139,18 +134,19 @@ func (c *PvController) CreatePVC(ctx context.Context, reqParam *types.Persistent
 	return err
 }
 
-func (c *PvController) GetPVCList(ctx context.Context, namespace string, keyword string) ([]*types.PersistentVolumeClaimRes, error) {
+func (c *PvController) GetPVCList(ctx context.Context, namespace string, query *types.ListQuery) ([]*types.PersistentVolumeClaimRes, *types.ListMeta, error) {
 	pvcResList := make([]*types.PersistentVolumeClaimRes, 0)
-	list, err := c.KubeConfigSet.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	items, err := listPersistentVolumeClaims(ctx, c.KubeConfigSet, c.Cache, namespace)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	for _, item := range list.Items {
-		//if strings.Contains(item.Name,keyword) {
-		//	continue
-		//}
+	for _, item := range items {
 		matchLabels := make([]types.ListMapItem, 0)
 
 		if item.Spec.Selector != nil {
@@ -171,7 +167,7 @@ func (c *PvController) GetPVCList(ctx context.Context, namespace string, keyword
 
 		pvcResList = append(pvcResList, pvcResItem)
 	}
-	return pvcResList, nil
+	return pvcResList, listMeta, nil
 }
 
 func (c *PvController) DeletePVC(ctx context.Context, namespace string, name string) error {
@@ -209,19 +205,19 @@ func (c *PvController) CreateSC(ctx context.Context, reqParam *types.StorageClas
 	return err
 }
 
-func (c *PvController) GetSCList(ctx context.Context, keyword string) ([]*types.StorageClassRes, error) {
-	list, err := c.KubeConfigSet.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+func (c *PvController) GetSCList(ctx context.Context, query *types.ListQuery) ([]*types.StorageClassRes, *types.ListMeta, error) {
+	items, err := listStorageClasses(ctx, c.KubeConfigSet, c.Cache)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 	scResList := make([]*types.StorageClassRes, 0)
 
-	for _, item := range list.Items {
-		//if strings.Contains(item.Name, keyword) {
-		//	continue
-		//}
-
+	for _, item := range items {
 		// item ->response
 		var allowVolumeExpansion bool
 		if item.AllowVolumeExpansion != nil {
@@ -245,7 +241,7 @@ func (c *PvController) GetSCList(ctx context.Context, keyword string) ([]*types.
 		}
 		scResList = append(scResList, scResItem)
 	}
-	return scResList, err
+	return scResList, listMeta, nil
 }
 
 func (c *PvController) DeleteSC(ctx context.Context, name string) error {
@@ -2,43 +2,43 @@ package controller
 
 import (
 	"context"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
+	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/internal/types/rbac"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"sync"
-)
-
-var (
-	rbacInstance       *RbacpController
-	rbacControllerOnce sync.Once
 )
 
 type RbacpController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          global.Server
 }
 
-func NewRbacController() *RbacpController {
-	rbacControllerOnce.Do(func() {
-		rbacInstance = &RbacpController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-		}
-	})
-	return rbacInstance
+func NewRbacController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *RbacpController {
+	return &RbacpController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
 }
 
-func (s *RbacpController) ServiceAccounts(ctx context.Context, namespace string, name string) ([]*rbac.ServiceAccount, error) {
-	list, err := s.KubeConfigSet.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+func (s *RbacpController) ServiceAccounts(ctx context.Context, namespace string, query *types.ListQuery) ([]*rbac.ServiceAccount, *types.ListMeta, error) {
+	items, err := listServiceAccounts(ctx, s.KubeConfigSet, s.Cache, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	resList := make([]*rbac.ServiceAccount, 0)
 
-	for _, item := range list.Items {
+	for _, item := range items {
 		resList = append(resList, &rbac.ServiceAccount{
 			Name:      item.Name,
 			Namespace: item.Namespace,
@@ -46,7 +46,7 @@ func (s *RbacpController) ServiceAccounts(ctx context.Context, namespace string,
 		})
 	}
 
-	return resList, err
+	return resList, listMeta, nil
 }
 
 func (s *RbacpController) CreateServiceAccount(ctx context.Context, reqParam *rbac.ServiceAccountRequest) error {
@@ -95,35 +95,43 @@ func (s *RbacpController) GetRoleDetail(ctx context.Context, namespace string, n
 	// ClusterRoles
 }
 
-func (s *RbacpController) GetRoleList(ctx context.Context, namespace string) ([]*rbac.Role, error) {
+func (s *RbacpController) GetRoleList(ctx context.Context, namespace string, query *types.ListQuery) ([]*rbac.Role, *types.ListMeta, error) {
 	resRoleList := make([]*rbac.Role, 0)
 	if namespace != "" {
-		roleK8sList, err := s.KubeConfigSet.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+		roleK8sList, err := listRoles(ctx, s.KubeConfigSet, s.Cache, namespace)
 		if err != nil {
-			return resRoleList, err
+			return resRoleList, nil, err
 		}
-		for _, item := range roleK8sList.Items {
-			resRoleList = append(resRoleList, &rbac.Role{
-				Name:      item.Name,
-				Namespace: item.Namespace,
-				Age:       item.CreationTimestamp.Unix(),
-			})
-		}
-
-	} else {
-		roleK8sList, err := s.KubeConfigSet.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+		roleK8sList, listMeta, err := listquery.Apply(roleK8sList, query)
 		if err != nil {
-			return resRoleList, err
+			return resRoleList, nil, err
 		}
-		for _, item := range roleK8sList.Items {
+		for _, item := range roleK8sList {
 			resRoleList = append(resRoleList, &rbac.Role{
 				Name:      item.Name,
 				Namespace: item.Namespace,
 				Age:       item.CreationTimestamp.Unix(),
 			})
 		}
+		return resRoleList, listMeta, nil
+	}
+
+	roleK8sList, err := listClusterRoles(ctx, s.KubeConfigSet, s.Cache)
+	if err != nil {
+		return resRoleList, nil, err
+	}
+	roleK8sList, listMeta, err := listquery.Apply(roleK8sList, query)
+	if err != nil {
+		return resRoleList, nil, err
 	}
-	return resRoleList, nil
+	for _, item := range roleK8sList {
+		resRoleList = append(resRoleList, &rbac.Role{
+			Name:      item.Name,
+			Namespace: item.Namespace,
+			Age:       item.CreationTimestamp.Unix(),
+		})
+	}
+	return resRoleList, listMeta, nil
 }
 
 func (s *RbacpController) CreateOrUpdateRole(ctx context.Context, reqParam *rbac.RoleRequest) error {
@@ -240,42 +248,43 @@ func (s *RbacpController) GetRbDetail(ctx context.Context, namespace string, nam
 	return rbRes, nil
 }
 
-func (s *RbacpController) GetRbList(ctx context.Context, namespace string, name string) ([]*rbac.RoleBindingRes, error) {
+func (s *RbacpController) GetRbList(ctx context.Context, namespace string, query *types.ListQuery) ([]*rbac.RoleBindingRes, *types.ListMeta, error) {
 	rbResList := make([]*rbac.RoleBindingRes, 0)
 	if namespace != "" {
-		list, err := s.KubeConfigSet.RbacV1().RoleBindings(namespace).
-			List(ctx, metav1.ListOptions{})
+		items, err := listRoleBindings(ctx, s.KubeConfigSet, s.Cache, namespace)
 		if err != nil {
-			return rbResList, err
-		}
-		for _, item := range list.Items {
-			//if !strings.Contains(item.Name, keyword) {
-			//	continue
-			//}
-			rbResList = append(rbResList, &rbac.RoleBindingRes{
-				Name:      item.Name,
-				Namespace: item.Namespace,
-				Age:       item.CreationTimestamp.Unix(),
-			})
+			return rbResList, nil, err
 		}
-	} else {
-		list, err := s.KubeConfigSet.RbacV1().ClusterRoleBindings().
-			List(ctx, metav1.ListOptions{})
+		items, listMeta, err := listquery.Apply(items, query)
 		if err != nil {
-			return rbResList, err
+			return rbResList, nil, err
 		}
-		for _, item := range list.Items {
-			//if !strings.Contains(item.Name, keyword) {
-			//	continue
-			//}
+		for _, item := range items {
 			rbResList = append(rbResList, &rbac.RoleBindingRes{
 				Name:      item.Name,
 				Namespace: item.Namespace,
 				Age:       item.CreationTimestamp.Unix(),
 			})
 		}
+		return rbResList, listMeta, nil
+	}
+
+	items, err := listClusterRoleBindings(ctx, s.KubeConfigSet, s.Cache)
+	if err != nil {
+		return rbResList, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return rbResList, nil, err
+	}
+	for _, item := range items {
+		rbResList = append(rbResList, &rbac.RoleBindingRes{
+			Name:      item.Name,
+			Namespace: item.Namespace,
+			Age:       item.CreationTimestamp.Unix(),
+		})
 	}
-	return rbResList, nil
+	return rbResList, listMeta, nil
 }
 
 func (s *RbacpController) CreateOrUpdateRolebing(ctx context.Context, reqParam *rbac.RoleBindingRequest) error {
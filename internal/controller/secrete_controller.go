@@ -3,32 +3,26 @@ package controller
 import (
 	"context"
 	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/secrete"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"strings"
-	"sync"
-)
-
-var (
-	secreteInstance       *SecreteController
-	secreteControllerOnce sync.Once
 )
 
 type SecreteController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          global.Server
 }
 
-func NewSecreteController() *SecreteController {
-	secreteControllerOnce.Do(func() {
-		secreteInstance = &SecreteController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-		}
-	})
-	return secreteInstance
+func NewSecreteController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *SecreteController {
+	return &SecreteController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
 }
 
 func (s *SecreteController) CreateOrUpdateSecret(ctx context.Context, reqParam *types.CreateOrUpadteSecreteRequest) error {
@@ -47,25 +41,24 @@ func (s *SecreteController) CreateOrUpdateSecret(ctx context.Context, reqParam *
 	return err
 }
 
-func (s *SecreteController) GetSecretList(ctx context.Context, namespace string, keyword string) ([]*types.Secret, error) {
-	list, err := s.KubeConfigSet.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+func (s *SecreteController) GetSecretList(ctx context.Context, namespace string, query *types.ListQuery) ([]*types.Secret, *types.ListMeta, error) {
+	items, err := listSecrets(ctx, s.KubeConfigSet, s.Cache, namespace)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	secreteList := make([]*types.Secret, 0)
 	secretConvert := &secrete.K8s2Res{}
-	for _, item := range list.Items {
-
-		if !strings.Contains(item.Name, keyword) {
-			continue
-		}
-
+	for _, item := range items {
 		secretRes := secretConvert.SecretK8s2ResItemConvert(item)
 		secreteList = append(secreteList, secretRes)
 	}
-	return secreteList, err
+	return secreteList, listMeta, nil
 }
 
 func (s *SecreteController) GetSecretDetail(ctx context.Context, namespace string, name string) (*types.Secret, error) {
@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/pod"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+type StatefulSetController struct {
+	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
+	CONF          global.Server
+}
+
+func NewStatefulSetController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *StatefulSetController {
+	return &StatefulSetController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+	}
+}
+
+func (s *StatefulSetController) CreateOrUpdateStatefulSet(ctx context.Context, reqParam *types.StatefulSetRequest) error {
+	// 转换为k8s结构
+	podK8sConvert := pod.Req2K8sConvert{}
+	podK8s := podK8sConvert.PodReq2K8s(reqParam.Template)
+	partition := reqParam.Base.Partition
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      reqParam.Base.Name,
+			Namespace: reqParam.Base.Namespace,
+			Labels:    maputils.ToMap(reqParam.Base.Labels),
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:            &reqParam.Base.Replicas,
+			ServiceName:         reqParam.Base.ServiceName,
+			PodManagementPolicy: appsv1.PodManagementPolicyType(reqParam.Base.PodManagementPolicy),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: maputils.ToMap(reqParam.Base.Selector),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: podK8s.ObjectMeta,
+				Spec:       podK8s.Spec,
+			},
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+					Partition: &partition,
+				},
+			},
+		},
+	}
+	for i := range reqParam.VolumeClaimTemplates {
+		statefulSet.Spec.VolumeClaimTemplates = append(statefulSet.Spec.VolumeClaimTemplates, pvcTemplateReq2K8s(&reqParam.VolumeClaimTemplates[i]))
+	}
+
+	statefulSetApi := s.KubeConfigSet.AppsV1().StatefulSets(statefulSet.Namespace)
+	statefulSetK8s, err := statefulSetApi.Get(ctx, statefulSet.Name, metav1.GetOptions{})
+	if err == nil {
+		err = applyImagePullSecrets(ctx, s.KubeConfigSet, statefulSet.Namespace, reqParam.Template.ImagePullSecrets, &statefulSet.Spec.Template.Spec, &statefulSetK8s.Spec.Template.Spec)
+		if err != nil {
+			return err
+		}
+		// serviceName、selector、podManagementPolicy、volumeClaimTemplates 创建后不能修改
+		statefulSetK8s.Labels = statefulSet.Labels
+		statefulSetK8s.Spec.Replicas = statefulSet.Spec.Replicas
+		statefulSetK8s.Spec.Template = statefulSet.Spec.Template
+		statefulSetK8s.Spec.UpdateStrategy = statefulSet.Spec.UpdateStrategy
+		_, err = statefulSetApi.Update(ctx, statefulSetK8s, metav1.UpdateOptions{})
+	} else {
+		err = applyImagePullSecrets(ctx, s.KubeConfigSet, statefulSet.Namespace, reqParam.Template.ImagePullSecrets, &statefulSet.Spec.Template.Spec, nil)
+		if err != nil {
+			return err
+		}
+		_, err = statefulSetApi.Create(ctx, statefulSet, metav1.CreateOptions{})
+	}
+	return err
+}
+
+func (s *StatefulSetController) GetStatefulSetDetail(ctx context.Context, namespace string, name string) (*types.StatefulSetResponse, error) {
+	statefulSetK8s, err := s.KubeConfigSet.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	podResConvert := pod.K8s2ReqConvert{}
+	podRes := podResConvert.PodK8s2Req(corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: statefulSetK8s.Spec.Template.Labels,
+		},
+		Spec: statefulSetK8s.Spec.Template.Spec,
+	})
+
+	statefulSetRes := &types.StatefulSetResponse{
+		Base: &types.StatefulSetBase{
+			Name:                statefulSetK8s.Name,
+			Namespace:           statefulSetK8s.Namespace,
+			Labels:              maputils.ToList(statefulSetK8s.Labels),
+			Selector:            maputils.ToList(statefulSetK8s.Spec.Selector.MatchLabels),
+			ServiceName:         statefulSetK8s.Spec.ServiceName,
+			PodManagementPolicy: string(statefulSetK8s.Spec.PodManagementPolicy),
+			Partition:           statefulSetPartition(statefulSetK8s),
+		},
+		Template:             &podRes,
+		VolumeClaimTemplates: make([]types.PersistentVolumeClaimRequest, 0),
+	}
+	if statefulSetK8s.Spec.Replicas != nil {
+		statefulSetRes.Base.Replicas = *statefulSetK8s.Spec.Replicas
+	}
+	for _, item := range statefulSetK8s.Spec.VolumeClaimTemplates {
+		statefulSetRes.VolumeClaimTemplates = append(statefulSetRes.VolumeClaimTemplates, pvcTemplateK8s2Req(&item))
+	}
+	return statefulSetRes, nil
+}
+
+func (s *StatefulSetController) GetStatefulSetList(ctx context.Context, namespace string, query *types.ListQuery) ([]*types.StatefulSetRes, *types.ListMeta, error) {
+	statefulSetList := make([]*types.StatefulSetRes, 0)
+	items, err := listStatefulSets(ctx, s.KubeConfigSet, s.Cache, namespace)
+	if err != nil {
+		return statefulSetList, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return statefulSetList, nil, err
+	}
+	if len(items) == 0 {
+		return statefulSetList, listMeta, nil
+	}
+
+	// 按owner分组, 用于展示每个序号的pod状态
+	pods, err := listPods(ctx, s.KubeConfigSet, s.Cache, namespace)
+	if err != nil {
+		return statefulSetList, nil, err
+	}
+	podsByOwner := make(map[k8stypes.UID][]corev1.Pod)
+	for _, item := range pods {
+		if owner := metav1.GetControllerOf(&item); owner != nil && owner.Kind == "StatefulSet" {
+			podsByOwner[owner.UID] = append(podsByOwner[owner.UID], item)
+		}
+	}
+
+	for i := range items {
+		statefulSetList = append(statefulSetList, statefulSetListItem(&items[i], podsByOwner[items[i].UID]))
+	}
+	return statefulSetList, listMeta, nil
+}
+
+func (s *StatefulSetController) DeleteStatefulSet(ctx context.Context, namespace, name string) error {
+	// pvc 不会随 statefulset 删除, 需要单独清理
+	return s.KubeConfigSet.AppsV1().StatefulSets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func statefulSetListItem(item *appsv1.StatefulSet, pods []corev1.Pod) *types.StatefulSetRes {
+	res := &types.StatefulSetRes{
+		Name:        item.Name,
+		Namespace:   item.Namespace,
+		Ready:       item.Status.ReadyReplicas,
+		Current:     item.Status.CurrentReplicas,
+		Updated:     item.Status.UpdatedReplicas,
+		Partition:   statefulSetPartition(item),
+		ServiceName: item.Spec.ServiceName,
+		Ordinals:    make([]*types.StatefulSetOrdinal, 0),
+		Age:         item.CreationTimestamp.Unix(),
+	}
+	if item.Spec.Replicas != nil {
+		res.Replicas = *item.Spec.Replicas
+	}
+
+	// 缩容过程中可能存在序号大于副本数的pod
+	podByOrdinal := make(map[int]*corev1.Pod)
+	count := int(res.Replicas)
+	for i := range pods {
+		ordinal, ok := statefulSetPodOrdinal(item.Name, pods[i].Name)
+		if !ok {
+			continue
+		}
+		podByOrdinal[ordinal] = &pods[i]
+		if ordinal >= count {
+			count = ordinal + 1
+		}
+	}
+	for ordinal := 0; ordinal < count; ordinal++ {
+		status := &types.StatefulSetOrdinal{
+			Ordinal: ordinal,
+			Name:    item.Name + "-" + strconv.Itoa(ordinal),
+		}
+		if k8sPod, ok := podByOrdinal[ordinal]; ok {
+			status.Phase = string(k8sPod.Status.Phase)
+			status.Ready = isPodReady(k8sPod)
+			status.Updated = k8sPod.Labels[appsv1.ControllerRevisionHashLabelKey] == item.Status.UpdateRevision
+		}
+		res.Ordinals = append(res.Ordinals, status)
+	}
+	return res
+}
+
+// statefulSetPodOrdinal pod 名称格式为 <statefulset>-<序号>
+func statefulSetPodOrdinal(statefulSetName, podName string) (int, bool) {
+	suffix, ok := strings.CutPrefix(podName, statefulSetName+"-")
+	if !ok {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	if err != nil || ordinal < 0 {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+func statefulSetPartition(item *appsv1.StatefulSet) int32 {
+	if rollingUpdate := item.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		return *rollingUpdate.Partition
+	}
+	return 0
+}
+
+func isPodReady(k8sPod *corev1.Pod) bool {
+	for _, condition := range k8sPod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// pvcTemplateReq2K8s 与创建pvc接口使用相同的参数, 容量单位为Mi
+func pvcTemplateReq2K8s(reqParam *types.PersistentVolumeClaimRequest) corev1.PersistentVolumeClaim {
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   reqParam.Name,
+			Labels: maputils.ToMap(reqParam.Labels),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: reqParam.AccessModes,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(strconv.Itoa(int(reqParam.Capacity)) + "Mi"),
+				},
+			},
+		},
+	}
+	if reqParam.StorageClassName != "" {
+		pvc.Spec.StorageClassName = &reqParam.StorageClassName
+	}
+	return pvc
+}
+
+func pvcTemplateK8s2Req(pvc *corev1.PersistentVolumeClaim) types.PersistentVolumeClaimRequest {
+	res := types.PersistentVolumeClaimRequest{
+		Name:        pvc.Name,
+		Labels:      maputils.ToList(pvc.Labels),
+		AccessModes: pvc.Spec.AccessModes,
+		Capacity:    int32(pvc.Spec.Resources.Requests.Storage().Value() / (1024 * 1024)),
+		Selector:    make([]types.ListMapItem, 0),
+	}
+	if pvc.Spec.StorageClassName != nil {
+		res.StorageClassName = *pvc.Spec.StorageClassName
+	}
+	return res
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatefulSetListItem(t *testing.T) {
+	replicas := int32(2)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "db"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{UpdateRevision: "mysql-v2"},
+	}
+	newPod := func(name, revision string, ready corev1.ConditionStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	pods := []corev1.Pod{
+		newPod("mysql-0", "mysql-v2", corev1.ConditionTrue),
+		newPod("mysql-2", "mysql-v1", corev1.ConditionFalse), // 缩容中
+		newPod("mysql-backup-0", "mysql-v2", corev1.ConditionTrue),
+	}
+
+	res := statefulSetListItem(statefulSet, pods)
+	assert.Len(t, res.Ordinals, 3)
+	assert.True(t, res.Ordinals[0].Ready)
+	assert.True(t, res.Ordinals[0].Updated)
+	assert.Equal(t, "", res.Ordinals[1].Phase)
+	assert.Equal(t, "mysql-1", res.Ordinals[1].Name)
+	assert.False(t, res.Ordinals[2].Ready)
+	assert.False(t, res.Ordinals[2].Updated)
+}
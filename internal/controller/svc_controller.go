@@ -2,35 +2,30 @@ package controller
 
 import (
 	"context"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
 	"github.com/xiaofan193/k8sadmin/internal/pkg/maputils"
+	"github.com/xiaofan193/k8sadmin/internal/types"
 	"github.com/xiaofan193/k8sadmin/internal/types/svc"
 	"github.com/xiaofan193/k8sadmin/pkg/global"
 	corve1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
-	"strings"
-	"sync"
-)
-
-var (
-	svcInstance       *SvcController
-	svcControllerOnce sync.Once
 )
 
 type SvcController struct {
 	KubeConfigSet *kubernetes.Clientset
+	Cache         *cluster.Cache
 	CONF          *global.Server
 }
 
-func NewSvcController() *SvcController {
-	svcControllerOnce.Do(func() {
-		svcInstance = &SvcController{
-			KubeConfigSet: global.GlobalKubeConfigSet,
-			CONF:          global.CONF,
-		}
-	})
-	return svcInstance
+func NewSvcController(kubeConfigSet *kubernetes.Clientset, cache *cluster.Cache) *SvcController {
+	return &SvcController{
+		KubeConfigSet: kubeConfigSet,
+		Cache:         cache,
+		CONF:          global.CONF,
+	}
 }
 
 func (s *SvcController) CreateOrUpdateSvc(ctx context.Context, reqParam *svc.CreateorUpdateServiceRequest) error {
@@ -98,28 +93,34 @@ func (s *SvcController) GetSvcDetail(ctx context.Context, namespace string, name
 	return svcRes, err
 }
 
-func (s *SvcController) GetSvcList(ctx context.Context, namespace string, keyword string) ([]*svc.ServiceRes, error) {
-	list, err := s.KubeConfigSet.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+func (s *SvcController) GetSvcList(ctx context.Context, namespace string, query *types.ListQuery) ([]*svc.ServiceRes, *types.ListMeta, error) {
+	items, err := listServices(ctx, s.KubeConfigSet, s.Cache, namespace)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	items, listMeta, err := listquery.Apply(items, query)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	serverList := make([]*svc.ServiceRes, 0)
-	for _, item := range list.Items {
-		if !strings.Contains(item.Name, keyword) {
-			continue
-		}
-		serverList = append(serverList, &svc.ServiceRes{
-			Name:       item.Name,
-			Namespace:  item.Namespace,
-			Type:       item.Spec.Type,
-			ClusterIp:  item.Spec.ClusterIP,
-			ExternalIp: item.Spec.ExternalIPs,
-			Age:        item.CreationTimestamp.Unix(),
-		})
+	for i := range items {
+		serverList = append(serverList, svcListItem(&items[i]))
+	}
+	return serverList, listMeta, nil
+}
+
+// svcListItem 列表与watch接口共用的转换
+func svcListItem(item *corve1.Service) *svc.ServiceRes {
+	return &svc.ServiceRes{
+		Name:       item.Name,
+		Namespace:  item.Namespace,
+		Type:       item.Spec.Type,
+		ClusterIp:  item.Spec.ClusterIP,
+		ExternalIp: item.Spec.ExternalIPs,
+		Age:        item.CreationTimestamp.Unix(),
 	}
-	return serverList, nil
 }
 
 func (s *SvcController) DeleteSvc(ctx context.Context, namespace string, name string) error {
@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/node"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/pod"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+// 支持watch的资源类型
+const (
+	WatchKindPod        = "pod"
+	WatchKindDeployment = "deployment"
+	WatchKindDaemonSet  = "daemonset"
+	WatchKindNode       = "node"
+	WatchKindService    = "svc"
+	WatchKindIngress    = "ingress"
+)
+
+// ErrWatchKind 不支持watch的资源类型
+var ErrWatchKind = errors.New("不支持watch的资源类型")
+
+type watchFunc func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+
+type listFunc func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error)
+
+type WatchController struct {
+	KubeConfigSet kubernetes.Interface
+}
+
+func NewWatchController(kubeConfigSet kubernetes.Interface) *WatchController {
+	return &WatchController{
+		KubeConfigSet: kubeConfigSet,
+	}
+}
+
+// Watch 建立到apiserver的watch, 事件转换为与列表接口一致的结构后写入返回的channel
+// 未指定 resourceVersion 时先列出当前所有对象推送 ADDED 事件, 再推送列表版本的 BOOKMARK 事件, 之后从列表版本开始watch
+// apiserver 主动关闭watch时从最后收到的 resourceVersion 继续, 出现错误时推送 ERROR 事件后关闭channel
+// namespace 为空表示所有命名空间, node 忽略 namespace
+func (w *WatchController) Watch(ctx context.Context, kind, namespace string, req *types.WatchRequest) (<-chan *types.WatchEvent, error) {
+	list, fn, err := w.resourceFuncs(kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+	opts := metav1.ListOptions{
+		LabelSelector:       req.LabelSelector,
+		FieldSelector:       req.FieldSelector,
+		ResourceVersion:     req.ResourceVersion,
+		AllowWatchBookmarks: true,
+	}
+
+	// 从空版本重新watch时apiserver会把所有对象作为 ADDED 再推送一遍, 需要记录列表的版本用于断线后继续
+	var initial []runtime.Object
+	if opts.ResourceVersion == "" {
+		initial, opts.ResourceVersion, err = listObjects(ctx, list, metav1.ListOptions{LabelSelector: req.LabelSelector, FieldSelector: req.FieldSelector})
+		if err != nil {
+			return nil, err
+		}
+	}
+	watcher, err := fn(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *types.WatchEvent)
+	go func() {
+		defer close(events)
+		if !sendInitialEvents(ctx, kind, initial, opts.ResourceVersion, events) {
+			watcher.Stop()
+			return
+		}
+		for {
+			rv, err := consumeWatch(ctx, watcher, kind, opts.ResourceVersion, events)
+			watcher.Stop()
+			if err != nil {
+				sendWatchEvent(ctx, events, watchErrorEvent(kind, rv, err))
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			opts.ResourceVersion = rv
+			if watcher, err = fn(ctx, opts); err != nil {
+				sendWatchEvent(ctx, events, watchErrorEvent(kind, rv, err))
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (w *WatchController) resourceFuncs(kind, namespace string) (listFunc, watchFunc, error) {
+	switch kind {
+	case WatchKindPod:
+		client := w.KubeConfigSet.CoreV1().Pods(namespace)
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.List(ctx, opts)
+		}, client.Watch, nil
+	case WatchKindDeployment:
+		client := w.KubeConfigSet.AppsV1().Deployments(namespace)
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.List(ctx, opts)
+		}, client.Watch, nil
+	case WatchKindDaemonSet:
+		client := w.KubeConfigSet.AppsV1().DaemonSets(namespace)
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.List(ctx, opts)
+		}, client.Watch, nil
+	case WatchKindNode:
+		client := w.KubeConfigSet.CoreV1().Nodes()
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.List(ctx, opts)
+		}, client.Watch, nil
+	case WatchKindService:
+		client := w.KubeConfigSet.CoreV1().Services(namespace)
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.List(ctx, opts)
+		}, client.Watch, nil
+	case WatchKindIngress:
+		client := w.KubeConfigSet.NetworkingV1().Ingresses(namespace)
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.List(ctx, opts)
+		}, client.Watch, nil
+	}
+	return nil, nil, fmt.Errorf("%w: %s", ErrWatchKind, kind)
+}
+
+// listObjects 返回当前所有对象和列表的 resourceVersion
+func listObjects(ctx context.Context, list listFunc, opts metav1.ListOptions) ([]runtime.Object, string, error) {
+	obj, err := list(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	listMeta, err := meta.ListAccessor(obj)
+	if err != nil {
+		return nil, "", err
+	}
+	items, err := meta.ExtractList(obj)
+	if err != nil {
+		return nil, "", err
+	}
+	return items, listMeta.GetResourceVersion(), nil
+}
+
+// sendInitialEvents 推送列表中的对象, 最后的 BOOKMARK 事件让客户端从列表版本断线重连
+// items 为nil表示请求指定了 resourceVersion, 没有列出对象
+func sendInitialEvents(ctx context.Context, kind string, items []runtime.Object, rv string, events chan<- *types.WatchEvent) bool {
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			continue
+		}
+		event := &types.WatchEvent{
+			Type:            string(watch.Added),
+			Kind:            kind,
+			ResourceVersion: accessor.GetResourceVersion(),
+			Object:          convertWatchObject(item),
+		}
+		if !sendWatchEvent(ctx, events, event) {
+			return false
+		}
+	}
+	if items == nil || rv == "" {
+		return true
+	}
+	return sendWatchEvent(ctx, events, &types.WatchEvent{Type: string(watch.Bookmark), Kind: kind, ResourceVersion: rv})
+}
+
+// consumeWatch 读取事件直到watch被关闭, 返回最后一个 resourceVersion
+func consumeWatch(ctx context.Context, watcher watch.Interface, kind, rv string, events chan<- *types.WatchEvent) (string, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return rv, nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return rv, nil
+			}
+			if event.Type == watch.Error {
+				return rv, apierrors.FromObject(event.Object)
+			}
+			accessor, err := meta.Accessor(event.Object)
+			if err != nil {
+				return rv, err
+			}
+			rv = accessor.GetResourceVersion()
+			item := &types.WatchEvent{
+				Type:            string(event.Type),
+				Kind:            kind,
+				ResourceVersion: rv,
+			}
+			if event.Type != watch.Bookmark {
+				item.Object = convertWatchObject(event.Object)
+			}
+			if !sendWatchEvent(ctx, events, item) {
+				return rv, nil
+			}
+		}
+	}
+}
+
+// convertWatchObject 复用列表接口的转换
+func convertWatchObject(obj runtime.Object) interface{} {
+	switch item := obj.(type) {
+	case *corev1.Pod:
+		k8s2req := pod.K8s2ReqConvert{}
+		return k8s2req.PodK8s2ItemRes(*item)
+	case *corev1.Node:
+		nodeConvert := node.NodeK8s2Res{}
+		return nodeConvert.GetNodeResItem(item)
+	case *appsv1.Deployment:
+		return deploymentListItem(item)
+	case *appsv1.DaemonSet:
+		return daemonsetListItem(item)
+	case *corev1.Service:
+		return svcListItem(item)
+	case *networkingv1.Ingress:
+		return ingressListItem(item)
+	}
+	return obj
+}
+
+func sendWatchEvent(ctx context.Context, events chan<- *types.WatchEvent, event *types.WatchEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func watchErrorEvent(kind, rv string, err error) *types.WatchEvent {
+	watchErr := &types.WatchError{Code: http.StatusInternalServerError, Message: err.Error()}
+	if status, ok := err.(apierrors.APIStatus); ok {
+		watchErr.Code = status.Status().Code
+	}
+	if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		watchErr.Code = http.StatusGone
+	}
+	return &types.WatchEvent{
+		Type:            string(watch.Error),
+		Kind:            kind,
+		ResourceVersion: rv,
+		Object:          watchErr,
+	}
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+func TestWatch(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := NewWatchController(clientset).Watch(ctx, WatchKindPod, "default", &types.WatchRequest{})
+	assert.NoError(t, err)
+
+	_, err = clientset.CoreV1().Pods("default").Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", ResourceVersion: "10"},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, "ADDED", event.Type)
+		assert.Equal(t, "10", event.ResourceVersion)
+		item, ok := event.Object.(types.PodListItem)
+		assert.True(t, ok)
+		assert.Equal(t, "nginx", item.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no watch event")
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func receiveWatchEvent(t *testing.T, events <-chan *types.WatchEvent) *types.WatchEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no watch event")
+	}
+	return nil
+}
+
+func TestWatchResumeFromList(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", ResourceVersion: "5"},
+	})
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &corev1.PodList{
+			ListMeta: metav1.ListMeta{ResourceVersion: "7"},
+			Items:    []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", ResourceVersion: "5"}}},
+		}, nil
+	})
+	watchers := make(chan *watch.FakeWatcher, 2)
+	var resourceVersions []string
+	clientset.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		resourceVersions = append(resourceVersions, action.(k8stesting.WatchAction).GetWatchRestrictions().ResourceVersion)
+		watcher := watch.NewFake()
+		watchers <- watcher
+		return true, watcher, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := NewWatchController(clientset).Watch(ctx, WatchKindPod, "default", &types.WatchRequest{})
+	assert.NoError(t, err)
+
+	// 先推送列表中的对象, 再推送列表版本的 BOOKMARK
+	event := receiveWatchEvent(t, events)
+	assert.Equal(t, "ADDED", event.Type)
+	assert.Equal(t, "5", event.ResourceVersion)
+	event = receiveWatchEvent(t, events)
+	assert.Equal(t, "BOOKMARK", event.Type)
+	assert.Equal(t, "7", event.ResourceVersion)
+
+	// apiserver 关闭watch后从列表版本继续, 不会重新推送所有对象
+	(<-watchers).Stop()
+	second := <-watchers
+	assert.Equal(t, []string{"7", "7"}, resourceVersions)
+
+	second.Modify(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", ResourceVersion: "9"}})
+	event = receiveWatchEvent(t, events)
+	assert.Equal(t, "MODIFIED", event.Type)
+	assert.Equal(t, "9", event.ResourceVersion)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchKind(t *testing.T) {
+	_, err := NewWatchController(fake.NewSimpleClientset()).Watch(context.Background(), "configmap", "", &types.WatchRequest{})
+	assert.ErrorIs(t, err, ErrWatchKind)
+}
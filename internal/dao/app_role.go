@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+var _ AppRoleDao = (*appRoleDao)(nil)
+
+// AppRoleDao defining the dao interface
+type AppRoleDao interface {
+	Create(ctx context.Context, table *model.AppRole) error
+	UpdateByID(ctx context.Context, table *model.AppRole) error
+	DeleteByID(ctx context.Context, id uint64) error
+	GetByID(ctx context.Context, id uint64) (*model.AppRole, error)
+	GetByName(ctx context.Context, name string) (*model.AppRole, error)
+	GetAll(ctx context.Context) ([]*model.AppRole, error)
+	GetByUserID(ctx context.Context, userID uint64) ([]*model.AppRole, error)
+}
+
+type appRoleDao struct {
+	db *gorm.DB
+}
+
+// NewAppRoleDao creating the dao interface
+func NewAppRoleDao(db *gorm.DB) AppRoleDao {
+	return &appRoleDao{db: db}
+}
+
+// Create a new role, insert the record and the id value is written back to the table
+func (d *appRoleDao) Create(ctx context.Context, table *model.AppRole) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// UpdateByID update name, description and rules of a role, rules are always replaced
+func (d *appRoleDao) UpdateByID(ctx context.Context, table *model.AppRole) error {
+	if table.ID < 1 {
+		return errors.New("id cannot be 0")
+	}
+	return d.db.WithContext(ctx).Model(table).Select("name", "description", "rules").Updates(table).Error
+}
+
+// DeleteByID delete a role and its bindings
+func (d *appRoleDao) DeleteByID(ctx context.Context, id uint64) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&model.AppRoleBinding{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.AppRole{}).Error
+	})
+}
+
+// GetByID get a role by id
+func (d *appRoleDao) GetByID(ctx context.Context, id uint64) (*model.AppRole, error) {
+	record := &model.AppRole{}
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
+	return record, err
+}
+
+// GetByName get a role by name
+func (d *appRoleDao) GetByName(ctx context.Context, name string) (*model.AppRole, error) {
+	record := &model.AppRole{}
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(record).Error
+	return record, err
+}
+
+// GetAll get all roles, the number of roles is small so no paging is needed
+func (d *appRoleDao) GetAll(ctx context.Context) ([]*model.AppRole, error) {
+	records := []*model.AppRole{}
+	err := d.db.WithContext(ctx).Order("id asc").Find(&records).Error
+	return records, err
+}
+
+// GetByUserID get the roles bound to the user directly or through the teams the user belongs to
+func (d *appRoleDao) GetByUserID(ctx context.Context, userID uint64) ([]*model.AppRole, error) {
+	db := d.db.WithContext(ctx)
+	teamIDs := db.Model(&model.TeamMember{}).Select("team_id").Where("user_id = ?", userID)
+	roleIDs := db.Model(&model.AppRoleBinding{}).Select("role_id").Where("user_id = ? OR team_id IN (?)", userID, teamIDs)
+	records := []*model.AppRole{}
+	err := db.Where("id IN (?)", roleIDs).Order("id asc").Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+var _ AppRoleBindingDao = (*appRoleBindingDao)(nil)
+
+// AppRoleBindingDao defining the dao interface
+type AppRoleBindingDao interface {
+	Create(ctx context.Context, table *model.AppRoleBinding) error
+	DeleteByID(ctx context.Context, id uint64) error
+	GetAll(ctx context.Context) ([]*model.AppRoleBinding, error)
+}
+
+type appRoleBindingDao struct {
+	db *gorm.DB
+}
+
+// NewAppRoleBindingDao creating the dao interface
+func NewAppRoleBindingDao(db *gorm.DB) AppRoleBindingDao {
+	return &appRoleBindingDao{db: db}
+}
+
+// Create a new binding, insert the record and the id value is written back to the table
+func (d *appRoleBindingDao) Create(ctx context.Context, table *model.AppRoleBinding) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete a binding by id
+func (d *appRoleBindingDao) DeleteByID(ctx context.Context, id uint64) error {
+	return d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.AppRoleBinding{}).Error
+}
+
+// GetAll get all bindings
+func (d *appRoleBindingDao) GetAll(ctx context.Context) ([]*model.AppRoleBinding, error) {
+	records := []*model.AppRoleBinding{}
+	err := d.db.WithContext(ctx).Order("id asc").Find(&records).Error
+	return records, err
+}
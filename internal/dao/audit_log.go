@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+var _ AuditLogDao = (*auditLogDao)(nil)
+
+// AuditLogFilter 审计日志的查询条件, 空值表示不过滤, 时间范围为 [Start, End)
+type AuditLogFilter struct {
+	Username  string
+	Cluster   string
+	Namespace string
+	Kind      string
+	Name      string
+	Verb      string
+	Result    string
+	RequestID string
+	Start     time.Time
+	End       time.Time
+}
+
+// AuditLogDao defining the dao interface
+type AuditLogDao interface {
+	Create(ctx context.Context, table *model.AuditLog) error
+	List(ctx context.Context, filter *AuditLogFilter, offset int, limit int) ([]*model.AuditLog, int64, error)
+}
+
+type auditLogDao struct {
+	db *gorm.DB
+}
+
+// NewAuditLogDao creating the dao interface
+func NewAuditLogDao(db *gorm.DB) AuditLogDao {
+	return &auditLogDao{db: db}
+}
+
+// Create a new audit log, insert the record and the id value is written back to the table
+func (d *auditLogDao) Create(ctx context.Context, table *model.AuditLog) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// List get audit logs by filter, order by id desc, return the total number of matched records
+func (d *auditLogDao) List(ctx context.Context, filter *AuditLogFilter, offset int, limit int) ([]*model.AuditLog, int64, error) {
+	db := d.db.WithContext(ctx).Model(&model.AuditLog{})
+	conditions := []struct {
+		column string
+		value  string
+	}{
+		{"username", filter.Username},
+		{"cluster", filter.Cluster},
+		{"namespace", filter.Namespace},
+		{"kind", filter.Kind},
+		{"name", filter.Name},
+		{"verb", filter.Verb},
+		{"result", filter.Result},
+		{"request_id", filter.RequestID},
+	}
+	for _, condition := range conditions {
+		if condition.value != "" {
+			db = db.Where(condition.column+" = ?", condition.value)
+		}
+	}
+	if !filter.Start.IsZero() {
+		db = db.Where("created_at >= ?", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		db = db.Where("created_at < ?", filter.End)
+	}
+
+	var total int64
+	err := db.Count(&total).Error
+	if err != nil || total == 0 {
+		return []*model.AuditLog{}, total, err
+	}
+	records := []*model.AuditLog{}
+	err = db.Order("id desc").Offset(offset).Limit(limit).Find(&records).Error
+	return records, total, err
+}
@@ -0,0 +1,60 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+var _ ClusterDao = (*clusterDao)(nil)
+
+// ClusterDao defining the dao interface
+type ClusterDao interface {
+	Create(ctx context.Context, table *model.Cluster) error
+	DeleteByID(ctx context.Context, id uint64) error
+	GetByID(ctx context.Context, id uint64) (*model.Cluster, error)
+	GetByName(ctx context.Context, name string) (*model.Cluster, error)
+	GetAll(ctx context.Context) ([]*model.Cluster, error)
+}
+
+type clusterDao struct {
+	db *gorm.DB
+}
+
+// NewClusterDao creating the dao interface
+func NewClusterDao(db *gorm.DB) ClusterDao {
+	return &clusterDao{db: db}
+}
+
+// Create a new cluster, insert the record and the id value is written back to the table
+func (d *clusterDao) Create(ctx context.Context, table *model.Cluster) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete a cluster by id
+func (d *clusterDao) DeleteByID(ctx context.Context, id uint64) error {
+	return d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Cluster{}).Error
+}
+
+// GetByID get a cluster by id
+func (d *clusterDao) GetByID(ctx context.Context, id uint64) (*model.Cluster, error) {
+	record := &model.Cluster{}
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
+	return record, err
+}
+
+// GetByName get a cluster by name
+func (d *clusterDao) GetByName(ctx context.Context, name string) (*model.Cluster, error) {
+	record := &model.Cluster{}
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(record).Error
+	return record, err
+}
+
+// GetAll get all registered clusters, the number of clusters is small so no paging is needed
+func (d *clusterDao) GetAll(ctx context.Context) ([]*model.Cluster, error) {
+	records := []*model.Cluster{}
+	err := d.db.WithContext(ctx).Order("id asc").Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,40 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+var _ ExecSessionDao = (*execSessionDao)(nil)
+
+// ExecSessionDao defining the dao interface
+type ExecSessionDao interface {
+	Create(ctx context.Context, table *model.ExecSession) error
+	UpdateByID(ctx context.Context, table *model.ExecSession) error
+}
+
+type execSessionDao struct {
+	db *gorm.DB
+}
+
+// NewExecSessionDao creating the dao interface
+func NewExecSessionDao(db *gorm.DB) ExecSessionDao {
+	return &execSessionDao{db: db}
+}
+
+// Create a new exec session record when the terminal is opened
+func (d *execSessionDao) Create(ctx context.Context, table *model.ExecSession) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// UpdateByID record the end of the session
+func (d *execSessionDao) UpdateByID(ctx context.Context, table *model.ExecSession) error {
+	return d.db.WithContext(ctx).Model(table).Where("id = ?", table.ID).Updates(map[string]interface{}{
+		"error":      table.Error,
+		"transcript": table.Transcript,
+		"ended_at":   table.EndedAt,
+	}).Error
+}
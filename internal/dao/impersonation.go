@@ -0,0 +1,60 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+var _ ImpersonationDao = (*impersonationDao)(nil)
+
+// ImpersonationDao defining the dao interface
+type ImpersonationDao interface {
+	Create(ctx context.Context, table *model.Impersonation) error
+	DeleteByID(ctx context.Context, id uint64) error
+	GetByUserCluster(ctx context.Context, userID uint64, cluster string) (*model.Impersonation, error)
+	GetByUserID(ctx context.Context, userID uint64) ([]*model.Impersonation, error)
+	GetAll(ctx context.Context) ([]*model.Impersonation, error)
+}
+
+type impersonationDao struct {
+	db *gorm.DB
+}
+
+// NewImpersonationDao creating the dao interface
+func NewImpersonationDao(db *gorm.DB) ImpersonationDao {
+	return &impersonationDao{db: db}
+}
+
+// Create a new impersonation, insert the record and the id value is written back to the table
+func (d *impersonationDao) Create(ctx context.Context, table *model.Impersonation) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete an impersonation by id
+func (d *impersonationDao) DeleteByID(ctx context.Context, id uint64) error {
+	return d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Impersonation{}).Error
+}
+
+// GetByUserCluster get the impersonation of a user in a cluster, cluster * is not expanded
+func (d *impersonationDao) GetByUserCluster(ctx context.Context, userID uint64, cluster string) (*model.Impersonation, error) {
+	record := &model.Impersonation{}
+	err := d.db.WithContext(ctx).Where("user_id = ? AND cluster = ?", userID, cluster).First(record).Error
+	return record, err
+}
+
+// GetByUserID get all impersonations of a user
+func (d *impersonationDao) GetByUserID(ctx context.Context, userID uint64) ([]*model.Impersonation, error) {
+	records := []*model.Impersonation{}
+	err := d.db.WithContext(ctx).Where("user_id = ?", userID).Order("id asc").Find(&records).Error
+	return records, err
+}
+
+// GetAll get all impersonations
+func (d *impersonationDao) GetAll(ctx context.Context) ([]*model.Impersonation, error) {
+	records := []*model.Impersonation{}
+	err := d.db.WithContext(ctx).Order("id asc").Find(&records).Error
+	return records, err
+}
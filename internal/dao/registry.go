@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+var _ RegistryDao = (*registryDao)(nil)
+
+// RegistryDao defining the dao interface
+type RegistryDao interface {
+	Create(ctx context.Context, table *model.Registry) error
+	DeleteByID(ctx context.Context, id uint64) error
+	UpdateByID(ctx context.Context, table *model.Registry) error
+	GetByID(ctx context.Context, id uint64) (*model.Registry, error)
+	GetByName(ctx context.Context, name string) (*model.Registry, error)
+	GetAll(ctx context.Context) ([]*model.Registry, error)
+}
+
+type registryDao struct {
+	db *gorm.DB
+}
+
+// NewRegistryDao creating the dao interface
+func NewRegistryDao(db *gorm.DB) RegistryDao {
+	return &registryDao{db: db}
+}
+
+// Create a new registry, insert the record and the id value is written back to the table
+func (d *registryDao) Create(ctx context.Context, table *model.Registry) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete a registry by id
+func (d *registryDao) DeleteByID(ctx context.Context, id uint64) error {
+	return d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Registry{}).Error
+}
+
+// UpdateByID update a registry by id, empty fields are not modified
+func (d *registryDao) UpdateByID(ctx context.Context, table *model.Registry) error {
+	if table.ID < 1 {
+		return errors.New("id cannot be 0")
+	}
+
+	update := map[string]interface{}{}
+
+	if table.Server != "" {
+		update["server"] = table.Server
+	}
+	if table.Username != "" {
+		update["username"] = table.Username
+	}
+	if table.Password != "" {
+		update["password"] = table.Password
+	}
+
+	return d.db.WithContext(ctx).Model(table).Updates(update).Error
+}
+
+// GetByID get a registry by id
+func (d *registryDao) GetByID(ctx context.Context, id uint64) (*model.Registry, error) {
+	record := &model.Registry{}
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
+	return record, err
+}
+
+// GetByName get a registry by name
+func (d *registryDao) GetByName(ctx context.Context, name string) (*model.Registry, error) {
+	record := &model.Registry{}
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(record).Error
+	return record, err
+}
+
+// GetAll get all registries, the number of registries is small so no paging is needed
+func (d *registryDao) GetAll(ctx context.Context) ([]*model.Registry, error) {
+	records := []*model.Registry{}
+	err := d.db.WithContext(ctx).Order("id asc").Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+var _ TeamDao = (*teamDao)(nil)
+
+// TeamDao defining the dao interface
+type TeamDao interface {
+	Create(ctx context.Context, table *model.Team) error
+	DeleteByID(ctx context.Context, id uint64) error
+	GetByID(ctx context.Context, id uint64) (*model.Team, error)
+	GetByName(ctx context.Context, name string) (*model.Team, error)
+	GetAll(ctx context.Context) ([]*model.Team, error)
+	GetMembers(ctx context.Context) ([]*model.TeamMember, error)
+	SetMembers(ctx context.Context, teamID uint64, userIDs []uint64) error
+}
+
+type teamDao struct {
+	db *gorm.DB
+}
+
+// NewTeamDao creating the dao interface
+func NewTeamDao(db *gorm.DB) TeamDao {
+	return &teamDao{db: db}
+}
+
+// Create a new team, insert the record and the id value is written back to the table
+func (d *teamDao) Create(ctx context.Context, table *model.Team) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete a team with its members and bindings
+func (d *teamDao) DeleteByID(ctx context.Context, id uint64) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_id = ?", id).Delete(&model.TeamMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", id).Delete(&model.AppRoleBinding{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.Team{}).Error
+	})
+}
+
+// GetByID get a team by id
+func (d *teamDao) GetByID(ctx context.Context, id uint64) (*model.Team, error) {
+	record := &model.Team{}
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
+	return record, err
+}
+
+// GetByName get a team by name
+func (d *teamDao) GetByName(ctx context.Context, name string) (*model.Team, error) {
+	record := &model.Team{}
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(record).Error
+	return record, err
+}
+
+// GetAll get all teams, the number of teams is small so no paging is needed
+func (d *teamDao) GetAll(ctx context.Context) ([]*model.Team, error) {
+	records := []*model.Team{}
+	err := d.db.WithContext(ctx).Order("id asc").Find(&records).Error
+	return records, err
+}
+
+// GetMembers get the members of all teams
+func (d *teamDao) GetMembers(ctx context.Context) ([]*model.TeamMember, error) {
+	records := []*model.TeamMember{}
+	err := d.db.WithContext(ctx).Order("id asc").Find(&records).Error
+	return records, err
+}
+
+// SetMembers replace the members of a team
+func (d *teamDao) SetMembers(ctx context.Context, teamID uint64, userIDs []uint64) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_id = ?", teamID).Delete(&model.TeamMember{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		members := make([]*model.TeamMember, 0, len(userIDs))
+		for _, userID := range userIDs {
+			members = append(members, &model.TeamMember{TeamID: teamID, UserID: userID})
+		}
+		return tx.Create(&members).Error
+	})
+}
@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"errors"
+	"time"
 
 	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
@@ -24,6 +25,7 @@ type UserDao interface {
 	DeleteByID(ctx context.Context, id uint64) error
 	UpdateByID(ctx context.Context, table *model.User) error
 	GetByID(ctx context.Context, id uint64) (*model.User, error)
+	GetByName(ctx context.Context, name string) (*model.User, error)
 	GetByColumns(ctx context.Context, params *query.Params) ([]*model.User, int64, error)
 
 	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.User) (uint64, error)
@@ -94,10 +96,33 @@ func (d *userDao) updateDataByID(ctx context.Context, db *gorm.DB, table *model.
 	if table.Name != "" {
 		update["name"] = table.Name
 	}
+	if table.Password != "" {
+		update["password"] = table.Password
+		// 修改密码后不再要求修改, 之前签发的令牌全部失效; iat 精度为秒, 截断后与其比较
+		update["must_change_password"] = table.MustChangePassword
+		update["password_changed_at"] = time.Now().Truncate(time.Second)
+	}
+	if table.Status != 0 {
+		update["status"] = table.Status
+	}
+	if table.Type != 0 {
+		update["type"] = table.Type
+	}
+	if table.LastLoginAt != nil {
+		update["last_login_at"] = table.LastLoginAt
+		update["last_login_ip"] = table.LastLoginIP
+	}
 
 	return db.WithContext(ctx).Model(table).Updates(update).Error
 }
 
+// GetByName get a user by name, used for login, not cached
+func (d *userDao) GetByName(ctx context.Context, name string) (*model.User, error) {
+	record := &model.User{}
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(record).Error
+	return record, err
+}
+
 // GetByID get a user by id
 func (d *userDao) GetByID(ctx context.Context, id uint64) (*model.User, error) {
 	// no cache
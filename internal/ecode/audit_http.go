@@ -0,0 +1,18 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// audit business-level http error codes.
+// the auditNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	auditNO       = 32
+	auditName     = "audit log"
+	auditBaseCode = errcode.HCode(auditNO)
+
+	ErrListAuditLog   = errcode.NewError(auditBaseCode+1, "failed to list of "+auditName)
+	ErrExportAuditLog = errcode.NewError(auditBaseCode+2, "failed to export "+auditName)
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
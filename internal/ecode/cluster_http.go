@@ -0,0 +1,21 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// cluster business-level http error codes.
+// the clusterNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	clusterNO       = 24
+	clusterName     = "cluster"
+	clusterBaseCode = errcode.HCode(clusterNO)
+
+	ErrCreateCluster     = errcode.NewError(clusterBaseCode+1, "failed to create "+clusterName)
+	ErrDeleteByIDCluster = errcode.NewError(clusterBaseCode+2, "failed to delete "+clusterName)
+	ErrListCluster       = errcode.NewError(clusterBaseCode+3, "failed to list of "+clusterName)
+	ErrConnectCluster    = errcode.NewError(clusterBaseCode+4, "failed to connect "+clusterName)
+	ErrExistCluster      = errcode.NewError(clusterBaseCode+5, clusterName+" already exists")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
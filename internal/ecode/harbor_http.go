@@ -0,0 +1,18 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// harbor business-level http error codes.
+// the harborNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	harborNO       = 28
+	harborName     = "harbor"
+	harborBaseCode = errcode.HCode(harborNO)
+
+	ErrHarborDisabled = errcode.NewError(harborBaseCode+1, harborName+" is not enabled")
+	ErrHarborRequest  = errcode.NewError(harborBaseCode+2, harborName+" request failed")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
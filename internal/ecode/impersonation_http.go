@@ -0,0 +1,22 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// impersonation business-level http error codes.
+// the impersonationNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	impersonationNO       = 31
+	impersonationName     = "impersonation"
+	impersonationBaseCode = errcode.HCode(impersonationNO)
+
+	ErrCreateImpersonation     = errcode.NewError(impersonationBaseCode+1, "failed to create "+impersonationName)
+	ErrDeleteByIDImpersonation = errcode.NewError(impersonationBaseCode+2, "failed to delete "+impersonationName)
+	ErrListImpersonation       = errcode.NewError(impersonationBaseCode+3, "failed to list of "+impersonationName)
+	ErrExistImpersonation      = errcode.NewError(impersonationBaseCode+4, impersonationName+" already exists for the user and cluster")
+	ErrLoadImpersonation       = errcode.NewError(impersonationBaseCode+5, "failed to load "+impersonationName)
+	ErrNoImpersonation         = errcode.NewError(impersonationBaseCode+6, "no kubernetes identity is mapped to the user")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
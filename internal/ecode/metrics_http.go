@@ -0,0 +1,18 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// metrics business-level http error codes.
+// the metricsNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	metricsNO       = 27
+	metricsName     = "metrics"
+	metricsBaseCode = errcode.HCode(metricsNO)
+
+	ErrPrometheusDisabled = errcode.NewError(metricsBaseCode+1, metricsName+" prometheus is not enabled")
+	ErrPrometheusQuery    = errcode.NewError(metricsBaseCode+2, metricsName+" prometheus query failed")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
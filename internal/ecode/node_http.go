@@ -0,0 +1,18 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// node business-level http error codes.
+// the nodeNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	nodeNO       = 26
+	nodeName     = "node"
+	nodeBaseCode = errcode.HCode(nodeNO)
+
+	ErrNodeDraining      = errcode.NewError(nodeBaseCode+1, nodeName+" is already being drained")
+	ErrDrainTaskNotFound = errcode.NewError(nodeBaseCode+2, nodeName+" drain task not found")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
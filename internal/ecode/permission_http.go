@@ -0,0 +1,32 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// permission business-level http error codes.
+// the permissionNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	permissionNO       = 30
+	permissionName     = "permission"
+	permissionBaseCode = errcode.HCode(permissionNO)
+
+	ErrPermissionDenied  = errcode.NewError(permissionBaseCode+1, permissionName+" denied")
+	ErrLoadPermission    = errcode.NewError(permissionBaseCode+2, "failed to load "+permissionName)
+	ErrAdminRequired     = errcode.NewError(permissionBaseCode+3, "administrator required")
+	ErrCreateRole        = errcode.NewError(permissionBaseCode+4, "failed to create role")
+	ErrUpdateRole        = errcode.NewError(permissionBaseCode+5, "failed to update role")
+	ErrDeleteRole        = errcode.NewError(permissionBaseCode+6, "failed to delete role")
+	ErrListRole          = errcode.NewError(permissionBaseCode+7, "failed to list of role")
+	ErrExistRole         = errcode.NewError(permissionBaseCode+8, "role already exists")
+	ErrCreateTeam        = errcode.NewError(permissionBaseCode+9, "failed to create team")
+	ErrDeleteTeam        = errcode.NewError(permissionBaseCode+10, "failed to delete team")
+	ErrListTeam          = errcode.NewError(permissionBaseCode+11, "failed to list of team")
+	ErrExistTeam         = errcode.NewError(permissionBaseCode+12, "team already exists")
+	ErrSetTeamMembers    = errcode.NewError(permissionBaseCode+13, "failed to set team members")
+	ErrCreateRoleBinding = errcode.NewError(permissionBaseCode+14, "failed to create role binding")
+	ErrDeleteRoleBinding = errcode.NewError(permissionBaseCode+15, "failed to delete role binding")
+	ErrListRoleBinding   = errcode.NewError(permissionBaseCode+16, "failed to list of role binding")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
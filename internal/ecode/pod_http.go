@@ -0,0 +1,18 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// pod business-level http error codes.
+// the podNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	podNO       = 25
+	podName     = "pod"
+	podBaseCode = errcode.HCode(podNO)
+
+	ErrRecreatePod          = errcode.NewError(podBaseCode+1, podName+" changes require recreate, set recreate=true to confirm")
+	ErrPodSecurityViolation = errcode.NewError(podBaseCode+2, podName+" violates the pod security standard")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
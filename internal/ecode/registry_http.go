@@ -0,0 +1,21 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// registry business-level http error codes.
+// the registryNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	registryNO       = 29
+	registryName     = "registry"
+	registryBaseCode = errcode.HCode(registryNO)
+
+	ErrCreateRegistry     = errcode.NewError(registryBaseCode+1, "failed to create "+registryName)
+	ErrDeleteByIDRegistry = errcode.NewError(registryBaseCode+2, "failed to delete "+registryName)
+	ErrListRegistry       = errcode.NewError(registryBaseCode+3, "failed to list of "+registryName)
+	ErrExistRegistry      = errcode.NewError(registryBaseCode+4, registryName+" already exists")
+	ErrUpdateByIDRegistry = errcode.NewError(registryBaseCode+5, "failed to update "+registryName)
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
@@ -16,6 +16,12 @@ var (
 	ErrUpdateByIDUser = errcode.NewError(userBaseCode+3, "failed to update "+userName)
 	ErrGetByIDUser    = errcode.NewError(userBaseCode+4, "failed to get "+userName+" details")
 	ErrListUser       = errcode.NewError(userBaseCode+5, "failed to list of "+userName)
+	ErrExistUser      = errcode.NewError(userBaseCode+6, userName+" already exists")
+	ErrLoginUser      = errcode.NewError(userBaseCode+7, "incorrect username or password")
+	ErrUserDisabled   = errcode.NewError(userBaseCode+8, userName+" is disabled")
+	ErrRefreshToken   = errcode.NewError(userBaseCode+9, "invalid or expired refresh token")
+	ErrLogoutUser     = errcode.NewError(userBaseCode+10, "failed to logout")
+	ErrOldPassword    = errcode.NewError(userBaseCode+11, "incorrect old password")
 
 	// error codes are globally unique, adding 1 to the previous error code
 )
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+const (
+	auditLogDefaultLimit = 20
+	// 单次最多导出的记录数
+	auditLogExportLimit = 10000
+)
+
+var auditLogCSVHeader = []string{"id", "createdAt", "requestID", "userID", "username", "cluster", "namespace", "kind", "name",
+	"verb", "method", "path", "clientIP", "result", "statusCode", "code", "message", "latency", "body"}
+
+var _ AuditLogHandler = (*auditLogHandler)(nil)
+
+// AuditLogHandler defining the handler interface
+type AuditLogHandler interface {
+	List(c *gin.Context)
+	Export(c *gin.Context)
+}
+
+type auditLogHandler struct {
+	iDao dao.AuditLogDao
+}
+
+// NewAuditLogHandler creating the handler interface
+func NewAuditLogHandler() AuditLogHandler {
+	return &auditLogHandler{
+		iDao: dao.NewAuditLogDao(database.GetDB()),
+	}
+}
+
+// List get audit logs by filters
+// @Summary Get audit logs of mutating operations on kubernetes resources
+// @Description Records are ordered by time desc, secrets in the request body are redacted.
+// @Tags audit
+// @Produce json
+// @Param query query types.ListAuditLogsRequest false "filters"
+// @Success 200 {object} types.ListAuditLogsReply{}
+// @Router /api/v1/audit/list [get]
+// @Security BearerAuth
+func (h *auditLogHandler) List(c *gin.Context) {
+	form := &types.ListAuditLogsRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Page == 0 {
+		form.Page = 1
+	}
+	if form.Limit == 0 {
+		form.Limit = auditLogDefaultLimit
+	}
+
+	ctx := middleware.WrapCtx(c)
+	records, total, err := h.iDao.List(ctx, auditLogFilter(&form.AuditLogFilter), (form.Page-1)*form.Limit, form.Limit)
+	if err != nil {
+		logger.Error("List error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrListAuditLog)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"auditLogs": convertAuditLogs(records),
+		"total":     total,
+	})
+}
+
+// Export export audit logs by filters
+// @Summary Export audit logs as csv or json file
+// @Description Export at most the latest 10000 records matching the filters.
+// @Tags audit
+// @Produce octet-stream
+// @Param query query types.ExportAuditLogsRequest false "filters"
+// @Success 200 {file} file
+// @Router /api/v1/audit/export [get]
+// @Security BearerAuth
+func (h *auditLogHandler) Export(c *gin.Context) {
+	form := &types.ExportAuditLogsRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Format == "" {
+		form.Format = "csv"
+	}
+
+	ctx := middleware.WrapCtx(c)
+	records, _, err := h.iDao.List(ctx, auditLogFilter(&form.AuditLogFilter), 0, auditLogExportLimit)
+	if err != nil {
+		logger.Error("List error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrExportAuditLog)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-%s.%s", time.Now().Format("20060102150405"), form.Format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if form.Format == "json" {
+		c.Header("Content-Type", "application/json")
+		err = json.NewEncoder(c.Writer).Encode(convertAuditLogs(records))
+	} else {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		err = writeAuditLogCSV(c, records)
+	}
+	if err != nil {
+		// 响应已经开始写入, 只记录日志
+		logger.Error("write audit logs error", logger.Err(err), middleware.GCtxRequestIDField(c))
+	}
+}
+
+func auditLogFilter(form *types.AuditLogFilter) *dao.AuditLogFilter {
+	filter := &dao.AuditLogFilter{
+		Username:  form.Username,
+		Cluster:   form.Cluster,
+		Namespace: form.Namespace,
+		Kind:      form.Kind,
+		Name:      form.Name,
+		Verb:      form.Verb,
+		Result:    form.Result,
+		RequestID: form.RequestID,
+	}
+	if form.Start > 0 {
+		filter.Start = time.Unix(form.Start, 0)
+	}
+	if form.End > 0 {
+		filter.End = time.Unix(form.End, 0)
+	}
+	return filter
+}
+
+func convertAuditLogs(records []*model.AuditLog) []*types.AuditLogObjDetail {
+	data := make([]*types.AuditLogObjDetail, 0, len(records))
+	for _, record := range records {
+		data = append(data, &types.AuditLogObjDetail{
+			ID:         record.ID,
+			RequestID:  record.RequestID,
+			UserID:     record.UserID,
+			Username:   record.Username,
+			Cluster:    record.Cluster,
+			Namespace:  record.Namespace,
+			Kind:       record.Kind,
+			Name:       record.Name,
+			Verb:       record.Verb,
+			Method:     record.Method,
+			Path:       record.Path,
+			ClientIP:   record.ClientIP,
+			Body:       record.Body,
+			Result:     record.Result,
+			StatusCode: record.StatusCode,
+			Code:       record.Code,
+			Message:    record.Message,
+			Latency:    record.Latency,
+			CreatedAt:  record.CreatedAt,
+		})
+	}
+	return data
+}
+
+func writeAuditLogCSV(c *gin.Context, records []*model.AuditLog) error {
+	w := csv.NewWriter(c.Writer)
+	if err := w.Write(auditLogCSVHeader); err != nil {
+		return err
+	}
+	for _, record := range records {
+		createdAt := ""
+		if record.CreatedAt != nil {
+			createdAt = record.CreatedAt.Format(time.RFC3339)
+		}
+		err := w.Write([]string{
+			strconv.FormatUint(record.ID, 10), createdAt, record.RequestID, strconv.FormatUint(record.UserID, 10), record.Username,
+			record.Cluster, record.Namespace, record.Kind, record.Name, record.Verb, record.Method, record.Path, record.ClientIP,
+			record.Result, strconv.Itoa(record.StatusCode), strconv.Itoa(record.Code), record.Message,
+			strconv.FormatInt(record.Latency, 10), record.Body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/cache"
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ AuthHandler = (*authHandler)(nil)
+
+// AuthHandler defining the handler interface
+type AuthHandler interface {
+	Login(c *gin.Context)
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
+	ChangePassword(c *gin.Context)
+}
+
+type authHandler struct {
+	iDao       dao.UserDao
+	tokenCache cache.TokenCache
+}
+
+// NewAuthHandler creating the handler interface
+func NewAuthHandler() AuthHandler {
+	return &authHandler{
+		iDao: dao.NewUserDao(
+			database.GetDB(), // db driver is mysql
+			cache.NewUserCache(database.GetCacheType()),
+		),
+		tokenCache: cache.NewTokenCache(database.GetCacheType()),
+	}
+}
+
+// Login 用户名密码登录
+// @Summary Login
+// @Description Returns an access token and a refresh token, the access token is carried in the Authorization header as "Bearer <accessToken>".
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body types.LoginRequest true "login information"
+// @Success 200 {object} types.LoginReply{}
+// @Router /api/v1/auth/login [post]
+func (h *authHandler) Login(c *gin.Context) {
+	form := &types.LoginRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	user, err := h.iDao.GetByName(ctx, form.Name)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			response.Error(c, ecode.ErrLoginUser)
+		} else {
+			logger.Error("GetByName error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+	// 用户不存在和密码错误返回相同的错误
+	if !auth.CheckPassword(user.Password, form.Password) {
+		logger.Warn("login failed", logger.String("name", form.Name), logger.String("ip", c.ClientIP()), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrLoginUser)
+		return
+	}
+	if user.Status == model.UserStatusDisabled {
+		response.Error(c, ecode.ErrUserDisabled)
+		return
+	}
+
+	tokens, err := auth.GenerateTokens(user)
+	if err != nil {
+		logger.Error("GenerateTokens error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	now := time.Now()
+	err = h.iDao.UpdateByID(ctx, &model.User{ID: user.ID, LastLoginAt: &now, LastLoginIP: c.ClientIP()})
+	if err != nil {
+		// 不影响登录
+		logger.Warn("update last login error", logger.Err(err), logger.Any("id", user.ID), middleware.GCtxRequestIDField(c))
+	} else {
+		user.LastLoginAt = &now
+		user.LastLoginIP = c.ClientIP()
+	}
+
+	data, err := convertUser(user)
+	if err != nil {
+		response.Error(c, ecode.ErrGetByIDUser)
+		return
+	}
+	response.Success(c, gin.H{
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresAt":    tokens.ExpiresAt,
+		"user":         data,
+	})
+}
+
+// Refresh 用 refresh token 换取新的 access token
+// @Summary Refresh access token
+// @Description The refresh token is returned unchanged, login again after it expires.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body types.RefreshTokenRequest true "refresh token"
+// @Success 200 {object} types.RefreshTokenReply{}
+// @Router /api/v1/auth/refresh [post]
+func (h *authHandler) Refresh(c *gin.Context) {
+	form := &types.RefreshTokenRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	claims, err := auth.ParseRefreshToken(form.RefreshToken)
+	if err != nil {
+		logger.Warn("ParseRefreshToken error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrRefreshToken)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	revoked, err := h.tokenCache.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		logger.Error("IsRevoked error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	if revoked {
+		response.Error(c, ecode.ErrRefreshToken)
+		return
+	}
+
+	uid, err := auth.UserID(claims)
+	if err != nil {
+		response.Error(c, ecode.ErrRefreshToken)
+		return
+	}
+	user, err := h.iDao.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			response.Error(c, ecode.ErrRefreshToken)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", uid), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+	if user.Status == model.UserStatusDisabled {
+		response.Error(c, ecode.ErrUserDisabled)
+		return
+	}
+	// 修改密码前签发的令牌不能刷新
+	if auth.IssuedBefore(claims, user.PasswordChangedAt) {
+		response.Error(c, ecode.ErrRefreshToken)
+		return
+	}
+
+	tokens, err := auth.RefreshTokens(claims, form.RefreshToken)
+	if err != nil {
+		logger.Error("RefreshTokens error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, &types.TokenObj{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+	})
+}
+
+// Logout 注销当前登录, access token 和 refresh token 同时失效
+// @Summary Logout
+// @Tags auth
+// @Produce json
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/auth/logout [post]
+// @Security BearerAuth
+func (h *authHandler) Logout(c *gin.Context) {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	// 两个令牌共用 jwt id, 保留到 refresh token 过期
+	err := h.tokenCache.Revoke(ctx, claims.ID, auth.RefreshExpire())
+	if err != nil {
+		logger.Error("Revoke error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrLogoutUser)
+		return
+	}
+
+	response.Success(c)
+}
+
+// ChangePassword 修改当前用户的密码, 使用一次性密码登录的用户必须先修改密码, 修改后已签发的令牌全部失效, 需要重新登录
+// @Summary Change password
+// @Description Changes the password of the current user, users created with a one-time password can only call this api and logout before changing it. All issued tokens are invalidated, login again afterwards.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body types.ChangePasswordRequest true "old and new password"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/auth/password [post]
+// @Security BearerAuth
+func (h *authHandler) ChangePassword(c *gin.Context) {
+	form := &types.ChangePasswordRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	user, ok := auth.CurrentUser(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+	if !auth.CheckPassword(user.Password, form.OldPassword) {
+		logger.Warn("change password failed", logger.String("name", user.Name), logger.String("ip", c.ClientIP()), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrOldPassword)
+		return
+	}
+
+	password, err := auth.HashPassword(form.NewPassword)
+	if err != nil {
+		logger.Error("HashPassword error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrUpdateByIDUser)
+		return
+	}
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.UpdateByID(ctx, &model.User{ID: user.ID, Password: password})
+	if err != nil {
+		logger.Error("UpdateByID error", logger.Err(err), logger.Any("id", user.ID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c)
+}
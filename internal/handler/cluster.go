@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ ClusterHandler = (*clusterHandler)(nil)
+
+// ClusterHandler defining the handler interface
+type ClusterHandler interface {
+	Create(c *gin.Context)
+	DeleteByID(c *gin.Context)
+	List(c *gin.Context)
+	Test(c *gin.Context)
+	TestByID(c *gin.Context)
+}
+
+type clusterHandler struct {
+	iDao dao.ClusterDao
+}
+
+// NewClusterHandler creating the handler interface
+func NewClusterHandler() ClusterHandler {
+	return &clusterHandler{
+		iDao: dao.NewClusterDao(
+			database.GetDB(), // db driver is mysql
+		),
+	}
+}
+
+// Create register a new cluster
+// @Summary Register a new cluster
+// @Description Tests the connectivity of the cluster, then stores the encrypted credentials and registers it.
+// @Tags cluster
+// @Accept json
+// @Produce json
+// @Param data body types.CreateClusterRequest true "cluster information"
+// @Success 200 {object} types.CreateClusterReply{}
+// @Router /api/v1/cluster [post]
+// @Security BearerAuth
+func (h *clusterHandler) Create(c *gin.Context) {
+	form := &types.CreateClusterRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Name == cluster.DefaultName {
+		response.Error(c, ecode.ErrExistCluster)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if _, err = h.iDao.GetByName(ctx, form.Name); err == nil {
+		response.Error(c, ecode.ErrExistCluster)
+		return
+	} else if !errors.Is(err, database.ErrRecordNotFound) {
+		logger.Error("GetByName error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	record, err := newClusterRecord(form)
+	if err != nil {
+		logger.Warn("newClusterRecord error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrCreateCluster, err.Error())
+		return
+	}
+	config, err := cluster.RestConfig(record)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+	if _, err = cluster.Ping(config); err != nil {
+		logger.Warn("Ping error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrConnectCluster, err.Error())
+		return
+	}
+
+	err = h.iDao.Create(ctx, record)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	if _, err = cluster.Register(record.Name, config); err != nil {
+		logger.Error("Register error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrCreateCluster, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"id": record.ID})
+}
+
+// DeleteByID remove a cluster by id
+// @Summary Remove a cluster by id
+// @Description Deletes the cluster record and unregisters it from the running server.
+// @Tags cluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.DeleteClusterByIDReply{}
+// @Router /api/v1/cluster/{id} [delete]
+// @Security BearerAuth
+func (h *clusterHandler) DeleteByID(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	record, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	err = h.iDao.DeleteByID(ctx, id)
+	if err != nil {
+		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDeleteByIDCluster)
+		return
+	}
+	cluster.Remove(record.Name)
+
+	response.Success(c)
+}
+
+// List get all clusters
+// @Summary Get all clusters
+// @Description Returns the default cluster and all registered clusters, credentials are not returned.
+// @Tags cluster
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.ListClustersReply{}
+// @Router /api/v1/cluster/list [get]
+// @Security BearerAuth
+func (h *clusterHandler) List(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	records, err := h.iDao.GetAll(ctx)
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrListCluster)
+		return
+	}
+
+	data := []*types.ClusterObjDetail{
+		{
+			Name:        cluster.DefaultName,
+			AuthType:    cluster.AuthTypeKubeConfig,
+			Connected:   isClusterConnected(cluster.DefaultName),
+			CacheSynced: isClusterCacheSynced(cluster.DefaultName),
+		},
+	}
+	for _, record := range records {
+		data = append(data, &types.ClusterObjDetail{
+			ID:          record.ID,
+			Name:        record.Name,
+			Description: record.Description,
+			AuthType:    record.AuthType,
+			Server:      record.Server,
+			Connected:   isClusterConnected(record.Name),
+			CacheSynced: isClusterCacheSynced(record.Name),
+			CreatedAt:   record.CreatedAt,
+			UpdatedAt:   record.UpdatedAt,
+		})
+	}
+
+	response.Success(c, gin.H{"clusters": data})
+}
+
+// Test the connectivity of the cluster credentials without saving them
+// @Summary Test cluster credentials
+// @Description Connects to the apiserver with the provided credentials and returns its version.
+// @Tags cluster
+// @Accept json
+// @Produce json
+// @Param data body types.CreateClusterRequest true "cluster information"
+// @Success 200 {object} types.TestClusterReply{}
+// @Router /api/v1/cluster/test [post]
+// @Security BearerAuth
+func (h *clusterHandler) Test(c *gin.Context) {
+	form := &types.CreateClusterRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	record, err := newClusterRecord(form)
+	if err != nil {
+		response.Error(c, ecode.ErrConnectCluster, err.Error())
+		return
+	}
+	config, err := cluster.RestConfig(record)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+	info, err := cluster.Ping(config)
+	if err != nil {
+		logger.Warn("Ping error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrConnectCluster, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"version": types.ClusterVersion{GitVersion: info.GitVersion, Platform: info.Platform}})
+}
+
+// TestByID test the connectivity of a registered cluster
+// @Summary Test a registered cluster
+// @Description Connects to the apiserver of the registered cluster and returns its version.
+// @Tags cluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.TestClusterReply{}
+// @Router /api/v1/cluster/{id}/test [get]
+// @Security BearerAuth
+func (h *clusterHandler) TestByID(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	record, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	config, err := cluster.RestConfig(record)
+	if err != nil {
+		logger.Error("RestConfig error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrConnectCluster, err.Error())
+		return
+	}
+	info, err := cluster.Ping(config)
+	if err != nil {
+		logger.Warn("Ping error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrConnectCluster, err.Error())
+		return
+	}
+	// reconnect the cluster if it failed to register at startup
+	if !isClusterConnected(record.Name) {
+		_, _ = cluster.Register(record.Name, config)
+	}
+
+	response.Success(c, gin.H{"version": types.ClusterVersion{GitVersion: info.GitVersion, Platform: info.Platform}})
+}
+
+// newClusterRecord validate the credentials and encrypt them
+func newClusterRecord(form *types.CreateClusterRequest) (*model.Cluster, error) {
+	record := &model.Cluster{
+		Name:        form.Name,
+		Description: form.Description,
+		AuthType:    form.AuthType,
+		Server:      form.Server,
+		Insecure:    form.Insecure,
+	}
+
+	var err error
+	switch form.AuthType {
+	case cluster.AuthTypeKubeConfig:
+		if form.KubeConfig == "" {
+			return nil, errors.New("kubeConfig 不能为空")
+		}
+		record.KubeConfig, err = cluster.Encrypt(form.KubeConfig)
+	case cluster.AuthTypeToken:
+		if form.Server == "" || form.Token == "" {
+			return nil, errors.New("server 和 token 不能为空")
+		}
+		if form.CAData == "" && !form.Insecure {
+			return nil, errors.New("caData 为空时需要开启 insecure")
+		}
+		if record.Token, err = cluster.Encrypt(form.Token); err != nil {
+			return nil, err
+		}
+		record.CAData, err = cluster.Encrypt(form.CAData)
+	default:
+		return nil, errors.New("不支持的认证类型: " + form.AuthType)
+	}
+
+	return record, err
+}
+
+func isClusterConnected(name string) bool {
+	_, err := cluster.Get(name)
+	return err == nil
+}
+
+func isClusterCacheSynced(name string) bool {
+	cls, err := cluster.Get(name)
+	return err == nil && cls.Cache.Synced()
+}
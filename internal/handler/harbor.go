@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+	"github.com/xiaofan193/k8sadmin/pkg/harbor"
+)
+
+var _ HarborHandler = (*harborHandler)(nil)
+
+// HarborHandler defining the handler interface
+type HarborHandler interface {
+	ListProjects(c *gin.Context)
+	ListRepositories(c *gin.Context)
+	ListArtifacts(c *gin.Context)
+	ListVulnerabilities(c *gin.Context)
+}
+
+type harborHandler struct {
+}
+
+// NewHarborHandler creating the handler interface
+func NewHarborHandler() HarborHandler {
+	return &harborHandler{}
+}
+
+// ListProjects list of harbor projects
+// @Summary List of harbor projects
+// @Description Lists the projects visible to the configured harbor account.
+// @Tags harbor
+// @Produce json
+// @Param query query types.HarborListRequest false "pagination"
+// @Success 200 {object} types.HarborProjectListReply{}
+// @Router /api/v1/harbor/project [get]
+// @Security BearerAuth
+func (h *harborHandler) ListProjects(c *gin.Context) {
+	form := &types.HarborListRequest{}
+	client, ok := bindHarborQuery(c, form)
+	if !ok {
+		return
+	}
+
+	projects, total, err := client.ListProjects(c.Request.Context(), harborListOptions(form))
+	if err != nil {
+		harborError(c, "ListProjects", err)
+		return
+	}
+	res := types.HarborProjectListReply{}
+	res.Data.List = make([]*types.HarborProject, 0, len(projects))
+	for _, project := range projects {
+		res.Data.List = append(res.Data.List, &types.HarborProject{
+			ProjectId:    project.ProjectID,
+			Name:         project.Name,
+			Public:       project.Public(),
+			RepoCount:    project.RepoCount,
+			CreationTime: unixTime(project.CreationTime),
+		})
+	}
+	res.Data.Total = total
+	response.Success(c, res.Data)
+}
+
+// ListRepositories list of repositories in a harbor project
+// @Summary List of repositories in a harbor project
+// @Tags harbor
+// @Produce json
+// @Param project path string true "project name"
+// @Param query query types.HarborListRequest false "pagination"
+// @Success 200 {object} types.HarborRepositoryListReply{}
+// @Router /api/v1/harbor/project/{project}/repository [get]
+// @Security BearerAuth
+func (h *harborHandler) ListRepositories(c *gin.Context) {
+	form := &types.HarborListRequest{}
+	client, ok := bindHarborQuery(c, form)
+	if !ok {
+		return
+	}
+
+	project := c.Param("project")
+	repositories, total, err := client.ListRepositories(c.Request.Context(), project, harborListOptions(form))
+	if err != nil {
+		harborError(c, "ListRepositories", err)
+		return
+	}
+	res := types.HarborRepositoryListReply{}
+	res.Data.List = make([]*types.HarborRepository, 0, len(repositories))
+	for _, repository := range repositories {
+		res.Data.List = append(res.Data.List, &types.HarborRepository{
+			Name:          strings.TrimPrefix(repository.Name, project+"/"),
+			FullName:      repository.Name,
+			Description:   repository.Description,
+			ArtifactCount: repository.ArtifactCount,
+			PullCount:     repository.PullCount,
+			UpdateTime:    unixTime(repository.UpdateTime),
+		})
+	}
+	res.Data.Total = total
+	response.Success(c, res.Data)
+}
+
+// ListArtifacts list of artifacts in a harbor repository
+// @Summary List of artifacts in a harbor repository
+// @Description Returns tags with push time, size, image address and vulnerability scan summary of each artifact.
+// @Tags harbor
+// @Produce json
+// @Param project path string true "project name"
+// @Param query query types.HarborArtifactRequest true "repository and pagination"
+// @Success 200 {object} types.HarborArtifactListReply{}
+// @Router /api/v1/harbor/project/{project}/artifact [get]
+// @Security BearerAuth
+func (h *harborHandler) ListArtifacts(c *gin.Context) {
+	form := &types.HarborArtifactRequest{}
+	client, ok := bindHarborQuery(c, form)
+	if !ok {
+		return
+	}
+
+	project := c.Param("project")
+	repository := project + "/" + strings.TrimPrefix(form.Repository, project+"/")
+	artifacts, total, err := client.ListArtifacts(c.Request.Context(), project, repository, harborListOptions(&form.HarborListRequest))
+	if err != nil {
+		harborError(c, "ListArtifacts", err)
+		return
+	}
+	res := types.HarborArtifactListReply{}
+	res.Data.List = make([]*types.HarborArtifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		res.Data.List = append(res.Data.List, harborArtifact(client, repository, artifact))
+	}
+	res.Data.Total = total
+	response.Success(c, res.Data)
+}
+
+// ListVulnerabilities list of vulnerabilities of a harbor artifact
+// @Summary List of vulnerabilities of a harbor artifact
+// @Tags harbor
+// @Produce json
+// @Param project path string true "project name"
+// @Param query query types.HarborVulnerabilityRequest true "repository and tag or digest"
+// @Success 200 {object} types.HarborVulnerabilityListReply{}
+// @Router /api/v1/harbor/project/{project}/vulnerability [get]
+// @Security BearerAuth
+func (h *harborHandler) ListVulnerabilities(c *gin.Context) {
+	form := &types.HarborVulnerabilityRequest{}
+	client, ok := bindHarborQuery(c, form)
+	if !ok {
+		return
+	}
+
+	vulnerabilities, err := client.GetVulnerabilities(c.Request.Context(), c.Param("project"), form.Repository, form.Reference)
+	if err != nil {
+		harborError(c, "GetVulnerabilities", err)
+		return
+	}
+	res := types.HarborVulnerabilityListReply{}
+	res.Data.List = make([]*types.HarborVulnerability, 0, len(vulnerabilities))
+	for _, item := range vulnerabilities {
+		res.Data.List = append(res.Data.List, &types.HarborVulnerability{
+			ID:          item.ID,
+			Package:     item.Package,
+			Version:     item.Version,
+			FixVersion:  item.FixVersion,
+			Severity:    item.Severity,
+			Description: item.Description,
+			Links:       item.Links,
+		})
+	}
+	response.Success(c, res.Data)
+}
+
+// bindHarborQuery 绑定query参数, harbor 未启用时直接返回错误
+func bindHarborQuery(c *gin.Context, form interface{}) (*harbor.Harbor, bool) {
+	if global.HarborClient == nil {
+		response.Error(c, ecode.ErrHarborDisabled)
+		return nil, false
+	}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return nil, false
+	}
+	return global.HarborClient, true
+}
+
+func harborError(c *gin.Context, action string, err error) {
+	if errors.Is(err, harbor.ErrNotFound) {
+		response.Error(c, ecode.NotFound)
+		return
+	}
+	logger.Error(action+" error", logger.Err(err), logger.String("path", c.Request.URL.Path), middleware.GCtxRequestIDField(c))
+	response.Error(c, ecode.ErrHarborRequest, err.Error())
+}
+
+func harborListOptions(form *types.HarborListRequest) harbor.ListOptions {
+	return harbor.ListOptions{Page: form.Page, PageSize: form.PageSize, Name: form.Name}
+}
+
+func harborArtifact(client *harbor.Harbor, repository string, artifact *harbor.Artifact) *types.HarborArtifact {
+	item := &types.HarborArtifact{
+		Digest:   artifact.Digest,
+		Size:     artifact.Size,
+		PushTime: unixTime(artifact.PushTime),
+		PullTime: unixTime(artifact.PullTime),
+		Image:    client.Image(repository, artifact.Digest),
+		Tags:     make([]*types.HarborTag, 0, len(artifact.Tags)),
+	}
+	for _, tag := range artifact.Tags {
+		item.Tags = append(item.Tags, &types.HarborTag{
+			Name:     tag.Name,
+			PushTime: unixTime(tag.PushTime),
+			Image:    client.Image(repository, tag.Name),
+		})
+	}
+	if scan := artifact.Scan(); scan != nil {
+		item.Scan = &types.HarborScanSummary{
+			Status:   scan.ScanStatus,
+			Severity: scan.Severity,
+			EndTime:  unixTime(scan.EndTime),
+		}
+		if scan.Summary != nil {
+			item.Scan.Total = scan.Summary.Total
+			item.Scan.Fixable = scan.Summary.Fixable
+			item.Scan.Severities = scan.Summary.Summary
+		}
+	}
+	return item
+}
+
+// unixTime harbor 用零值表示没有时间, 如从未拉取
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
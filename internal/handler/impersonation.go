@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/cache"
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/impersonation"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ ImpersonationHandler = (*impersonationHandler)(nil)
+
+// ImpersonationHandler defining the handler interface
+type ImpersonationHandler interface {
+	Create(c *gin.Context)
+	DeleteByID(c *gin.Context)
+	List(c *gin.Context)
+}
+
+type impersonationHandler struct {
+	iDao    dao.ImpersonationDao
+	userDao dao.UserDao
+}
+
+// NewImpersonationHandler creating the handler interface
+func NewImpersonationHandler() ImpersonationHandler {
+	return &impersonationHandler{
+		iDao:    dao.NewImpersonationDao(database.GetDB()),
+		userDao: dao.NewUserDao(database.GetDB(), cache.NewUserCache(database.GetCacheType())),
+	}
+}
+
+// Create map a user to a kubernetes identity
+// @Summary Map a user to a kubernetes user, groups or serviceaccount
+// @Description Requests of the user to the cluster impersonate the identity, the cluster credential must be allowed to impersonate users, groups and serviceaccounts.
+// @Tags impersonation
+// @Accept json
+// @Produce json
+// @Param data body types.CreateImpersonationRequest true "impersonation information"
+// @Success 200 {object} types.CreateImpersonationReply{}
+// @Router /api/v1/impersonation [post]
+// @Security BearerAuth
+func (h *impersonationHandler) Create(c *gin.Context) {
+	form := &types.CreateImpersonationRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil || (form.Username == "") == (form.ServiceAccount == nil) || (form.ServiceAccount != nil && len(form.Groups) > 0) {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Cluster == "" {
+		form.Cluster = impersonation.AllClusters
+	}
+	username := form.Username
+	if form.ServiceAccount != nil {
+		username = impersonation.ServiceAccountUsername(form.ServiceAccount.Namespace, form.ServiceAccount.Name)
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if !recordExists(c, func() error { _, err := h.userDao.GetByID(ctx, form.UserID); return err }) {
+		return
+	}
+	if _, err = h.iDao.GetByUserCluster(ctx, form.UserID, form.Cluster); err == nil {
+		response.Error(c, ecode.ErrExistImpersonation)
+		return
+	} else if !errors.Is(err, database.ErrRecordNotFound) {
+		logger.Error("GetByUserCluster error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	record := &model.Impersonation{UserID: form.UserID, Cluster: form.Cluster, Username: username, Groups: form.Groups}
+	err = h.iDao.Create(ctx, record)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrCreateImpersonation)
+		return
+	}
+	impersonation.Invalidate()
+
+	response.Success(c, gin.H{"id": record.ID})
+}
+
+// DeleteByID remove a user mapping
+// @Summary Remove a user mapping by id
+// @Tags impersonation
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/impersonation/{id} [delete]
+// @Security BearerAuth
+func (h *impersonationHandler) DeleteByID(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.DeleteByID(ctx, id)
+	if err != nil {
+		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDeleteByIDImpersonation)
+		return
+	}
+	impersonation.Invalidate()
+
+	response.Success(c)
+}
+
+// List get all user mappings
+// @Summary Get all user mappings
+// @Tags impersonation
+// @Produce json
+// @Success 200 {object} types.ListImpersonationsReply{}
+// @Router /api/v1/impersonation/list [get]
+// @Security BearerAuth
+func (h *impersonationHandler) List(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	records, err := h.iDao.GetAll(ctx)
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrListImpersonation)
+		return
+	}
+
+	data := make([]*types.ImpersonationObjDetail, 0, len(records))
+	for _, record := range records {
+		data = append(data, &types.ImpersonationObjDetail{
+			ID:        record.ID,
+			UserID:    record.UserID,
+			Cluster:   record.Cluster,
+			Username:  record.Username,
+			Groups:    record.Groups,
+			CreatedAt: record.CreatedAt,
+		})
+	}
+
+	response.Success(c, gin.H{"impersonations": data})
+}
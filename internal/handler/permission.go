@@ -0,0 +1,486 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/cache"
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ PermissionHandler = (*permissionHandler)(nil)
+
+// PermissionHandler defining the handler interface
+type PermissionHandler interface {
+	CreateRole(c *gin.Context)
+	UpdateRole(c *gin.Context)
+	DeleteRole(c *gin.Context)
+	ListRoles(c *gin.Context)
+	CreateTeam(c *gin.Context)
+	DeleteTeam(c *gin.Context)
+	SetTeamMembers(c *gin.Context)
+	ListTeams(c *gin.Context)
+	CreateBinding(c *gin.Context)
+	DeleteBinding(c *gin.Context)
+	ListBindings(c *gin.Context)
+}
+
+type permissionHandler struct {
+	roleDao    dao.AppRoleDao
+	teamDao    dao.TeamDao
+	bindingDao dao.AppRoleBindingDao
+	userDao    dao.UserDao
+}
+
+// NewPermissionHandler creating the handler interface
+func NewPermissionHandler() PermissionHandler {
+	return &permissionHandler{
+		roleDao:    dao.NewAppRoleDao(database.GetDB()),
+		teamDao:    dao.NewTeamDao(database.GetDB()),
+		bindingDao: dao.NewAppRoleBindingDao(database.GetDB()),
+		userDao:    dao.NewUserDao(database.GetDB(), cache.NewUserCache(database.GetCacheType())),
+	}
+}
+
+// CreateRole create a role
+// @Summary Create a role
+// @Description Roles grant verbs on resource kinds in namespaces of clusters, they take effect after being bound to users or teams.
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Param data body types.CreateAppRoleRequest true "role information"
+// @Success 200 {object} types.CreatePermissionObjReply{}
+// @Router /api/v1/permission/role [post]
+// @Security BearerAuth
+func (h *permissionHandler) CreateRole(c *gin.Context) {
+	form := &types.CreateAppRoleRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if _, err = h.roleDao.GetByName(ctx, form.Name); err == nil {
+		response.Error(c, ecode.ErrExistRole)
+		return
+	} else if !errors.Is(err, database.ErrRecordNotFound) {
+		logger.Error("GetByName error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	role := &model.AppRole{}
+	if err = copier.Copy(role, form); err != nil {
+		response.Error(c, ecode.ErrCreateRole)
+		return
+	}
+	err = h.roleDao.Create(ctx, role)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, gin.H{"id": role.ID})
+}
+
+// UpdateRole update a role by id
+// @Summary Update a role by id
+// @Description The rules are replaced as a whole, the change takes effect immediately for all bound users.
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.CreateAppRoleRequest true "role information"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/permission/role/{id} [put]
+// @Security BearerAuth
+func (h *permissionHandler) UpdateRole(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form := &types.CreateAppRoleRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if !recordExists(c, func() error { _, err := h.roleDao.GetByID(ctx, id); return err }) {
+		return
+	}
+	if record, err := h.roleDao.GetByName(ctx, form.Name); err == nil && record.ID != id {
+		response.Error(c, ecode.ErrExistRole)
+		return
+	}
+
+	role := &model.AppRole{}
+	if err = copier.Copy(role, form); err != nil {
+		response.Error(c, ecode.ErrUpdateRole)
+		return
+	}
+	role.ID = id
+	if role.Rules == nil {
+		role.Rules = []model.PermissionRule{}
+	}
+	err = h.roleDao.UpdateByID(ctx, role)
+	if err != nil {
+		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrUpdateRole)
+		return
+	}
+	permission.Invalidate()
+
+	response.Success(c)
+}
+
+// DeleteRole delete a role and its bindings
+// @Summary Delete a role by id
+// @Tags permission
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/permission/role/{id} [delete]
+// @Security BearerAuth
+func (h *permissionHandler) DeleteRole(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.roleDao.DeleteByID(ctx, id)
+	if err != nil {
+		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDeleteRole)
+		return
+	}
+	permission.Invalidate()
+
+	response.Success(c)
+}
+
+// ListRoles get all roles
+// @Summary Get all roles
+// @Tags permission
+// @Produce json
+// @Success 200 {object} types.ListAppRolesReply{}
+// @Router /api/v1/permission/role/list [get]
+// @Security BearerAuth
+func (h *permissionHandler) ListRoles(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	records, err := h.roleDao.GetAll(ctx)
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrListRole)
+		return
+	}
+
+	data := make([]*types.AppRoleObjDetail, 0, len(records))
+	for _, record := range records {
+		item := &types.AppRoleObjDetail{}
+		if err = copier.Copy(item, record); err != nil {
+			response.Error(c, ecode.ErrListRole)
+			return
+		}
+		data = append(data, item)
+	}
+
+	response.Success(c, gin.H{"roles": data})
+}
+
+// CreateTeam create a team
+// @Summary Create a team
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Param data body types.CreateTeamRequest true "team information"
+// @Success 200 {object} types.CreatePermissionObjReply{}
+// @Router /api/v1/permission/team [post]
+// @Security BearerAuth
+func (h *permissionHandler) CreateTeam(c *gin.Context) {
+	form := &types.CreateTeamRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if _, err = h.teamDao.GetByName(ctx, form.Name); err == nil {
+		response.Error(c, ecode.ErrExistTeam)
+		return
+	} else if !errors.Is(err, database.ErrRecordNotFound) {
+		logger.Error("GetByName error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	team := &model.Team{Name: form.Name, Description: form.Description}
+	err = h.teamDao.Create(ctx, team)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, gin.H{"id": team.ID})
+}
+
+// DeleteTeam delete a team with its members and bindings
+// @Summary Delete a team by id
+// @Tags permission
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/permission/team/{id} [delete]
+// @Security BearerAuth
+func (h *permissionHandler) DeleteTeam(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.teamDao.DeleteByID(ctx, id)
+	if err != nil {
+		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDeleteTeam)
+		return
+	}
+	permission.Invalidate()
+
+	response.Success(c)
+}
+
+// SetTeamMembers replace the members of a team
+// @Summary Replace the members of a team
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.SetTeamMembersRequest true "user ids"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/permission/team/{id}/members [put]
+// @Security BearerAuth
+func (h *permissionHandler) SetTeamMembers(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form := &types.SetTeamMembersRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if !recordExists(c, func() error { _, err := h.teamDao.GetByID(ctx, id); return err }) {
+		return
+	}
+	userIDs := make([]uint64, 0, len(form.UserIDs))
+	seen := make(map[uint64]bool, len(form.UserIDs))
+	for _, userID := range form.UserIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		if !recordExists(c, func() error { _, err := h.userDao.GetByID(ctx, userID); return err }) {
+			return
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	err = h.teamDao.SetMembers(ctx, id, userIDs)
+	if err != nil {
+		logger.Error("SetMembers error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrSetTeamMembers)
+		return
+	}
+	permission.Invalidate()
+
+	response.Success(c)
+}
+
+// ListTeams get all teams with their members
+// @Summary Get all teams
+// @Tags permission
+// @Produce json
+// @Success 200 {object} types.ListTeamsReply{}
+// @Router /api/v1/permission/team/list [get]
+// @Security BearerAuth
+func (h *permissionHandler) ListTeams(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	records, err := h.teamDao.GetAll(ctx)
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrListTeam)
+		return
+	}
+	members, err := h.teamDao.GetMembers(ctx)
+	if err != nil {
+		logger.Error("GetMembers error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrListTeam)
+		return
+	}
+	userIDs := make(map[uint64][]uint64)
+	for _, member := range members {
+		userIDs[member.TeamID] = append(userIDs[member.TeamID], member.UserID)
+	}
+
+	data := make([]*types.TeamObjDetail, 0, len(records))
+	for _, record := range records {
+		item := &types.TeamObjDetail{
+			ID:          record.ID,
+			Name:        record.Name,
+			Description: record.Description,
+			UserIDs:     userIDs[record.ID],
+			CreatedAt:   record.CreatedAt,
+			UpdatedAt:   record.UpdatedAt,
+		}
+		if item.UserIDs == nil {
+			item.UserIDs = []uint64{}
+		}
+		data = append(data, item)
+	}
+
+	response.Success(c, gin.H{"teams": data})
+}
+
+// CreateBinding grant a role to a user or a team
+// @Summary Grant a role to a user or a team
+// @Description Exactly one of userID and teamID must be set.
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Param data body types.CreateAppRoleBindingRequest true "binding information"
+// @Success 200 {object} types.CreatePermissionObjReply{}
+// @Router /api/v1/permission/binding [post]
+// @Security BearerAuth
+func (h *permissionHandler) CreateBinding(c *gin.Context) {
+	form := &types.CreateAppRoleBindingRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil || (form.UserID == 0) == (form.TeamID == 0) {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if !recordExists(c, func() error { _, err := h.roleDao.GetByID(ctx, form.RoleID); return err }) {
+		return
+	}
+	if form.UserID != 0 {
+		if !recordExists(c, func() error { _, err := h.userDao.GetByID(ctx, form.UserID); return err }) {
+			return
+		}
+	} else if !recordExists(c, func() error { _, err := h.teamDao.GetByID(ctx, form.TeamID); return err }) {
+		return
+	}
+
+	binding := &model.AppRoleBinding{RoleID: form.RoleID, UserID: form.UserID, TeamID: form.TeamID}
+	err = h.bindingDao.Create(ctx, binding)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrCreateRoleBinding)
+		return
+	}
+	permission.Invalidate()
+
+	response.Success(c, gin.H{"id": binding.ID})
+}
+
+// DeleteBinding revoke a role binding
+// @Summary Revoke a role binding by id
+// @Tags permission
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/permission/binding/{id} [delete]
+// @Security BearerAuth
+func (h *permissionHandler) DeleteBinding(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.bindingDao.DeleteByID(ctx, id)
+	if err != nil {
+		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDeleteRoleBinding)
+		return
+	}
+	permission.Invalidate()
+
+	response.Success(c)
+}
+
+// ListBindings get all role bindings
+// @Summary Get all role bindings
+// @Tags permission
+// @Produce json
+// @Success 200 {object} types.ListAppRoleBindingsReply{}
+// @Router /api/v1/permission/binding/list [get]
+// @Security BearerAuth
+func (h *permissionHandler) ListBindings(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	records, err := h.bindingDao.GetAll(ctx)
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrListRoleBinding)
+		return
+	}
+
+	data := make([]*types.AppRoleBindingObjDetail, 0, len(records))
+	for _, record := range records {
+		data = append(data, &types.AppRoleBindingObjDetail{
+			ID:        record.ID,
+			RoleID:    record.RoleID,
+			UserID:    record.UserID,
+			TeamID:    record.TeamID,
+			CreatedAt: record.CreatedAt,
+		})
+	}
+
+	response.Success(c, gin.H{"bindings": data})
+}
+
+// recordExists 记录不存在时返回 NotFound
+func recordExists(c *gin.Context, get func() error) bool {
+	err := get()
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, database.ErrRecordNotFound) {
+		response.Error(c, ecode.NotFound)
+	} else {
+		logger.Error("get record error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/pullsecret"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+// 凭证变化后后台同步托管secret的超时时间
+const registrySyncTimeout = 5 * time.Minute
+
+var _ RegistryHandler = (*registryHandler)(nil)
+
+// RegistryHandler defining the handler interface
+type RegistryHandler interface {
+	Create(c *gin.Context)
+	DeleteByID(c *gin.Context)
+	UpdateByID(c *gin.Context)
+	List(c *gin.Context)
+	Sync(c *gin.Context)
+}
+
+type registryHandler struct {
+	iDao dao.RegistryDao
+}
+
+// NewRegistryHandler creating the handler interface
+func NewRegistryHandler() RegistryHandler {
+	return &registryHandler{
+		iDao: dao.NewRegistryDao(
+			database.GetDB(), // db driver is mysql
+		),
+	}
+}
+
+// Create add registry credentials
+// @Summary Add registry credentials
+// @Description Stores the encrypted credentials, then updates the managed imagePullSecret in all clusters in the background.
+// @Tags registry
+// @Accept json
+// @Produce json
+// @Param data body types.CreateRegistryRequest true "registry information"
+// @Success 200 {object} types.CreateRegistryReply{}
+// @Router /api/v1/registry [post]
+// @Security BearerAuth
+func (h *registryHandler) Create(c *gin.Context) {
+	form := &types.CreateRegistryRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if _, err = h.iDao.GetByName(ctx, form.Name); err == nil {
+		response.Error(c, ecode.ErrExistRegistry)
+		return
+	} else if !errors.Is(err, database.ErrRecordNotFound) {
+		logger.Error("GetByName error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	password, err := cluster.Encrypt(form.Password)
+	if err != nil {
+		logger.Warn("Encrypt error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrCreateRegistry, err.Error())
+		return
+	}
+	record := &model.Registry{
+		Name:     form.Name,
+		Server:   form.Server,
+		Username: form.Username,
+		Password: password,
+	}
+	err = h.iDao.Create(ctx, record)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	go syncPullSecrets()
+
+	response.Success(c, gin.H{"id": record.ID})
+}
+
+// DeleteByID remove registry credentials by id
+// @Summary Remove registry credentials by id
+// @Description Deletes the credentials, then updates the managed imagePullSecret in all clusters in the background.
+// @Tags registry
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.DeleteRegistryByIDReply{}
+// @Router /api/v1/registry/{id} [delete]
+// @Security BearerAuth
+func (h *registryHandler) DeleteByID(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if _, err := h.iDao.GetByID(ctx, id); err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	err := h.iDao.DeleteByID(ctx, id)
+	if err != nil {
+		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDeleteByIDRegistry)
+		return
+	}
+	go syncPullSecrets()
+
+	response.Success(c)
+}
+
+// UpdateByID update registry credentials by id
+// @Summary Update registry credentials by id
+// @Description Updates the credentials in place, e.g. to rotate the password, then updates the managed imagePullSecret in all clusters in the background.
+// @Tags registry
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.UpdateRegistryByIDRequest true "registry information"
+// @Success 200 {object} types.UpdateRegistryByIDReply{}
+// @Router /api/v1/registry/{id} [put]
+// @Security BearerAuth
+func (h *registryHandler) UpdateByID(c *gin.Context) {
+	_, id, isAbort := getUserIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.UpdateRegistryByIDRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form.ID = id
+
+	ctx := middleware.WrapCtx(c)
+	if _, err = h.iDao.GetByID(ctx, id); err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	record := &model.Registry{
+		ID:       id,
+		Server:   form.Server,
+		Username: form.Username,
+	}
+	if form.Password != "" {
+		record.Password, err = cluster.Encrypt(form.Password)
+		if err != nil {
+			logger.Warn("Encrypt error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrUpdateByIDRegistry, err.Error())
+			return
+		}
+	}
+	err = h.iDao.UpdateByID(ctx, record)
+	if err != nil {
+		logger.Error("UpdateByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrUpdateByIDRegistry)
+		return
+	}
+	go syncPullSecrets()
+
+	response.Success(c)
+}
+
+// List get all registry credentials
+// @Summary Get all registry credentials
+// @Description Returns the harbor registry from the configuration file and all added registries, passwords are not returned.
+// @Tags registry
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.ListRegistriesReply{}
+// @Router /api/v1/registry/list [get]
+// @Security BearerAuth
+func (h *registryHandler) List(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	records, err := h.iDao.GetAll(ctx)
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrListRegistry)
+		return
+	}
+
+	data := make([]*types.RegistryObjDetail, 0, len(records)+1)
+	if global.CONF != nil && global.CONF.System.Harbor.Enable && global.CONF.System.Harbor.Host != "" {
+		data = append(data, &types.RegistryObjDetail{
+			Name:     "harbor",
+			Server:   global.CONF.System.Harbor.Host,
+			Username: global.CONF.System.Harbor.Username,
+			Source:   "config",
+		})
+	}
+	for _, record := range records {
+		data = append(data, &types.RegistryObjDetail{
+			ID:        record.ID,
+			Name:      record.Name,
+			Server:    record.Server,
+			Username:  record.Username,
+			Source:    "db",
+			CreatedAt: record.CreatedAt,
+			UpdatedAt: record.UpdatedAt,
+		})
+	}
+
+	response.Success(c, gin.H{"registries": data})
+}
+
+// Sync update the managed imagePullSecret in all clusters
+// @Summary Update the managed imagePullSecret in all clusters
+// @Description Rewrites every existing managed imagePullSecret with the current credentials, returns the clusters that failed.
+// @Tags registry
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.SyncRegistryReply{}
+// @Router /api/v1/registry/sync [post]
+// @Security BearerAuth
+func (h *registryHandler) Sync(c *gin.Context) {
+	failed := make(map[string]string)
+	for name, err := range pullsecret.SyncClusters(c.Request.Context()) {
+		logger.Warn("sync pull secret error", logger.Err(err), logger.String("cluster", name), middleware.GCtxRequestIDField(c))
+		failed[name] = err.Error()
+	}
+	response.Success(c, gin.H{"failed": failed})
+}
+
+// syncPullSecrets 凭证变化后在后台更新所有集群的托管secret
+func syncPullSecrets() {
+	ctx, cancel := context.WithTimeout(context.Background(), registrySyncTimeout)
+	defer cancel()
+	for name, err := range pullsecret.SyncClusters(ctx) {
+		logger.Warn("sync pull secret error", logger.Err(err), logger.String("cluster", name))
+	}
+}
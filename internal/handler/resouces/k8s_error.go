@@ -0,0 +1,23 @@
+package resouces
+
+import (
+	"errors"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
+)
+
+// k8sErrorCode apiserver 返回的错误沿用其状态码(如404、403), 列表查询使用了不支持的字段为400, 其他错误为500
+func k8sErrorCode(err error) int {
+	if errors.Is(err, listquery.ErrUnsupportedField) {
+		return http.StatusBadRequest
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Code != 0 {
+		return int(status.Status().Code)
+	}
+	return ecode.InternalServerError.ToHTTPCode()
+}
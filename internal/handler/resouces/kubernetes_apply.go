@@ -0,0 +1,91 @@
+package resouces
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+// 请求体最大字节数
+const maxApplyBodySize = 10 << 20
+
+var _ ApplyHandler = (*applyHandler)(nil)
+
+// ApplyHandler defining the handler interface
+type ApplyHandler interface {
+	Apply(c *gin.Context)
+}
+
+type applyHandler struct {
+}
+
+func NewApplyHandler() ApplyHandler {
+	return &applyHandler{}
+}
+
+// Apply 以 server-side apply 方式创建或更新任意资源
+// @Summary Apply 创建或更新任意资源
+// @Description 请求体为多文档YAML或JSON(包括List), 支持CRD; 每个对象单独检查 edit 权限, 资源类型为小写的kind(有路由的资源与路由名称一致, 如 svc、pvc)
+// @Tags apply
+// @Accept plain
+// @Produce json
+// @Param namespace query string false "命名空间级资源没有指定命名空间时使用, 默认为 default"
+// @Param dryRun query bool false "只校验, 不保存"
+// @Param force query bool false "字段被其他manager管理时强制接管"
+// @Param data body string true "YAML或JSON"
+// @Success 200 {object} types.ApplyReply{}
+// @Router /api/v1/k8s/{cluster}/apply [post]
+// @Security BearerAuth
+func (h *applyHandler) Apply(c *gin.Context) {
+	reqParam := &types.ApplyRequest{}
+	err := c.ShouldBindQuery(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxApplyBodySize+1))
+	if err != nil || len(data) > maxApplyBodySize {
+		logger.Warn("read request body error", logger.Err(err), logger.Int("size", len(data)), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	objects, err := controller.ParseManifests(data)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+
+	client, err := cluster.DynamicClient(c)
+	if err != nil {
+		logger.Error("DynamicClient error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	perms := permission.FromContext(c)
+	clusterName := c.Param("cluster")
+	allowed := func(namespace string, gvk schema.GroupVersionKind) bool {
+		return perms.Allowed(clusterName, namespace, permission.ObjectKind(gvk.Kind), permission.VerbEdit)
+	}
+	results := controller.NewApplyController(client, cluster.FromContext(c).Mapper).Apply(c.Request.Context(), objects, reqParam, allowed)
+	for _, item := range results {
+		if item.Result == types.ApplyResultFailed {
+			logger.Warn("apply object failed", logger.Any("object", item), middleware.GCtxRequestIDField(c))
+		}
+	}
+
+	res := types.ApplyReply{}
+	res.Data.DryRun = reqParam.DryRun
+	res.Data.List = results
+	response.Success(c, res)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 )
 
@@ -45,7 +46,7 @@ func (h *configMapHandler) CreateOrUpdateConfigMap(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams)
 		return
 	}
-	err = controller.NewConfigMapController().CreateOrUpdateConfigMap(c.Request.Context(), reqParam)
+	err = controller.NewConfigMapController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateConfigMap(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("CreateOrUpdateConfigMap error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -75,7 +76,7 @@ func (h *configMapHandler) GetConfigMapDetail(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace and name 不能为空")
 		return
 	}
-	cm, err := controller.NewConfigMapController().GetConfigMapDetail(c.Request.Context(), &reqparam)
+	cm, err := controller.NewConfigMapController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetConfigMapDetail(c.Request.Context(), &reqparam)
 	if err != nil {
 		logger.Error("GetConfigMapDetail error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -93,26 +94,33 @@ func (h *configMapHandler) GetConfigMapDetail(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param namespace query string struct true "namespace"
-// @Param keyword query string struct true "keyword"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} []types.ConfigMapRe
 // @Router /api/v1/k8s/configmap/:namespace [get]
 // @Security BearerAuth
 func (h *configMapHandler) GetConfigMapList(c *gin.Context) {
 	reqparam := &types.GetConfigMapDetailORListRequest{}
 	reqparam.Namespace = c.Param("namespace")
-	reqparam.Keyword = c.Query("keyword")
 
 	if reqparam.Namespace == "" {
 		response.Error(c, ecode.InvalidParams, "namespace  不能为空")
 		return
 	}
-	cmList, err := controller.NewConfigMapController().GetConfigMapList(c.Request.Context(), reqparam)
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+	cmList, listMeta, err := controller.NewConfigMapController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetConfigMapList(c.Request.Context(), reqparam, query)
 	if err != nil {
 		logger.Error("GetConfigMapList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
-	response.Success(c, cmList)
+	resList := types.ListConfigMapReply{}
+	resList.Data.List = cmList
+	resList.Data.ListMeta = *listMeta
+	response.Success(c, resList)
 }
 
 // DeleteConfigMap 删除configMap
@@ -135,7 +143,7 @@ func (h *configMapHandler) DeleteConfigMap(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace and name 不能为空")
 		return
 	}
-	err := controller.NewConfigMapController().DeleteConfigMap(c.Request.Context(), reqParam)
+	err := controller.NewConfigMapController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteConfigMap(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("DeleteConfigMap error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -0,0 +1,229 @@
+package resouces
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ CronJobHandler = (*cronJobHandler)(nil)
+
+// CronJobHandler defining the handler interface
+type CronJobHandler interface {
+	CreateOrUpdateCronJob(c *gin.Context)
+	GetCronJobDetail(c *gin.Context)
+	GetCronJobList(c *gin.Context)
+	DeleteCronJob(c *gin.Context)
+	SuspendCronJob(c *gin.Context)
+	ResumeCronJob(c *gin.Context)
+	TriggerCronJob(c *gin.Context)
+	GetCronJobHistory(c *gin.Context)
+}
+
+type cronJobHandler struct {
+}
+
+func NewCronJobHandler() CronJobHandler {
+	return &cronJobHandler{}
+}
+
+// CreateOrUpdateCronJob 创建或更新cronjob
+// @Summary CreateOrUpdateCronJob 创建或更新cronjob
+// @Description 修改只影响之后创建的job
+// @Tags cronjob
+// @Accept json
+// @Produce json
+// @Param data body types.CronJobRequest true "请求参数"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/cronjob [post]
+// @Security BearerAuth
+func (h *cronJobHandler) CreateOrUpdateCronJob(c *gin.Context) {
+	reqParam := &types.CronJobRequest{}
+	err := c.ShouldBindJSON(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	err = controller.NewCronJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateCronJob(c.Request.Context(), reqParam)
+	if err != nil {
+		logger.Error("CreateOrUpdateCronJob error", logger.Err(err), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// GetCronJobDetail cronjob详情
+// @Summary GetCronJobDetail cronjob详情
+// @Tags cronjob
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.CronJobDetailReply{}
+// @Router /api/v1/k8s/{cluster}/cronjob/{namespace}/{name} [get]
+// @Security BearerAuth
+func (h *cronJobHandler) GetCronJobDetail(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	res, err := controller.NewCronJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetCronJobDetail(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("GetCronJobDetail error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	detail := types.CronJobDetailReply{}
+	detail.Data.CronJob = res
+	response.Success(c, detail)
+}
+
+// GetCronJobList cronjob列表
+// @Summary GetCronJobList cronjob列表
+// @Description 列表中包含保留的执行记录中成功和失败的job数
+// @Tags cronjob
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
+// @Success 200 {object} types.CronJobListReply{}
+// @Router /api/v1/k8s/{cluster}/cronjob/{namespace} [get]
+// @Security BearerAuth
+func (h *cronJobHandler) GetCronJobList(c *gin.Context) {
+	namespace := c.Param("namespace")
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+
+	resList, listMeta, err := controller.NewCronJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetCronJobList(c.Request.Context(), namespace, query)
+	if err != nil {
+		logger.Error("GetCronJobList error", logger.Err(err), logger.String("namespace", namespace), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.CronJobListReply{}
+	res.Data.List = resList
+	res.Data.ListMeta = *listMeta
+	response.Success(c, res)
+}
+
+// DeleteCronJob 删除cronjob及其创建的job
+// @Summary DeleteCronJob 删除cronjob
+// @Tags cronjob
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/cronjob/{namespace}/{name} [delete]
+// @Security BearerAuth
+func (h *cronJobHandler) DeleteCronJob(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	err := controller.NewCronJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteCronJob(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("DeleteCronJob error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// SuspendCronJob 暂停调度
+// @Summary SuspendCronJob 暂停调度
+// @Description 已经在运行的job不受影响
+// @Tags cronjob
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/cronjob/{namespace}/{name}/suspend [put]
+// @Security BearerAuth
+func (h *cronJobHandler) SuspendCronJob(c *gin.Context) {
+	h.setSuspend(c, true)
+}
+
+// ResumeCronJob 恢复调度
+// @Summary ResumeCronJob 恢复调度
+// @Tags cronjob
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/cronjob/{namespace}/{name}/resume [put]
+// @Security BearerAuth
+func (h *cronJobHandler) ResumeCronJob(c *gin.Context) {
+	h.setSuspend(c, false)
+}
+
+func (h *cronJobHandler) setSuspend(c *gin.Context, suspend bool) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	err := controller.NewCronJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).SuspendCronJob(c.Request.Context(), namespace, name, suspend)
+	if err != nil {
+		logger.Error("SuspendCronJob error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), logger.Bool("suspend", suspend), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// TriggerCronJob 立即执行一次
+// @Summary TriggerCronJob 立即执行一次
+// @Description 按cronjob的模板创建一个job, 暂停状态下也可以执行
+// @Tags cronjob
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.TriggerCronJobReply{}
+// @Router /api/v1/k8s/{cluster}/cronjob/{namespace}/{name}/trigger [post]
+// @Security BearerAuth
+func (h *cronJobHandler) TriggerCronJob(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	jobName, err := controller.NewCronJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).TriggerCronJob(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("TriggerCronJob error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.TriggerCronJobReply{}
+	res.Data.Name = jobName
+	response.Success(c, res)
+}
+
+// GetCronJobHistory cronjob执行记录
+// @Summary GetCronJobHistory cronjob执行记录
+// @Description 返回保留的job及每个pod的结果, 按创建时间倒序
+// @Tags cronjob
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.CronJobHistoryReply{}
+// @Router /api/v1/k8s/{cluster}/cronjob/{namespace}/{name}/jobs [get]
+// @Security BearerAuth
+func (h *cronJobHandler) GetCronJobHistory(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	history, err := controller.NewCronJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetCronJobHistory(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("GetCronJobHistory error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.CronJobHistoryReply{}
+	res.Data.List = history
+	response.Success(c, res)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 )
 
@@ -36,15 +37,31 @@ func (h *daemonsetHandler) CreateOrUpdateDaemonset(c *gin.Context) {
 		return
 
 	}
+	query := types.PodSecurityQuery{}
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if reqParam.Base == nil || reqParam.Template == nil {
+		response.Error(c, ecode.InvalidParams, "base 和 template 不能为空")
+		return
+	}
+	podSecurity, ok := checkPodSecurity(c, reqParam.Template, reqParam.Base.Namespace, query)
+	if !ok {
+		return
+	}
 
-	err = controller.NewDaemonsetController().CreateOrDaemonset(c.Request.Context(), reqParam)
+	err = controller.NewDaemonsetController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrDaemonset(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("CreateOrUpdateDaemonset error", logger.Err(err), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
 
-	response.Success(c, true)
+	res := types.CreateOrUpdateWorkloadReply{}
+	res.Data.PodSecurity = podSecurity
+	response.Success(c, res)
 }
 func (h *daemonsetHandler) GetDaemonsetDetail(c *gin.Context) {
 	namespace := c.Param("namespace")
@@ -55,7 +72,7 @@ func (h *daemonsetHandler) GetDaemonsetDetail(c *gin.Context) {
 			return
 		}
 	}
-	res, err := controller.NewDaemonsetController().GetDaemonsetDetail(c.Request.Context(), namespace, name)
+	res, err := controller.NewDaemonsetController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetDaemonsetDetail(c.Request.Context(), namespace, name)
 
 	if err != nil {
 		logger.Error("GetDaemonsetDetail error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
@@ -74,18 +91,23 @@ func (h *daemonsetHandler) GetDaemonsetList(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  and name 不能为空")
 		return
 	}
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
 
-	resList, err := controller.NewDaemonsetController().GetDaemonsetList(c.Request.Context(), namespace)
+	resList, listMeta, err := controller.NewDaemonsetController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetDaemonsetList(c.Request.Context(), namespace, query)
 
 	if err != nil {
 		logger.Error("GetDaemonsetList error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
 
-	resDeployList := types.DaemonsetListReply{
-		Data: struct{ List []*types.DaemonSetRes }{List: resList},
-	}
+	resDeployList := types.DaemonsetListReply{}
+	resDeployList.Data.List = resList
+	resDeployList.Data.ListMeta = *listMeta
 
 	response.Success(c, resDeployList)
 }
@@ -99,7 +121,7 @@ func (h *daemonsetHandler) DeleteDaemonset(c *gin.Context) {
 		}
 	}
 
-	err := controller.NewDaemonsetController().DeleteDaemonset(c.Request.Context(), namespace, name)
+	err := controller.NewDaemonsetController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteDaemonset(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("CreateOrUpdateDaemonset error", logger.Err(err), logger.Any("name", name), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
@@ -7,6 +7,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 )
 
@@ -18,6 +19,13 @@ type DeploymentHandler interface {
 	GetDeploymentDetail(c *gin.Context)
 	GetDeploymentList(c *gin.Context)
 	DeleteDeployment(c *gin.Context)
+	ScaleDeployment(c *gin.Context)
+	RestartDeployment(c *gin.Context)
+	PauseDeployment(c *gin.Context)
+	ResumeDeployment(c *gin.Context)
+	GetDeploymentHistory(c *gin.Context)
+	RollbackDeployment(c *gin.Context)
+	GetDeploymentRolloutStatus(c *gin.Context)
 }
 
 type deploymentHandler struct {
@@ -36,15 +44,31 @@ func (h *deploymentHandler) CreateOrUpdateDeployment(c *gin.Context) {
 		return
 
 	}
+	query := types.PodSecurityQuery{}
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if reqParam.Base == nil || reqParam.Template == nil {
+		response.Error(c, ecode.InvalidParams, "base 和 template 不能为空")
+		return
+	}
+	podSecurity, ok := checkPodSecurity(c, reqParam.Template, reqParam.Base.Namespace, query)
+	if !ok {
+		return
+	}
 
-	err = controller.NewDeploymentController().CreateOrDeployment(c.Request.Context(), reqParam)
+	err = controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrDeployment(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("CreateOrUpdateDeployment error", logger.Err(err), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
 
-	response.Success(c, true)
+	res := types.CreateOrUpdateWorkloadReply{}
+	res.Data.PodSecurity = podSecurity
+	response.Success(c, res)
 
 }
 func (h *deploymentHandler) GetDeploymentDetail(c *gin.Context) {
@@ -56,7 +80,7 @@ func (h *deploymentHandler) GetDeploymentDetail(c *gin.Context) {
 			return
 		}
 	}
-	res, err := controller.NewDeploymentController().GetDeploymentDetail(c.Request.Context(), namespace, name)
+	res, err := controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetDeploymentDetail(c.Request.Context(), namespace, name)
 
 	if err != nil {
 		logger.Error("GetDeploymentDetail error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
@@ -76,18 +100,23 @@ func (h *deploymentHandler) GetDeploymentList(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  and name 不能为空")
 		return
 	}
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
 
-	resList, err := controller.NewDeploymentController().GetDeploymentList(c.Request.Context(), namespace)
+	resList, listMeta, err := controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetDeploymentList(c.Request.Context(), namespace, query)
 
 	if err != nil {
-		logger.Error("GetDeploymentDetail error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		logger.Error("GetDeploymentList error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
 
-	resDeployList := types.DeploymentListReply{
-		Data: struct{ List []*types.DeploymentRes }{List: resList},
-	}
+	resDeployList := types.DeploymentListReply{}
+	resDeployList.Data.List = resList
+	resDeployList.Data.ListMeta = *listMeta
 
 	response.Success(c, resDeployList)
 
@@ -101,7 +130,7 @@ func (h *deploymentHandler) DeleteDeployment(c *gin.Context) {
 			return
 		}
 	}
-	err := controller.NewDeploymentController().DeleteDeployment(c.Request.Context(), namespace, name)
+	err := controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteDeployment(c.Request.Context(), namespace, name)
 
 	if err != nil {
 		logger.Error("DeleteDeployment error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
@@ -110,3 +139,181 @@ func (h *deploymentHandler) DeleteDeployment(c *gin.Context) {
 	}
 	response.Success(c, true)
 }
+
+// ScaleDeployment 修改副本数
+// @Summary ScaleDeployment 修改副本数
+// @Tags deployment
+// @Accept json
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Param data body types.DeploymentScaleRequest true "请求参数"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/deployment/{namespace}/{name}/scale [put]
+// @Security BearerAuth
+func (h *deploymentHandler) ScaleDeployment(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	reqParam := &types.DeploymentScaleRequest{}
+	err := c.ShouldBindJSON(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	err = controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).ScaleDeployment(c.Request.Context(), namespace, name, reqParam.Replicas)
+	if err != nil {
+		logger.Error("ScaleDeployment error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// RestartDeployment 滚动重启
+// @Summary RestartDeployment 滚动重启
+// @Description 与 kubectl rollout restart 相同, 暂停状态下不能重启
+// @Tags deployment
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/deployment/{namespace}/{name}/restart [put]
+// @Security BearerAuth
+func (h *deploymentHandler) RestartDeployment(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	err := controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).RestartDeployment(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("RestartDeployment error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// PauseDeployment 暂停发布
+// @Summary PauseDeployment 暂停发布
+// @Description 暂停期间修改模板不会触发滚动更新
+// @Tags deployment
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/deployment/{namespace}/{name}/pause [put]
+// @Security BearerAuth
+func (h *deploymentHandler) PauseDeployment(c *gin.Context) {
+	h.setPaused(c, true)
+}
+
+// ResumeDeployment 恢复发布
+// @Summary ResumeDeployment 恢复发布
+// @Tags deployment
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/deployment/{namespace}/{name}/resume [put]
+// @Security BearerAuth
+func (h *deploymentHandler) ResumeDeployment(c *gin.Context) {
+	h.setPaused(c, false)
+}
+
+func (h *deploymentHandler) setPaused(c *gin.Context, paused bool) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	err := controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).PauseDeployment(c.Request.Context(), namespace, name, paused)
+	if err != nil {
+		logger.Error("PauseDeployment error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), logger.Bool("paused", paused), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// GetDeploymentHistory 历史版本
+// @Summary GetDeploymentHistory 历史版本
+// @Description 按版本号倒序, 每个版本包含相对上一个版本的模板变化
+// @Tags deployment
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.DeploymentHistoryReply{}
+// @Router /api/v1/k8s/{cluster}/deployment/{namespace}/{name}/history [get]
+// @Security BearerAuth
+func (h *deploymentHandler) GetDeploymentHistory(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	history, err := controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetDeploymentHistory(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("GetDeploymentHistory error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.DeploymentHistoryReply{}
+	res.Data.List = history
+	response.Success(c, res)
+}
+
+// RollbackDeployment 回滚到指定版本
+// @Summary RollbackDeployment 回滚到指定版本
+// @Description revision 为0时回滚到上一个版本, 暂停状态下不能回滚
+// @Tags deployment
+// @Accept json
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Param data body types.DeploymentRollbackRequest true "请求参数"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/deployment/{namespace}/{name}/rollback [put]
+// @Security BearerAuth
+func (h *deploymentHandler) RollbackDeployment(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	reqParam := &types.DeploymentRollbackRequest{}
+	err := c.ShouldBindJSON(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	err = controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).RollbackDeployment(c.Request.Context(), namespace, name, reqParam.Revision)
+	if err != nil {
+		logger.Error("RollbackDeployment error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// GetDeploymentRolloutStatus 发布进度
+// @Summary GetDeploymentRolloutStatus 发布进度
+// @Description 返回发布是否完成、是否超过 progressDeadlineSeconds 卡住
+// @Tags deployment
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.DeploymentRolloutStatusReply{}
+// @Router /api/v1/k8s/{cluster}/deployment/{namespace}/{name}/status [get]
+// @Security BearerAuth
+func (h *deploymentHandler) GetDeploymentRolloutStatus(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	status, err := controller.NewDeploymentController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetDeploymentRolloutStatus(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("GetDeploymentRolloutStatus error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.DeploymentRolloutStatusReply{}
+	res.Data.Status = status
+	response.Success(c, res)
+}
@@ -0,0 +1,140 @@
+package resouces
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/terminal"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+// exec_session.error 字段长度
+const maxExecErrorLen = 1024
+
+// Cors 中间件不作用于websocket握手, 需要单独校验 Origin
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin: func(r *http.Request) bool {
+		var allowed []string
+		if global.CONF != nil {
+			allowed = global.CONF.System.ExecAllowedOrigins
+		}
+		return terminal.CheckOrigin(r, allowed)
+	},
+}
+
+var _ ExecHandler = (*execHandler)(nil)
+
+// ExecHandler defining the handler interface
+type ExecHandler interface {
+	Exec(c *gin.Context)
+}
+
+type execHandler struct {
+	iDao dao.ExecSessionDao
+}
+
+// NewExecHandler creating the handler interface
+func NewExecHandler() ExecHandler {
+	return &execHandler{
+		iDao: dao.NewExecSessionDao(
+			database.GetDB(), // db driver is mysql
+		),
+	}
+}
+
+// Exec 容器web终端
+// @Summary Exec 容器web终端
+// @Description websocket连接, 客户端发送 {"type":"stdin","data":"ls\r"} 或 {"type":"resize","cols":120,"rows":40}, 服务端以二进制消息返回终端输出, 会话结束时关闭连接并在关闭原因中说明; 每次会话都会记录审计
+// @Tags pod
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Param query query types.PodExecRequest false "终端参数"
+// @Router /api/v1/k8s/{cluster}/pod/{namespace}/{name}/exec [get]
+// @Security BearerAuth
+func (h *execHandler) Exec(c *gin.Context) {
+	reqParam := &types.PodExecRequest{}
+	if err := c.ShouldBindQuery(reqParam); err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	reqParam.Namespace = c.Param("namespace")
+	reqParam.Name = c.Param("name")
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade 失败时已经写入了错误响应
+		logger.Warn("websocket upgrade error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		return
+	}
+	session := terminal.NewSession(conn, global.CONF != nil && global.CONF.System.ExecTranscript)
+	session.Resize(reqParam.Cols, reqParam.Rows)
+
+	startedAt := time.Now()
+	record := &model.ExecSession{
+		Username:  operator(c),
+		ClientIP:  c.ClientIP(),
+		Cluster:   cluster.FromContext(c).Name,
+		Namespace: reqParam.Namespace,
+		Pod:       reqParam.Name,
+		Container: reqParam.Container,
+		Command:   strings.Join(controller.ExecCommand(reqParam.Shell), " "),
+		StartedAt: &startedAt,
+	}
+	// 没有审计记录的会话不允许建立
+	if err = h.iDao.Create(c.Request.Context(), record); err != nil {
+		logger.Error("Create exec session error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		session.Close("audit record failed")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-session.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err = controller.NewPodController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).ExecPod(ctx, cluster.ClientConfig(c), reqParam, remotecommand.StreamOptions{
+		Stdin:             session,
+		Stdout:            session,
+		Tty:               true,
+		TerminalSizeQueue: session,
+	})
+	if err != nil {
+		logger.Warn("ExecPod error", logger.Err(err), logger.Any("parmm", reqParam), middleware.GCtxRequestIDField(c))
+		record.Error = err.Error()
+		if len(record.Error) > maxExecErrorLen {
+			record.Error = strings.ToValidUTF8(record.Error[:maxExecErrorLen], "")
+		}
+	}
+	session.Close(record.Error)
+
+	endedAt := time.Now()
+	record.EndedAt = &endedAt
+	record.Transcript = session.Transcript()
+	if err = h.iDao.UpdateByID(context.WithoutCancel(c.Request.Context()), record); err != nil {
+		logger.Error("UpdateByID exec session error", logger.Err(err), middleware.GCtxRequestIDField(c))
+	}
+}
@@ -0,0 +1,112 @@
+package resouces
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+)
+
+var _ ExportHandler = (*exportHandler)(nil)
+
+// ExportHandler defining the handler interface
+type ExportHandler interface {
+	GetYAML(kind string) gin.HandlerFunc
+	ExportNamespace(c *gin.Context)
+}
+
+type exportHandler struct {
+}
+
+func NewExportHandler() ExportHandler {
+	return &exportHandler{}
+}
+
+// GetYAML 导出资源的YAML, 去掉了 status、managedFields、uid、resourceVersion 等服务端字段, 可以直接 apply
+// @Summary GetYAML 导出资源的YAML
+// @Description kind 为 pod/deployment/daemonset/statefulset/job/cronjob/svc/ingress/ingroute/configmap/secret/sa/pvc/role, 集群级资源 node/pv/sc 的路径为 /{kind}/{name}/yaml, rolebinding 的路径为 /role/binding/{name}/yaml?namespace=, namespace 为空时为 ClusterRoleBinding
+// @Tags export
+// @Produce plain
+// @Param kind path string true "资源类型"
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {string} string "yaml"
+// @Router /api/v1/k8s/{cluster}/{kind}/{namespace}/{name}/yaml [get]
+// @Security BearerAuth
+func (h *exportHandler) GetYAML(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace := c.Param("namespace")
+		if namespace == "" {
+			namespace = c.Query("namespace")
+		}
+		name := c.Param("name")
+		client, err := cluster.DynamicClient(c)
+		if err != nil {
+			logger.Error("DynamicClient error", logger.Err(err), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			return
+		}
+
+		data, err := controller.NewExportController(client).GetYAML(c.Request.Context(), kind, namespace, name)
+		if err != nil {
+			logger.Error("GetYAML error", logger.Err(err), logger.String("kind", kind), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+			response.Output(c, k8sErrorCode(err), err)
+			return
+		}
+
+		c.Data(http.StatusOK, "application/yaml; charset=utf-8", data)
+	}
+}
+
+// ExportNamespace 把命名空间中的资源导出为zip
+// @Summary ExportNamespace 导出命名空间中的所有资源
+// @Description zip 中的文件为 <kind>/<name>.yaml, 只包含当前用户有查看权限的资源类型; 跳过由控制器创建的对象(如deployment创建的pod)和集群自动创建的对象
+// @Tags export
+// @Produce application/zip
+// @Param namespace path string true "namespace"
+// @Success 200 {file} file
+// @Router /api/v1/k8s/{cluster}/export/{namespace} [get]
+// @Security BearerAuth
+func (h *exportHandler) ExportNamespace(c *gin.Context) {
+	namespace := c.Param("namespace")
+	perms := permission.FromContext(c)
+	clusterName := c.Param("cluster")
+	allowed := func(kind string) bool {
+		verb := permission.VerbView
+		if kind == "secret" {
+			verb = permission.VerbSecretRead
+		}
+		return perms.Allowed(clusterName, namespace, kind, verb)
+	}
+	if !perms.NamespaceVisible(clusterName, namespace) {
+		response.Error(c, ecode.ErrPermissionDenied, fmt.Sprintf("export namespace %q", namespace))
+		return
+	}
+	client, err := cluster.DynamicClient(c)
+	if err != nil {
+		logger.Error("DynamicClient error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	// 先写入内存, 导出失败时仍然可以返回错误
+	buf := &bytes.Buffer{}
+	err = controller.NewExportController(client).ExportNamespace(c.Request.Context(), namespace, buf, allowed)
+	if err != nil {
+		logger.Error("ExportNamespace error", logger.Err(err), logger.String("namespace", namespace), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", namespace+".zip"))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
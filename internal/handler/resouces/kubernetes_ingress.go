@@ -7,6 +7,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types/ingress"
 )
 
@@ -44,7 +45,7 @@ func (h *ingressHandler) CreateOrUpdateIngress(c *gin.Context) {
 		return
 	}
 
-	err = controller.NewIngressController().CreateOrUpdateIngress(c.Request.Context(), reqParam)
+	err = controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateIngress(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("Create error", logger.Err(err), logger.Any("reqParam", reqParam), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
@@ -60,7 +61,7 @@ func (h *ingressHandler) GetIngressDetail(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  and name 不能为空")
 		return
 	}
-	ingressDetail, err := controller.NewIngressController().GetIngressDetail(c.Request.Context(), namespace, name)
+	ingressDetail, err := controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetIngressDetail(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("GetIngressDetail error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
@@ -79,15 +80,20 @@ func (h *ingressHandler) GetIngressList(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace 不能为空")
 		return
 	}
-	ingressList, err := controller.NewIngressController().GetIngressList(c.Request.Context(), namespace)
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetIngressList error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	ingressRes := &ingress.IngressListReply{
-		Data: struct{ List []*ingress.IngressRes }{List: ingressList},
+	ingressList, listMeta, err := controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetIngressList(c.Request.Context(), namespace, query)
+	if err != nil {
+		logger.Error("GetIngressList error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+	ingressRes := &ingress.IngressListReply{}
+	ingressRes.Data.List = ingressList
+	ingressRes.Data.ListMeta = *listMeta
 	response.Success(c, ingressRes)
 }
 
@@ -98,7 +104,7 @@ func (h *ingressHandler) DeleteIngress(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  and name 不能为空")
 		return
 	}
-	err := controller.NewIngressController().DetIngress(c.Request.Context(), namespace, name)
+	err := controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DetIngress(c.Request.Context(), namespace, name)
 
 	if err != nil {
 		logger.Error("Create error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
@@ -116,7 +122,7 @@ func (h *ingressHandler) CreateOrUpdateIngRoute(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  and name 不能为空")
 		return
 	}
-	err = controller.NewIngressController().CreateOrUpdateRoute(c.Request.Context(), reqParam)
+	err = controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateRoute(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("Create error", logger.Err(err), logger.Any("reqParam", reqParam), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
@@ -134,7 +140,7 @@ func (h *ingressHandler) GetIngRouteDetail(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  and name 不能为空")
 		return
 	}
-	ingressRoute, err := controller.NewIngressController().GetIngRouteDetail(c.Request.Context(), namespace, name)
+	ingressRoute, err := controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetIngRouteDetail(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("GetIngRouteDetail error", logger.Err(err), logger.Any("reqParam", name), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
@@ -148,37 +154,46 @@ func (h *ingressHandler) GetIngRouteDetail(c *gin.Context) {
 
 func (h *ingressHandler) GetIngRouteList(c *gin.Context) {
 	namespace := c.Param("namespace")
-	keyword := c.Query("keyword")
 	if namespace == "" {
 		response.Error(c, ecode.InvalidParams, "namespace 不能为空")
 		return
 	}
-
-	ingressRouteList, err := controller.NewIngressController().GetIngRouteList(c.Request.Context(), namespace, keyword)
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetIngRouteDetail error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
 
-	res := ingress.IngressRouteResListReply{
-		Data: struct{ Data []*ingress.IngressRouteRes }{Data: ingressRouteList},
+	ingressRouteList, listMeta, err := controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetIngRouteList(c.Request.Context(), namespace, query)
+	if err != nil {
+		logger.Error("GetIngRouteList error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+
+	res := ingress.IngressRouteResListReply{}
+	res.Data.Data = ingressRouteList
+	res.Data.ListMeta = *listMeta
 	response.Success(c, res)
 }
 
 func (h *ingressHandler) GetIngRouteMiddlewareList(c *gin.Context) {
 	namespace := c.Param("namespace")
-	list, err := controller.NewIngressController().GetIngRouteMiddlewareList(c.Request.Context(), namespace)
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetIngRouteDetail error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-
-	res := ingress.IngressRouteMiddlewareListReply{
-		Data: struct{ Data []string }{Data: list},
+	list, listMeta, err := controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetIngRouteMiddlewareList(c.Request.Context(), namespace, query)
+	if err != nil {
+		logger.Error("GetIngRouteMiddlewareList error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+
+	res := ingress.IngressRouteMiddlewareListReply{}
+	res.Data.Data = list
+	res.Data.ListMeta = *listMeta
 	response.Success(c, res)
 }
 
@@ -186,7 +201,7 @@ func (h *ingressHandler) DeleteIngRoute(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
 
-	err := controller.NewIngressController().DeleteIngRoute(c.Request.Context(), namespace, name)
+	err := controller.NewIngressController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteIngRoute(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("DeleteIngRoute error", logger.Err(err), logger.Any("reqParam", namespace), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
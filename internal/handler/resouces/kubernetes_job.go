@@ -0,0 +1,160 @@
+package resouces
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ JobHandler = (*jobHandler)(nil)
+
+// JobHandler defining the handler interface
+type JobHandler interface {
+	CreateOrUpdateJob(c *gin.Context)
+	GetJobDetail(c *gin.Context)
+	GetJobList(c *gin.Context)
+	GetJobPods(c *gin.Context)
+	DeleteJob(c *gin.Context)
+}
+
+type jobHandler struct {
+}
+
+func NewJobHandler() JobHandler {
+	return &jobHandler{}
+}
+
+// CreateOrUpdateJob 创建或更新job
+// @Summary CreateOrUpdateJob 创建或更新job
+// @Description 更新时只修改 labels/parallelism/activeDeadlineSeconds/ttlSecondsAfterFinished, 其余字段创建后不能修改
+// @Tags job
+// @Accept json
+// @Produce json
+// @Param data body types.JobRequest true "请求参数"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/job [post]
+// @Security BearerAuth
+func (h *jobHandler) CreateOrUpdateJob(c *gin.Context) {
+	reqParam := &types.JobRequest{}
+	err := c.ShouldBindJSON(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	err = controller.NewJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateJob(c.Request.Context(), reqParam)
+	if err != nil {
+		logger.Error("CreateOrUpdateJob error", logger.Err(err), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// GetJobDetail job详情
+// @Summary GetJobDetail job详情
+// @Tags job
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.JobDetailReply{}
+// @Router /api/v1/k8s/{cluster}/job/{namespace}/{name} [get]
+// @Security BearerAuth
+func (h *jobHandler) GetJobDetail(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	res, err := controller.NewJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetJobDetail(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("GetJobDetail error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	detail := types.JobDetailReply{}
+	detail.Data.Job = res
+	response.Success(c, detail)
+}
+
+// GetJobList job列表
+// @Summary GetJobList job列表
+// @Description 列表中包含运行中、成功、失败的pod数
+// @Tags job
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
+// @Success 200 {object} types.JobListReply{}
+// @Router /api/v1/k8s/{cluster}/job/{namespace} [get]
+// @Security BearerAuth
+func (h *jobHandler) GetJobList(c *gin.Context) {
+	namespace := c.Param("namespace")
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+
+	resList, listMeta, err := controller.NewJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetJobList(c.Request.Context(), namespace, query)
+	if err != nil {
+		logger.Error("GetJobList error", logger.Err(err), logger.String("namespace", namespace), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.JobListReply{}
+	res.Data.List = resList
+	res.Data.ListMeta = *listMeta
+	response.Success(c, res)
+}
+
+// GetJobPods job中每个pod的执行结果
+// @Summary GetJobPods job中每个pod的执行结果
+// @Tags job
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.JobPodsReply{}
+// @Router /api/v1/k8s/{cluster}/job/{namespace}/{name}/pods [get]
+// @Security BearerAuth
+func (h *jobHandler) GetJobPods(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	pods, err := controller.NewJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetJobPods(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("GetJobPods error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.JobPodsReply{}
+	res.Data.List = pods
+	response.Success(c, res)
+}
+
+// DeleteJob 删除job及其创建的pod
+// @Summary DeleteJob 删除job
+// @Tags job
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/job/{namespace}/{name} [delete]
+// @Security BearerAuth
+func (h *jobHandler) DeleteJob(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	err := controller.NewJobController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteJob(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("DeleteJob error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
@@ -0,0 +1,89 @@
+package resouces
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/podsecurity"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ LintHandler = (*lintHandler)(nil)
+
+// LintHandler defining the handler interface
+type LintHandler interface {
+	LintPod(c *gin.Context)
+}
+
+type lintHandler struct {
+}
+
+func NewLintHandler() LintHandler {
+	return &lintHandler{}
+}
+
+// LintPod 按pod安全标准检查pod定义, 不提交到集群
+// @Summary LintPod 检查pod安全标准
+// @Description 按 Pod Security Standards 检查pod定义, 返回不满足的字段路径; level 为空时使用 base.namespace 的 pod-security.kubernetes.io/* 标签, 都没有时按 baseline 检查, 但与准入插件一致按 privileged 强制, allowed 总是为true
+// @Tags pod
+// @Accept json
+// @Produce json
+// @Param level query string false "privileged/baseline/restricted"
+// @Param data body types.Pod true "pod information"
+// @Success 200 {object} types.LintPodReply{}
+// @Router /api/v1/k8s/{cluster}/lint/pod [post]
+// @Security BearerAuth
+func (h *lintHandler) LintPod(c *gin.Context) {
+	podReq := &types.Pod{}
+	err := c.ShouldBindJSON(podReq)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	query := &types.LintPodRequest{}
+	if err = c.ShouldBindQuery(query); err != nil || (query.Level != "" && !podsecurity.ValidLevel(query.Level)) {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	result, err := controller.NewPodSecurityController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CheckPod(c.Request.Context(), podReq, podReq.Base.Namespace, query.Level)
+	if err != nil {
+		logger.Error("CheckPod error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.LintPodReply{}
+	res.Data.Result = result
+	response.Success(c, res)
+}
+
+// checkPodSecurity 提交工作负载前按pod安全标准检查, 未请求检查时返回 nil
+// 不满足强制级别或检查失败时已写入响应, ok 为 false
+func checkPodSecurity(c *gin.Context, podReq *types.Pod, namespace string, query types.PodSecurityQuery) (*types.PodSecurityResult, bool) {
+	if !query.PodSecurityCheck {
+		return nil, true
+	}
+	if query.PodSecurityLevel != "" && !podsecurity.ValidLevel(query.PodSecurityLevel) {
+		response.Error(c, ecode.InvalidParams, "podSecurityLevel 只能为 privileged、baseline 或 restricted")
+		return nil, false
+	}
+	result, err := controller.NewPodSecurityController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CheckPod(c.Request.Context(), podReq, namespace, query.PodSecurityLevel)
+	if err != nil {
+		logger.Error("CheckPod error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return nil, false
+	}
+	if !result.Allowed {
+		response.Error(c, ecode.ErrPodSecurityViolation, result)
+		return nil, false
+	}
+	return result, true
+}
@@ -0,0 +1,140 @@
+package resouces
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ MetricsHandler = (*metricsHandler)(nil)
+
+// MetricsHandler defining the handler interface
+type MetricsHandler interface {
+	GetPodMetrics(c *gin.Context)
+	GetDeploymentMetrics(c *gin.Context)
+	GetNodeMetrics(c *gin.Context)
+	GetNamespaceMetrics(c *gin.Context)
+}
+
+type metricsHandler struct {
+}
+
+func NewMetricsHandler() MetricsHandler {
+	return &metricsHandler{}
+}
+
+type metricsQueryFunc func(ctl *controller.MetricsController, req *types.MetricsRequest) ([]*types.MetricsSeries, error)
+
+// GetPodMetrics pod监控数据
+// @Summary GetPodMetrics pod监控数据
+// @Description 返回 cpu、内存、网络收发的时间序列
+// @Tags metrics
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Param query query types.MetricsRequest false "时间范围"
+// @Success 200 {object} types.MetricsReply{}
+// @Router /api/v1/k8s/{cluster}/metrics/pod/{namespace}/{name} [get]
+// @Security BearerAuth
+func (h *metricsHandler) GetPodMetrics(c *gin.Context) {
+	namespace, name := c.Param("namespace"), c.Param("name")
+	h.query(c, "GetPodMetrics", func(ctl *controller.MetricsController, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+		return ctl.GetPodMetrics(c.Request.Context(), namespace, name, req)
+	})
+}
+
+// GetDeploymentMetrics deployment监控数据
+// @Summary GetDeploymentMetrics deployment监控数据
+// @Description 返回 deployment 所有pod用量之和的时间序列
+// @Tags metrics
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Param query query types.MetricsRequest false "时间范围"
+// @Success 200 {object} types.MetricsReply{}
+// @Router /api/v1/k8s/{cluster}/metrics/deployment/{namespace}/{name} [get]
+// @Security BearerAuth
+func (h *metricsHandler) GetDeploymentMetrics(c *gin.Context) {
+	namespace, name := c.Param("namespace"), c.Param("name")
+	h.query(c, "GetDeploymentMetrics", func(ctl *controller.MetricsController, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+		return ctl.GetDeploymentMetrics(c.Request.Context(), namespace, name, req)
+	})
+}
+
+// GetNodeMetrics node监控数据
+// @Summary GetNodeMetrics node监控数据
+// @Tags metrics
+// @Produce json
+// @Param name path string true "node名称"
+// @Param query query types.MetricsRequest false "时间范围"
+// @Success 200 {object} types.MetricsReply{}
+// @Router /api/v1/k8s/{cluster}/metrics/node/{name} [get]
+// @Security BearerAuth
+func (h *metricsHandler) GetNodeMetrics(c *gin.Context) {
+	name := c.Param("name")
+	h.query(c, "GetNodeMetrics", func(ctl *controller.MetricsController, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+		return ctl.GetNodeMetrics(c.Request.Context(), name, req)
+	})
+}
+
+// GetNamespaceMetrics namespace监控数据
+// @Summary GetNamespaceMetrics namespace监控数据
+// @Description 返回命名空间下所有pod用量之和的时间序列
+// @Tags metrics
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param query query types.MetricsRequest false "时间范围"
+// @Success 200 {object} types.MetricsReply{}
+// @Router /api/v1/k8s/{cluster}/metrics/namespace/{namespace} [get]
+// @Security BearerAuth
+func (h *metricsHandler) GetNamespaceMetrics(c *gin.Context) {
+	namespace := c.Param("namespace")
+	h.query(c, "GetNamespaceMetrics", func(ctl *controller.MetricsController, req *types.MetricsRequest) ([]*types.MetricsSeries, error) {
+		return ctl.GetNamespaceMetrics(c.Request.Context(), namespace, req)
+	})
+}
+
+func (h *metricsHandler) query(c *gin.Context, action string, fn metricsQueryFunc) {
+	reqParam := &types.MetricsRequest{}
+	err := c.ShouldBindQuery(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctl := controller.NewMetricsController(cluster.KubeConfigSet(c), cluster.InformerCache(c))
+	ctl.Cluster = cluster.FromContext(c).Name
+	list, err := fn(ctl, reqParam)
+	var status apierrors.APIStatus
+	switch {
+	case errors.Is(err, controller.ErrMetricsDisabled):
+		response.Error(c, ecode.ErrPrometheusDisabled)
+		return
+	case errors.Is(err, controller.ErrInvalidMetricsRange):
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	case errors.As(err, &status):
+		// 查询前获取 deployment 等资源失败
+		logger.Warn(action+" error", logger.Err(err), logger.String("path", c.Request.URL.Path), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	case err != nil:
+		logger.Error(action+" error", logger.Err(err), logger.String("path", c.Request.URL.Path), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrPrometheusQuery, err.Error())
+		return
+	}
+
+	res := types.MetricsReply{}
+	res.Data.List = list
+	response.Success(c, res)
+}
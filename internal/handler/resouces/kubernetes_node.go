@@ -1,6 +1,7 @@
 package resouces
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
@@ -8,6 +9,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 )
 
@@ -19,6 +21,11 @@ type NodeHandler interface {
 	GetNodeList(c *gin.Context)
 	UpdateNodeLabel(c *gin.Context)
 	UpdateNodeTaint(c *gin.Context)
+	CordonNode(c *gin.Context)
+	UncordonNode(c *gin.Context)
+	DrainNode(c *gin.Context)
+	GetDrainTask(c *gin.Context)
+	CancelDrain(c *gin.Context)
 }
 
 type nodeHandler struct {
@@ -47,7 +54,7 @@ func (n *nodeHandler) GetNodeDetail(c *gin.Context) {
 	reqParam := &types.NodeDetailRequest{
 		NodeName: nodeName,
 	}
-	detail, err := controller.NewNodeController().GetNodeDetail(c.Request.Context(), reqParam)
+	detail, err := controller.NewNodeController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetNodeDetail(c.Request.Context(), reqParam)
 
 	if err != nil {
 		logger.Error("GetNodeDetail error", logger.Err(err), logger.Any("parmm", reqParam), middleware.GCtxRequestIDField(c))
@@ -68,25 +75,25 @@ func (n *nodeHandler) GetNodeDetail(c *gin.Context) {
 // @Tags node
 // @Accept json
 // @Produce json
-// @Param keyword query string true "关键字"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} types.ListNodeReply{}
 // @Router /api/v1/k8s/node [get]
 // @Security BearerAuth
 func (n *nodeHandler) GetNodeList(c *gin.Context) {
-	keyWord := c.Query("keyword")
-	reqParam := &types.NodeListRequest{}
-	reqParam.KeyWord = keyWord
-	list, err := controller.NewNodeController().GetNodeList(c.Request.Context(), reqParam)
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetNodeList error", logger.Err(err), logger.Any("parmm", reqParam), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	res := types.ListNodeReply{
-		Data: struct {
-			Nodes []*types.Node `json:"nodes"`
-		}{Nodes: list},
+	list, listMeta, err := controller.NewNodeController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetNodeList(c.Request.Context(), query)
+	if err != nil {
+		logger.Error("GetNodeList error", logger.Err(err), logger.Any("parmm", query), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+	res := types.ListNodeReply{}
+	res.Data.Nodes = list
+	res.Data.ListMeta = *listMeta
 	response.Success(c, res)
 }
 
@@ -115,7 +122,7 @@ func (n *nodeHandler) UpdateNodeLabel(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "labels 不能为空")
 		return
 	}
-	err = controller.NewNodeController().UpdateNodeLabel(c.Request.Context(), reqParam)
+	err = controller.NewNodeController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).UpdateNodeLabel(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("UpdateNodeLabel error", logger.Err(err), logger.Any("reqParam", reqParam), logger.String("msg", err.Error()), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -145,7 +152,7 @@ func (n *nodeHandler) UpdateNodeTaint(c *gin.Context) {
 		return
 	}
 
-	err = controller.NewNodeController().UpdateNodeTaint(c.Request.Context(), reqParam)
+	err = controller.NewNodeController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).UpdateNodeTaint(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("UpdateNodeTaint error", logger.Err(err), logger.Any("reqParam", reqParam), logger.String("msg", err.Error()), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -153,3 +160,120 @@ func (n *nodeHandler) UpdateNodeTaint(c *gin.Context) {
 	}
 	response.Success(c)
 }
+
+// CordonNode 设置节点不可调度
+// @Summary CordonNode 设置节点不可调度
+// @Description 已经运行的pod不受影响
+// @Tags node
+// @Produce json
+// @Param name path string true "node名称"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/node/{name}/cordon [put]
+// @Security BearerAuth
+func (n *nodeHandler) CordonNode(c *gin.Context) {
+	n.setUnschedulable(c, true)
+}
+
+// UncordonNode 恢复节点调度
+// @Summary UncordonNode 恢复节点调度
+// @Tags node
+// @Produce json
+// @Param name path string true "node名称"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/node/{name}/uncordon [put]
+// @Security BearerAuth
+func (n *nodeHandler) UncordonNode(c *gin.Context) {
+	n.setUnschedulable(c, false)
+}
+
+func (n *nodeHandler) setUnschedulable(c *gin.Context, unschedulable bool) {
+	name := c.Param("name")
+	err := controller.NewNodeController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CordonNode(c.Request.Context(), name, unschedulable)
+	if err != nil {
+		logger.Error("CordonNode error", logger.Err(err), logger.String("name", name), logger.Bool("unschedulable", unschedulable), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+	response.Success(c, true)
+}
+
+// DrainNode 驱逐节点上的pod
+// @Summary DrainNode 驱逐节点上的pod
+// @Description 先设置节点不可调度, 再通过 Eviction API 驱逐pod, 遵守 PodDisruptionBudget, 跳过 daemonset 和静态pod;
+// @Description 任务在后台执行, 通过 GET /node/{name}/drain 查询进度
+// @Tags node
+// @Accept json
+// @Produce json
+// @Param name path string true "node名称"
+// @Param data body types.NodeDrainRequest true "请求参数"
+// @Success 200 {object} types.NodeDrainReply{}
+// @Router /api/v1/k8s/{cluster}/node/{name}/drain [post]
+// @Security BearerAuth
+func (n *nodeHandler) DrainNode(c *gin.Context) {
+	name := c.Param("name")
+	reqParam := &types.NodeDrainRequest{}
+	err := c.ShouldBindJSON(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	task, err := controller.NewNodeController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DrainNode(c.Request.Context(), cluster.FromContext(c).Name, name, reqParam)
+	if errors.Is(err, controller.ErrNodeDraining) {
+		response.Error(c, ecode.ErrNodeDraining)
+		return
+	}
+	if err != nil {
+		logger.Error("DrainNode error", logger.Err(err), logger.String("name", name), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+	logger.Info("DrainNode started", logger.String("name", name), logger.String("task", task.ID), logger.String("operator", operator(c)), middleware.GCtxRequestIDField(c))
+
+	res := types.NodeDrainReply{}
+	res.Data.Task = task
+	response.Success(c, res)
+}
+
+// GetDrainTask 查询驱逐进度
+// @Summary GetDrainTask 查询驱逐进度
+// @Description 返回节点最近一次驱逐任务及每个pod的驱逐状态
+// @Tags node
+// @Produce json
+// @Param name path string true "node名称"
+// @Success 200 {object} types.NodeDrainReply{}
+// @Router /api/v1/k8s/{cluster}/node/{name}/drain [get]
+// @Security BearerAuth
+func (n *nodeHandler) GetDrainTask(c *gin.Context) {
+	task, ok := controller.NewNodeController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetDrainTask(cluster.FromContext(c).Name, c.Param("name"))
+	if !ok {
+		response.Error(c, ecode.ErrDrainTaskNotFound)
+		return
+	}
+
+	res := types.NodeDrainReply{}
+	res.Data.Task = task
+	response.Success(c, res)
+}
+
+// CancelDrain 取消驱逐
+// @Summary CancelDrain 取消驱逐
+// @Description 已经提交驱逐的pod不会恢复, 节点保持不可调度
+// @Tags node
+// @Produce json
+// @Param name path string true "node名称"
+// @Success 200 {object} types.NodeDrainReply{}
+// @Router /api/v1/k8s/{cluster}/node/{name}/drain [delete]
+// @Security BearerAuth
+func (n *nodeHandler) CancelDrain(c *gin.Context) {
+	task, ok := controller.NewNodeController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CancelDrain(cluster.FromContext(c).Name, c.Param("name"))
+	if !ok {
+		response.Error(c, ecode.ErrDrainTaskNotFound)
+		return
+	}
+
+	res := types.NodeDrainReply{}
+	res.Data.Task = task
+	response.Success(c, res)
+}
@@ -0,0 +1,61 @@
+package resouces
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ PermissionHandler = (*permissionHandler)(nil)
+
+// PermissionHandler defining the handler interface
+type PermissionHandler interface {
+	CheckPermission(c *gin.Context)
+}
+
+type permissionHandler struct {
+}
+
+func NewPermissionHandler() PermissionHandler {
+	return &permissionHandler{}
+}
+
+// CheckPermission 批量检查当前用户的权限, 用于前端隐藏没有权限的操作
+// @Summary CheckPermission 批量检查当前用户的权限
+// @Description 集群级资源(node/pv/sc)的 namespace 为空
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Param data body types.CheckPermissionRequest true "请求参数"
+// @Success 200 {object} types.CheckPermissionReply{}
+// @Router /api/v1/k8s/{cluster}/permission/check [post]
+// @Security BearerAuth
+func (h *permissionHandler) CheckPermission(c *gin.Context) {
+	reqParam := &types.CheckPermissionRequest{}
+	err := c.ShouldBindJSON(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	perms := permission.FromContext(c)
+	clusterName := c.Param("cluster")
+	items := make([]*types.PermissionCheckResult, 0, len(reqParam.Items))
+	for _, item := range reqParam.Items {
+		items = append(items, &types.PermissionCheckResult{
+			PermissionCheckItem: item,
+			Allowed:             perms.Allowed(clusterName, item.Namespace, item.Kind, item.Verb),
+		})
+	}
+
+	res := types.CheckPermissionReply{}
+	res.Data.Admin = perms.IsAdmin()
+	res.Data.Items = items
+	response.Success(c, res)
+}
@@ -0,0 +1,56 @@
+package resouces
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ PriorityClassHandler = (*priorityClassHandler)(nil)
+
+// PriorityClassHandler defining the handler interface
+type PriorityClassHandler interface {
+	GetPriorityClassList(c *gin.Context)
+}
+
+type priorityClassHandler struct {
+}
+
+func NewPriorityClassHandler() PriorityClassHandler {
+	return &priorityClassHandler{}
+}
+
+// GetPriorityClassList 获取PriorityClass列表
+// @Summary GetPriorityClassList 获取PriorityClass列表
+// @Description 获取PriorityClass列表, 用于选择pod的 priorityClassName
+// @Tags pod
+// @Accept json
+// @Produce json
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
+// @Success 200 {object} types.PriorityClassListReply
+// @Router /api/v1/k8s/{cluster}/priorityclass/list [get]
+// @Security BearerAuth
+func (h *priorityClassHandler) GetPriorityClassList(c *gin.Context) {
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+	list, listMeta, err := controller.NewPriorityClassController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetPriorityClassList(c.Request.Context(), query)
+	if err != nil {
+		logger.Error("GetPriorityClassList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.PriorityClassListReply{}
+	res.Data.List = list
+	res.Data.ListMeta = *listMeta
+	response.Success(c, res)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 )
 
@@ -51,7 +52,7 @@ func (h *pvHandler) CreatePv(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	err = controller.NewPvController().Createpv(c.Request.Context(), reqParam)
+	err = controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).Createpv(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("CreateOrUpdateConfigMap error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -67,22 +68,26 @@ func (h *pvHandler) CreatePv(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param namespace query string  true "namespace"
-// @Param keyword query string  true "keyword"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} types.PersistentVolumeResListReply
 // @Router /api/v1/k8s/pv/list [get]
 // @Security BearerAuth
 func (h *pvHandler) GetPvList(c *gin.Context) {
-	keyword := c.Query("keyword")
-	list, err := controller.NewPvController().GetPvList(c.Request.Context(), keyword)
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+	list, listMeta, err := controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetPvList(c.Request.Context(), query)
 
 	if err != nil {
 		logger.Error("GetPvList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
-	resList := types.PersistentVolumeResListReply{
-		Data: struct{ List []*types.PersistentVolumeRes }{List: list},
-	}
+	resList := types.PersistentVolumeResListReply{}
+	resList.Data.List = list
+	resList.Data.ListMeta = *listMeta
 	response.Success(c, resList)
 }
 
@@ -102,7 +107,7 @@ func (h *pvHandler) DeletePV(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, " name 不能为空")
 		return
 	}
-	err := controller.NewPvController().DeletePV(c.Request.Context(), name)
+	err := controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeletePV(c.Request.Context(), name)
 	if err != nil {
 		logger.Error("DeletePV error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -129,7 +134,7 @@ func (h *pvHandler) CreatePVC(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	err := controller.NewPvController().CreatePVC(c.Request.Context(), reqParam)
+	err := controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreatePVC(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("CreatePVC error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -145,7 +150,7 @@ func (h *pvHandler) CreatePVC(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param namespace query string  true "namespace"
-// @Param keyword query string  true "keyword"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} types.PersistentVolumeClaimResListReply
 // @Router /api/v1/k8s/pvc/{namespace} [get]
 // @Security BearerAuth
@@ -157,18 +162,20 @@ func (h *pvHandler) GetPVCList(c *gin.Context) {
 		return
 	}
 
-	keyworkd := c.Query("keyword")
-	list, err := controller.NewPvController().GetPVCList(c.Request.Context(), namespace, keyworkd)
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetPvList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	resList := types.PersistentVolumeClaimResListReply{
-		Data: struct {
-			List []*types.PersistentVolumeClaimRes
-		}{List: list},
+	list, listMeta, err := controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetPVCList(c.Request.Context(), namespace, query)
+	if err != nil {
+		logger.Error("GetPVCList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+	resList := types.PersistentVolumeClaimResListReply{}
+	resList.Data.List = list
+	resList.Data.ListMeta = *listMeta
 	response.Success(c, resList)
 }
 
@@ -192,7 +199,7 @@ func (h *pvHandler) DeletePVC(c *gin.Context) {
 		return
 	}
 
-	err := controller.NewPvController().DeletePVC(c.Request.Context(), namespace, name)
+	err := controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeletePVC(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("DeletePVC error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -219,7 +226,7 @@ func (h *pvHandler) CreateSC(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	err := controller.NewPvController().CreateSC(c.Request.Context(), reqParam)
+	err := controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateSC(c.Request.Context(), reqParam)
 
 	if err != nil {
 		logger.Error("DeletePVC error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
@@ -236,24 +243,26 @@ func (h *pvHandler) CreateSC(c *gin.Context) {
 // @Tags pv
 // @Accept json
 // @Produce json
-// @Param keyword query string  true "keyword"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} types.StorageClassListReply
 // @Router /api/v1/k8s/sc/list [get]
 // @Security BearerAuth
 func (h *pvHandler) GetSCList(c *gin.Context) {
-	keyword := c.Query("keyword")
-	list, err := controller.NewPvController().GetSCList(c.Request.Context(), keyword)
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+	list, listMeta, err := controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetSCList(c.Request.Context(), query)
 	if err != nil {
 		logger.Error("GetSCList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
 
-	resList := types.StorageClassListReply{
-		Data: struct {
-			List []*types.StorageClassRes `json:"list"`
-		}{List: list},
-	}
+	resList := types.StorageClassListReply{}
+	resList.Data.List = list
+	resList.Data.ListMeta = *listMeta
 	response.Success(c, resList)
 }
 
@@ -276,7 +285,7 @@ func (h *pvHandler) DeleteSC(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, " namespace 不能为空")
 		return
 	}
-	err := controller.NewPvController().DeleteSC(c.Request.Context(), name)
+	err := controller.NewPvController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteSC(c.Request.Context(), name)
 	if err != nil {
 		logger.Error("DeletePVC error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
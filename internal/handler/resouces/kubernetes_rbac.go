@@ -7,6 +7,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types/rbac"
 )
 
@@ -36,27 +37,31 @@ func NewRBACHandler() RBACHandler {
 // @Accept json
 // @Produce json
 // @Param namespace query string struct true "namespace"
-// @Param name query string struct true "name"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} types.ServiceAccountReply
 // @Router /api/v1/k8s/sa/{namespace}
 // @Security BearerAuth
 func (r *rbacHander) GetServiceAccountList(c *gin.Context) {
 	namespace := c.Param("namespace")
-	name := c.Query("name")
 
-	if namespace == "" || name == "" {
-		response.Error(c, ecode.InvalidParams, "namespace and name 不能为空")
+	if namespace == "" {
+		response.Error(c, ecode.InvalidParams, "namespace 不能为空")
 		return
 	}
-	list, err := controller.NewRbacController().ServiceAccounts(c.Request.Context(), namespace, name)
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetConfigMapDetail error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	resList := rbac.ServiceAccountReply{
-		Data: struct{ List []*rbac.ServiceAccount }{List: list},
+	list, listMeta, err := controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).ServiceAccounts(c.Request.Context(), namespace, query)
+	if err != nil {
+		logger.Error("GetServiceAccountList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+	resList := rbac.ServiceAccountReply{}
+	resList.Data.List = list
+	resList.Data.ListMeta = *listMeta
 	response.Success(c, resList)
 }
 
@@ -78,7 +83,7 @@ func (r *rbacHander) CreateServiceAccount(c *gin.Context) {
 		return
 	}
 
-	if err := controller.NewRbacController().CreateServiceAccount(c.Request.Context(), reqParam); err != nil {
+	if err := controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateServiceAccount(c.Request.Context(), reqParam); err != nil {
 		logger.Error("CreateServiceAccount error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
 		return
@@ -107,7 +112,7 @@ func (h *rbacHander) DeleteServiceAccount(c *gin.Context) {
 		return
 	}
 
-	err := controller.NewRbacController().DeleteServiceAccount(c.Request.Context(), namespace, name)
+	err := controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteServiceAccount(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("DeleteServiceAccount error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -136,7 +141,7 @@ func (h *rbacHander) GetRoleDetail(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "name 不能为空")
 		return
 	}
-	rbacDetail, err := controller.NewRbacController().GetRoleDetail(c.Request.Context(), namespace, name)
+	rbacDetail, err := controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetRoleDetail(c.Request.Context(), namespace, name)
 
 	if err != nil {
 		logger.Error("GetRoleDetail error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
@@ -156,22 +161,27 @@ func (h *rbacHander) GetRoleDetail(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param namespace query string struct true "namespace"
-// @Param name query string struct true "name"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} rbac.RoleResListReply
 // @Router /api/v1/k8s/roles
 // @Security BearerAuth
 func (h *rbacHander) GetRoleList(c *gin.Context) {
 	namespace := c.Param("namespace")
-	roleList, err := controller.NewRbacController().GetRoleList(c.Request.Context(), namespace)
-
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetRoleDetail error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	roleResList := rbac.RoleResListReply{
-		Data: struct{ List []*rbac.Role }{List: roleList},
+	roleList, listMeta, err := controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetRoleList(c.Request.Context(), namespace, query)
+
+	if err != nil {
+		logger.Error("GetRoleList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+	roleResList := rbac.RoleResListReply{}
+	roleResList.Data.List = roleList
+	roleResList.Data.ListMeta = *listMeta
 	response.Success(c, roleResList)
 }
 
@@ -185,7 +195,7 @@ func (h *rbacHander) CreateOrUpdateRole(c *gin.Context) {
 		return
 	}
 
-	err = controller.NewRbacController().CreateOrUpdateRole(c.Request.Context(), reqParam)
+	err = controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateRole(c.Request.Context(), reqParam)
 
 	if err != nil {
 		logger.Error("DeleteServiceAccount error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
@@ -204,7 +214,7 @@ func (h *rbacHander) DeleteRoleBingding(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "name 不能为空")
 		return
 	}
-	err := controller.NewRbacController().DeleteRoleBindgs(c.Request.Context(), namespace, name)
+	err := controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteRoleBindgs(c.Request.Context(), namespace, name)
 
 	if err != nil {
 		logger.Error("DeleteRoleBingding error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
@@ -219,7 +229,7 @@ func (h *rbacHander) GetRolbingDetail(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	rb, err := controller.NewRbacController().GetRbDetail(c.Request.Context(), namespace, name)
+	rb, err := controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetRbDetail(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("DeleteRoleBingding error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err.Error())
@@ -230,15 +240,22 @@ func (h *rbacHander) GetRolbingDetail(c *gin.Context) {
 
 func (h *rbacHander) GetRolbingList(c *gin.Context) {
 	namespace := c.Param("namespace")
-	name := c.Query("name")
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
 
-	rb, err := controller.NewRbacController().GetRbList(c.Request.Context(), namespace, name)
+	rb, listMeta, err := controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetRbList(c.Request.Context(), namespace, query)
 	if err != nil {
 		logger.Error("GetRolbingList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
-	response.Success(c, rb)
+	rbResList := rbac.RoleBindingResListReply{}
+	rbResList.Data.List = rb
+	rbResList.Data.ListMeta = *listMeta
+	response.Success(c, rbResList)
 }
 
 func (h *rbacHander) CreateOrUpdateRoleBingding(c *gin.Context) {
@@ -250,7 +267,7 @@ func (h *rbacHander) CreateOrUpdateRoleBingding(c *gin.Context) {
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
 		return
 	}
-	err = controller.NewRbacController().CreateOrUpdateRolebing(c.Request.Context(), reqParam)
+	err = controller.NewRbacController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateRolebing(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("CreateOrUpdateRoleBingding error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
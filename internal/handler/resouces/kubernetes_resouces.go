@@ -1,6 +1,7 @@
 package resouces
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
@@ -8,7 +9,10 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
 	"github.com/xiaofan193/k8sadmin/internal/types"
+	"net/http"
 )
 
 var _ ResoucesHandler = (*resoucesHandler)(nil)
@@ -19,6 +23,7 @@ type ResoucesHandler interface {
 	GetPodList(c *gin.Context)
 	GetPodDetail(c *gin.Context)
 	DeletePod(c *gin.Context)
+	GetPodLogs(c *gin.Context)
 	GetNamespaceList(c *gin.Context)
 }
 
@@ -32,11 +37,14 @@ func NewResourceHandler() ResoucesHandler {
 
 // CreateOrUpdatePod a new pod
 // @Summary CreateOruUpdate a new pod
-// @Description Creates a new pod entity using the provided data in the request body.
+// @Description Creates a new pod, or updates the mutable fields (labels, annotations, images, activeDeadlineSeconds, new tolerations) in place. Other changes require recreate=true, otherwise the changes are returned with an error.
 // @Tags pod
 // @Accept json
 // @Produce json
 // @Param data body types.Pod true "pod information"
+// @Param recreate query bool false "allow deleting and recreating the pod"
+// @Param podSecurityCheck query bool false "check the pod security standard before submitting, rejected when the enforce level is violated"
+// @Param podSecurityLevel query string false "privileged/baseline/restricted, defaults to the namespace pod-security.kubernetes.io/* labels"
 // @Success 200 {object} types.CreateOrUpdatePodReply{}
 // @Router /api/v1/k8s/pod [post]
 // @Security BearerAuth
@@ -48,6 +56,12 @@ func (h *resoucesHandler) CreateOrUpdatePod(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams)
 		return
 	}
+	query := &types.CreateOrUpdatePodRequest{}
+	if err = c.ShouldBindQuery(query); err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
 	// 校验必填项
 	podValidate := PodValidate{}
 	if err = podValidate.Validate(podReq); err != nil {
@@ -55,15 +69,26 @@ func (h *resoucesHandler) CreateOrUpdatePod(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, err)
 		return
 	}
+	podSecurity, ok := checkPodSecurity(c, podReq, podReq.Base.Namespace, query.PodSecurityQuery)
+	if !ok {
+		return
+	}
 	ctxg := c.Request.Context()
-	msg, err := controller.NewPodController().CreateOrUpdatePod(ctxg, podReq)
+	result, err := controller.NewPodController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdatePod(ctxg, podReq, query.Recreate)
+	if errors.Is(err, controller.ErrPodRecreateRequired) {
+		response.Error(c, ecode.ErrRecreatePod, result)
+		return
+	}
 	if err != nil {
-		logger.Error("Create error", logger.Err(err), logger.Any("podReq", podReq), logger.String("msg", msg), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		logger.Error("Create error", logger.Err(err), logger.Any("podReq", podReq), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
 
-	response.Success(c)
+	res := types.CreateOrUpdatePodReply{}
+	res.Data.Result = result
+	res.Data.PodSecurity = podSecurity
+	response.Success(c, res)
 }
 
 // GetPodList 获取pod列表
@@ -74,6 +99,7 @@ func (h *resoucesHandler) CreateOrUpdatePod(c *gin.Context) {
 // @Produce json
 // @Param namespace query types.GetPodListRequest true "请求参数"
 
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} types.ListPodsReply{}
 // @Router /api/v1/k8s/{namespace} [get]
 // @Security BearerAuth
@@ -81,24 +107,26 @@ func (h *resoucesHandler) GetPodList(c *gin.Context) {
 
 	reqParam := &types.GetPodListRequest{
 		Namespace: c.Param("namespace"),
-		Keyword:   c.Query("keyword"),
 		NodeName:  c.Query("nodeName"),
 	}
 	if reqParam.Namespace == "" {
 		reqParam.Namespace = "default"
 	}
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
 
-	podList, err := controller.NewPodController().GetPodList(c.Request.Context(), reqParam)
+	podList, listMeta, err := controller.NewPodController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetPodList(c.Request.Context(), reqParam, query)
 	if err != nil {
 		logger.Error("GetPodList error", logger.Err(err), logger.Any("parmm", reqParam), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
-	res := types.ListPodsReply{
-		Data: struct {
-			PodList []*types.PodListItem `json:"podList"`
-		}{PodList: podList},
-	}
+	res := types.ListPodsReply{}
+	res.Data.PodList = podList
+	res.Data.ListMeta = *listMeta
 	response.Success(c, res)
 }
 
@@ -126,7 +154,7 @@ func (h *resoucesHandler) GetPodDetail(c *gin.Context) {
 	if reqParam.Name == "" {
 		response.Error(c, ecode.InvalidParams, fmt.Errorf("pod name 不能为空"))
 	}
-	detail, err := controller.NewPodController().GetPodDetail(c.Request.Context(), reqParam)
+	detail, err := controller.NewPodController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetPodDetail(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("GetPodDetail error", logger.Err(err), logger.Any("parmm", reqParam), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
@@ -163,7 +191,7 @@ func (h *resoucesHandler) DeletePod(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, fmt.Errorf("pod name  不能为空"))
 	}
 
-	err := controller.NewPodController().DeletePod(c.Request.Context(), reqParam)
+	err := controller.NewPodController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeletePod(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Error("DeletePod error", logger.Err(err), logger.Any("parmm", reqParam), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -172,28 +200,98 @@ func (h *resoucesHandler) DeletePod(c *gin.Context) {
 	response.Success(c)
 }
 
+// GetPodLogs 查看pod日志
+// @Summary GetPodLogs 查看pod日志
+// @Description 以chunked方式输出容器日志, follow=true 时持续输出直到客户端断开; download=true 时以附件下载, 未指定容器时包含所有容器的日志
+// @Tags pod
+// @Produce plain
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Param query query types.GetPodLogsRequest false "日志参数"
+// @Success 200 {string} string "日志内容"
+// @Router /api/v1/k8s/{cluster}/pod/{namespace}/{name}/logs [get]
+// @Security BearerAuth
+func (h *resoucesHandler) GetPodLogs(c *gin.Context) {
+	reqParam := &types.GetPodLogsRequest{}
+	if err := c.ShouldBindQuery(reqParam); err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	reqParam.Namespace = c.Param("namespace")
+	reqParam.Name = c.Param("name")
+
+	podController := controller.NewPodController(cluster.KubeConfigSet(c), cluster.InformerCache(c))
+	if reqParam.Download {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", controller.PodLogsFilename(reqParam)))
+		err := podController.DownloadPodLogs(c.Request.Context(), reqParam, c.Writer)
+		if err != nil {
+			logger.Error("DownloadPodLogs error", logger.Err(err), logger.Any("parmm", reqParam), middleware.GCtxRequestIDField(c))
+			if !c.Writer.Written() {
+				c.Writer.Header().Del("Content-Disposition")
+				response.Output(c, k8sErrorCode(err), err)
+			}
+		}
+		return
+	}
+
+	stream, err := podController.GetPodLogs(c.Request.Context(), reqParam)
+	if err != nil {
+		logger.Error("GetPodLogs error", logger.Err(err), logger.Any("parmm", reqParam), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+	defer stream.Close()
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("X-Accel-Buffering", "no") // 关闭nginx缓冲
+	c.Status(http.StatusOK)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := stream.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
 // GetNamespaceList 获取namespace列表
 // @Summary GetNamespaceList 获取pod列表
 // @Description get namespace列表
 // @Tags pod
 // @Accept json
 // @Produce json
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} types.ListNamespacesReply{}
 // @Router /api/v1/k8s/namespace [get]
 // @Security BearerAuth
 func (h *resoucesHandler) GetNamespaceList(c *gin.Context) {
-	namespaceList, err := controller.NewPodController().GetNamespaceList(c.Request.Context())
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+	// 只返回当前用户可以查看资源的命名空间
+	perms := permission.FromContext(c)
+	clusterName := c.Param("cluster")
+	visible := func(namespace string) bool { return perms.NamespaceVisible(clusterName, namespace) }
+	namespaceList, listMeta, err := controller.NewPodController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetNamespaceList(c.Request.Context(), query, visible)
 
 	if err != nil {
 		logger.Error("GetNamespaceList error", logger.Err(err), logger.Any("parmm", ""), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Output(c, k8sErrorCode(err), err)
 		return
 	}
 
-	res := types.ListNamespacesReply{
-		Data: struct {
-			Namespaces []*types.Namespace `json:"namespaces"`
-		}{Namespaces: namespaceList},
-	}
+	res := types.ListNamespacesReply{}
+	res.Data.Namespaces = namespaceList
+	res.Data.ListMeta = *listMeta
 	response.Success(c, res)
 }
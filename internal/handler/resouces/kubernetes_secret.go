@@ -8,6 +8,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 )
 
@@ -45,7 +46,7 @@ func (h *scretetMapHandler) CreateOrUpdateSecret(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams)
 		return
 	}
-	err := controller.NewSecreteController().CreateOrUpdateSecret(c.Request.Context(), reqParam)
+	err := controller.NewSecreteController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateSecret(c.Request.Context(), reqParam)
 	if err != nil {
 		logger.Warn("CreateOrUpdateConfigMap error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -76,7 +77,7 @@ func (h *scretetMapHandler) GetSecretDetail(c *gin.Context) {
 		return
 
 	}
-	res, err := controller.NewSecreteController().GetSecretDetail(c.Request.Context(), namespace, name)
+	res, err := controller.NewSecreteController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetSecretDetail(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Warn("CreateOrUpdateConfigMap error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
@@ -95,7 +96,7 @@ func (h *scretetMapHandler) GetSecretDetail(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param namespace query string struct true "namespace"
-// @Param keyword query string struct true "keyword"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
 // @Success 200 {object} []types.ListKeyItemReply
 // @Router /api/v1/k8s/secret/:namespace [get]
 // @Security BearerAuth
@@ -105,19 +106,21 @@ func (h *scretetMapHandler) GetSecretList(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  不能为空")
 		return
 	}
-	keyword := c.Query("keyword")
-	list, err := controller.NewSecreteController().GetSecretList(c.Request.Context(), namespace, keyword)
-
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetConfigMapList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
-	resList := types.ListSecretItemReply{
-		Data: struct {
-			List []*types.Secret `json:"list"`
-		}{List: list},
+	list, listMeta, err := controller.NewSecreteController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetSecretList(c.Request.Context(), namespace, query)
+
+	if err != nil {
+		logger.Error("GetSecretList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+	resList := types.ListSecretItemReply{}
+	resList.Data.List = list
+	resList.Data.ListMeta = *listMeta
 	response.Success(c, resList)
 }
 
@@ -143,7 +146,7 @@ func (h *scretetMapHandler) DeleteSecret(c *gin.Context) {
 		return
 
 	}
-	err := controller.NewSecreteController().DeleteSecret(c.Request.Context(), namespace, name)
+	err := controller.NewSecreteController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteSecret(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("CreateOrUpdateConfigMap error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
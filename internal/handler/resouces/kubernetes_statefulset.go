@@ -0,0 +1,135 @@
+package resouces
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+var _ StatefulSetHandler = (*statefulSetHandler)(nil)
+
+// StatefulSetHandler defining the handler interface
+type StatefulSetHandler interface {
+	CreateOrUpdateStatefulSet(c *gin.Context)
+	GetStatefulSetDetail(c *gin.Context)
+	GetStatefulSetList(c *gin.Context)
+	DeleteStatefulSet(c *gin.Context)
+}
+
+type statefulSetHandler struct {
+}
+
+func NewStatefulSetHandler() StatefulSetHandler {
+	return &statefulSetHandler{}
+}
+
+// CreateOrUpdateStatefulSet 创建或更新statefulset
+// @Summary CreateOrUpdateStatefulSet 创建或更新statefulset
+// @Description 更新时只修改 replicas/template/partition/labels, 其余字段创建后不能修改
+// @Tags statefulset
+// @Accept json
+// @Produce json
+// @Param data body types.StatefulSetRequest true "请求参数"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/statefulset [post]
+// @Security BearerAuth
+func (h *statefulSetHandler) CreateOrUpdateStatefulSet(c *gin.Context) {
+	reqParam := &types.StatefulSetRequest{}
+	err := c.ShouldBindJSON(reqParam)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	err = controller.NewStatefulSetController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateStatefulSet(c.Request.Context(), reqParam)
+	if err != nil {
+		logger.Error("CreateOrUpdateStatefulSet error", logger.Err(err), logger.Any("form", reqParam), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
+
+// GetStatefulSetDetail statefulset详情
+// @Summary GetStatefulSetDetail statefulset详情
+// @Tags statefulset
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.StatefulSetDetailReply{}
+// @Router /api/v1/k8s/{cluster}/statefulset/{namespace}/{name} [get]
+// @Security BearerAuth
+func (h *statefulSetHandler) GetStatefulSetDetail(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	res, err := controller.NewStatefulSetController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetStatefulSetDetail(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("GetStatefulSetDetail error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	detail := types.StatefulSetDetailReply{}
+	detail.Data.StatefulSet = res
+	response.Success(c, detail)
+}
+
+// GetStatefulSetList statefulset列表
+// @Summary GetStatefulSetList statefulset列表
+// @Description 列表中包含每个序号pod的状态
+// @Tags statefulset
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param query query types.ListQuery false "列表查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue"
+// @Success 200 {object} types.StatefulSetListReply{}
+// @Router /api/v1/k8s/{cluster}/statefulset/{namespace} [get]
+// @Security BearerAuth
+func (h *statefulSetHandler) GetStatefulSetList(c *gin.Context) {
+	namespace := c.Param("namespace")
+	query, err := bindListQuery(c)
+	if err != nil {
+		response.Error(c, ecode.InvalidParams, err.Error())
+		return
+	}
+
+	resList, listMeta, err := controller.NewStatefulSetController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetStatefulSetList(c.Request.Context(), namespace, query)
+	if err != nil {
+		logger.Error("GetStatefulSetList error", logger.Err(err), logger.String("namespace", namespace), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	res := types.StatefulSetListReply{}
+	res.Data.List = resList
+	res.Data.ListMeta = *listMeta
+	response.Success(c, res)
+}
+
+// DeleteStatefulSet 删除statefulset, pvc 需要单独删除
+// @Summary DeleteStatefulSet 删除statefulset
+// @Tags statefulset
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param name path string true "name"
+// @Success 200 {object} types.Result{}
+// @Router /api/v1/k8s/{cluster}/statefulset/{namespace}/{name} [delete]
+// @Security BearerAuth
+func (h *statefulSetHandler) DeleteStatefulSet(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	err := controller.NewStatefulSetController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteStatefulSet(c.Request.Context(), namespace, name)
+	if err != nil {
+		logger.Error("DeleteStatefulSet error", logger.Err(err), logger.String("namespace", namespace), logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	response.Success(c, true)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-dev-frame/sponge/pkg/logger"
 	"github.com/xiaofan193/k8sadmin/internal/controller"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 	"github.com/xiaofan193/k8sadmin/internal/types/svc"
 )
 
@@ -48,7 +49,7 @@ func (h *svcHandler) CreateOrUpdateSvc(c *gin.Context) {
 		return
 	}
 
-	err = controller.NewSvcController().CreateOrUpdateSvc(c.Request.Context(), reqparam)
+	err = controller.NewSvcController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).CreateOrUpdateSvc(c.Request.Context(), reqparam)
 
 	if err != nil {
 		logger.Error("CreateOrUpdateSvc error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
@@ -66,7 +67,7 @@ func (h *svcHandler) GetSvcDetail(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  and name 不能为空")
 		return
 	}
-	server, err := controller.NewSvcController().GetSvcDetail(c.Request.Context(), namespace, name)
+	server, err := controller.NewSvcController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetSvcDetail(c.Request.Context(), namespace, name)
 
 	if err != nil {
 		logger.Error("GetSvcDetail error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
@@ -87,18 +88,22 @@ func (h *svcHandler) GetSvcList(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams, "namespace  不能为空")
 		return
 	}
-	keyword := c.Query("keyword")
-	serverList, err := controller.NewSvcController().GetSvcList(c.Request.Context(), namespace, keyword)
-
+	query, err := bindListQuery(c)
 	if err != nil {
-		logger.Error("GetSvcDetail error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
+		response.Error(c, ecode.InvalidParams, err.Error())
 		return
 	}
+	serverList, listMeta, err := controller.NewSvcController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).GetSvcList(c.Request.Context(), namespace, query)
 
-	resList := svc.ServerResListReply{
-		Data: struct{ List []*svc.ServiceRes }{List: serverList},
+	if err != nil {
+		logger.Error("GetSvcList error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
 	}
+
+	resList := svc.ServerResListReply{}
+	resList.Data.List = serverList
+	resList.Data.ListMeta = *listMeta
 	response.Success(c, resList)
 }
 
@@ -110,7 +115,7 @@ func (h *svcHandler) DeleteSvc(c *gin.Context) {
 		return
 	}
 
-	err := controller.NewSvcController().DeleteSvc(c.Request.Context(), namespace, name)
+	err := controller.NewSvcController(cluster.KubeConfigSet(c), cluster.InformerCache(c)).DeleteSvc(c.Request.Context(), namespace, name)
 	if err != nil {
 		logger.Error("DeleteSvc error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode(), err)
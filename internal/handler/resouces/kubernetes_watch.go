@@ -0,0 +1,95 @@
+package resouces
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/controller"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+// 没有事件时定时发送注释行, 避免代理或浏览器因空闲断开连接
+const watchKeepAlive = 30 * time.Second
+
+var _ WatchHandler = (*watchHandler)(nil)
+
+// WatchHandler defining the handler interface
+type WatchHandler interface {
+	Watch(c *gin.Context)
+}
+
+type watchHandler struct {
+}
+
+func NewWatchHandler() WatchHandler {
+	return &watchHandler{}
+}
+
+// Watch 以SSE推送资源变化
+// @Summary Watch 资源变化推送
+// @Description 以 text/event-stream 推送 pod/deployment/daemonset/node/svc/ingress 的变化, 事件id为resourceVersion, 浏览器 EventSource 断线重连时会自动带上 Last-Event-ID 从断点继续
+// @Tags watch
+// @Produce text/event-stream
+// @Param kind path string true "资源类型 pod|deployment|daemonset|node|svc|ingress"
+// @Param namespace path string false "命名空间, 不传表示所有命名空间"
+// @Param query query types.WatchRequest false "watch参数"
+// @Success 200 {object} types.WatchEvent
+// @Router /api/v1/k8s/{cluster}/watch/{kind}/{namespace} [get]
+// @Security BearerAuth
+func (h *watchHandler) Watch(c *gin.Context) {
+	req := &types.WatchRequest{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if req.ResourceVersion == "" {
+		req.ResourceVersion = c.GetHeader("Last-Event-ID")
+	}
+
+	kind := c.Param("kind")
+	events, err := controller.NewWatchController(cluster.KubeConfigSet(c)).Watch(c.Request.Context(), kind, c.Param("namespace"), req)
+	if err != nil {
+		if errors.Is(err, controller.ErrWatchKind) {
+			response.Error(c, ecode.InvalidParams, err.Error())
+			return
+		}
+		logger.Error("Watch error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, k8sErrorCode(err), err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭nginx缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(watchKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			c.Render(-1, sse.Event{Id: event.ResourceVersion, Data: event})
+			c.Writer.Flush()
+		case <-ticker.C:
+			_, _ = c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
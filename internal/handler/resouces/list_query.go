@@ -0,0 +1,24 @@
+package resouces
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/listquery"
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+// bindListQuery 解析并校验列表接口通用查询参数 keyword/page/limit/sort/labelSelector/fieldSelector/continue
+func bindListQuery(c *gin.Context) (*types.ListQuery, error) {
+	query := &types.ListQuery{}
+	if err := c.ShouldBindQuery(query); err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		return nil, err
+	}
+	if err := listquery.Validate(query); err != nil {
+		logger.Warn("ListQuery validate error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		return nil, err
+	}
+	return query, nil
+}
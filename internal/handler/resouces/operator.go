@@ -0,0 +1,12 @@
+package resouces
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+)
+
+// operator 当前操作用户, 未登录时为空
+func operator(c *gin.Context) string {
+	return c.GetString(auth.CtxUsernameKey)
+}
@@ -2,7 +2,9 @@ package resouces
 
 import (
 	"errors"
+	"fmt"
 	"github.com/xiaofan193/k8sadmin/internal/types"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
@@ -51,8 +53,36 @@ func (*PodValidate) Validate(podReq *types.Pod) error {
 			}
 		}
 	}
+	for _, volume := range podReq.Volumes {
+		if volume.Name == "" {
+			return errors.New("Volumes中发现没有定义名称的卷！")
+		}
+		if _, err := parseQuantity(volume.EmptyDirVolume.SizeLimit); err != nil {
+			return fmt.Errorf("卷%s的容量上限格式错误: %w", volume.Name, err)
+		}
+		if _, err := parseQuantity(volume.EphemeralVolume.Storage); err != nil {
+			return fmt.Errorf("卷%s的容量格式错误: %w", volume.Name, err)
+		}
+	}
+	for _, containers := range [][]types.Container{podReq.InitContainers, podReq.Containers} {
+		for _, container := range containers {
+			for _, mount := range container.VolumeMounts {
+				if mount.SubPath != "" && mount.SubPathExpr != "" {
+					return fmt.Errorf("容器%s挂载卷%s时 subPath 和 subPathExpr 只能设置一个！", container.Name, mount.MountName)
+				}
+			}
+		}
+	}
 	if podReq.Base.RestartPolicy == "" {
 		podReq.Base.RestartPolicy = RESTART_POLICY_ALWAYS
 	}
 	return nil
 }
+
+// parseQuantity 空字符串表示不设置
+func parseQuantity(value string) (resource.Quantity, error) {
+	if value == "" {
+		return resource.Quantity{}, nil
+	}
+	return resource.ParseQuantity(value)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/xiaofan193/k8sadmin/internal/database"
 	"github.com/xiaofan193/k8sadmin/internal/ecode"
 	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 )
 
@@ -70,11 +71,33 @@ func (h *userHandler) Create(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	if user.Status == 0 {
+		user.Status = model.UserStatusActive
+	}
+	if user.Type == 0 {
+		user.Type = model.UserTypeMember
+	}
+	user.Password, err = auth.HashPassword(form.Password)
+	if err != nil {
+		logger.Error("HashPassword error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrCreateUser)
+		return
+	}
 
 	ctx := middleware.WrapCtx(c)
+	_, err = h.iDao.GetByName(ctx, user.Name)
+	if err == nil {
+		response.Error(c, ecode.ErrExistUser)
+		return
+	}
+	if !errors.Is(err, database.ErrRecordNotFound) {
+		logger.Error("GetByName error", logger.Err(err), logger.String("name", user.Name), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
 	err = h.iDao.Create(ctx, user)
 	if err != nil {
-		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		logger.Error("Create error", logger.Err(err), logger.String("name", form.Name), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -144,11 +167,19 @@ func (h *userHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	if form.Password != "" {
+		user.Password, err = auth.HashPassword(form.Password)
+		if err != nil {
+			logger.Error("HashPassword error", logger.Err(err), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrUpdateByIDUser)
+			return
+		}
+	}
 
 	ctx := middleware.WrapCtx(c)
 	err = h.iDao.UpdateByID(ctx, user)
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		logger.Error("UpdateByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditLog k8s资源变更操作的审计记录, 请求体中的敏感字段已脱敏
+type AuditLog struct {
+	ID         uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	RequestID  string     `gorm:"column:request_id;type:varchar(64);index" json:"requestID"`
+	UserID     uint64     `gorm:"column:user_id;type:bigint(20)" json:"userID"`
+	Username   string     `gorm:"column:username;type:varchar(64);index" json:"username"`
+	Cluster    string     `gorm:"column:cluster;type:varchar(64);index" json:"cluster"`
+	Namespace  string     `gorm:"column:namespace;type:varchar(255)" json:"namespace"`
+	Kind       string     `gorm:"column:kind;type:varchar(64)" json:"kind"`
+	Name       string     `gorm:"column:name;type:varchar(255)" json:"name"`
+	Verb       string     `gorm:"column:verb;type:varchar(32)" json:"verb"`
+	Method     string     `gorm:"column:method;type:varchar(16)" json:"method"`
+	Path       string     `gorm:"column:path;type:varchar(1024)" json:"path"`
+	ClientIP   string     `gorm:"column:client_ip;type:varchar(64)" json:"clientIP"`
+	Body       string     `gorm:"column:body;type:mediumtext" json:"body"`
+	Result     string     `gorm:"column:result;type:varchar(16)" json:"result"` // success | failure
+	StatusCode int        `gorm:"column:status_code;type:int" json:"statusCode"`
+	Code       int        `gorm:"column:code;type:int" json:"code"` // 响应中的业务错误码
+	Message    string     `gorm:"column:message;type:varchar(1024)" json:"message"`
+	Latency    int64      `gorm:"column:latency;type:bigint(20)" json:"latency"` // 毫秒
+	CreatedAt  *time.Time `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null;index" json:"createdAt"`
+}
+
+// TableName table name
+func (m *AuditLog) TableName() string {
+	return "audit_log"
+}
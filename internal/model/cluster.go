@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// Cluster 已注册的kubernetes集群, 凭证字段均为加密后的密文
+type Cluster struct {
+	ID          uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	Name        string     `gorm:"column:name;type:varchar(64);not null;uniqueIndex" json:"name"`
+	Description string     `gorm:"column:description;type:varchar(255)" json:"description"`
+	AuthType    string     `gorm:"column:auth_type;type:varchar(20);not null" json:"authType"` // kubeconfig | token
+	KubeConfig  string     `gorm:"column:kube_config;type:text" json:"kubeConfig"`
+	Server      string     `gorm:"column:server;type:varchar(255)" json:"server"`
+	Token       string     `gorm:"column:token;type:text" json:"token"`
+	CAData      string     `gorm:"column:ca_data;type:text" json:"caData"`
+	Insecure    bool       `gorm:"column:insecure;type:tinyint(1);not null;default:0" json:"insecure"`
+	CreatedAt   *time.Time `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+}
+
+// TableName table name
+func (m *Cluster) TableName() string {
+	return "cluster"
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+)
+
+// ExecSession 终端会话审计记录
+type ExecSession struct {
+	ID         uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	Username   string     `gorm:"column:username;type:varchar(64);not null;index" json:"username"`
+	ClientIP   string     `gorm:"column:client_ip;type:varchar(64)" json:"clientIP"`
+	Cluster    string     `gorm:"column:cluster;type:varchar(64);not null" json:"cluster"`
+	Namespace  string     `gorm:"column:namespace;type:varchar(255);not null" json:"namespace"`
+	Pod        string     `gorm:"column:pod;type:varchar(255);not null" json:"pod"`
+	Container  string     `gorm:"column:container;type:varchar(255)" json:"container"`
+	Command    string     `gorm:"column:command;type:varchar(255)" json:"command"`
+	Error      string     `gorm:"column:error;type:varchar(1024)" json:"error"`        // 会话异常结束的原因
+	Transcript string     `gorm:"column:transcript;type:mediumtext" json:"transcript"` // 用户输入记录, 需要开启 system.execTranscript
+	StartedAt  *time.Time `gorm:"column:started_at;type:datetime;not null" json:"startedAt"`
+	EndedAt    *time.Time `gorm:"column:ended_at;type:datetime" json:"endedAt"`
+}
+
+// TableName table name
+func (m *ExecSession) TableName() string {
+	return "exec_session"
+}
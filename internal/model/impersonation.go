@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// Impersonation 用户访问集群时模拟的k8s身份, 请求由集群自身的RBAC鉴权, apiserver审计日志中记录该身份
+// Cluster 为 * 时对所有集群生效, 指定集群的记录优先
+type Impersonation struct {
+	ID        uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	UserID    uint64     `gorm:"column:user_id;type:bigint(20);not null;uniqueIndex:idx_user_cluster" json:"userID"`
+	Cluster   string     `gorm:"column:cluster;type:varchar(64);not null;uniqueIndex:idx_user_cluster" json:"cluster"`
+	Username  string     `gorm:"column:username;type:varchar(255);not null" json:"username"` // serviceaccount 为 system:serviceaccount:<namespace>:<name>
+	Groups    []string   `gorm:"column:kube_groups;type:text;serializer:json" json:"groups"`
+	CreatedAt *time.Time `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+}
+
+// TableName table name
+func (m *Impersonation) TableName() string {
+	return "impersonation"
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+)
+
+// PermissionRule 授权规则, 各字段为 * 时匹配全部
+// 集群级资源(node/pv/sc)没有命名空间, 只有 namespaces 包含 * 的规则才能匹配
+type PermissionRule struct {
+	Cluster    string   `json:"cluster"`
+	Namespaces []string `json:"namespaces"`
+	Kinds      []string `json:"kinds"` // pod | deployment | secret ...
+	Verbs      []string `json:"verbs"` // view | edit | delete | exec | secret-read
+}
+
+// AppRole 应用角色, 与集群中的 Role 无关, 所有操作仍使用服务自身的kubeconfig
+type AppRole struct {
+	ID          uint64           `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	Name        string           `gorm:"column:name;type:varchar(64);not null;uniqueIndex" json:"name"`
+	Description string           `gorm:"column:description;type:varchar(255)" json:"description"`
+	Rules       []PermissionRule `gorm:"column:rules;type:text;serializer:json" json:"rules"`
+	CreatedAt   *time.Time       `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt   *time.Time       `gorm:"column:updated_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+}
+
+// TableName table name
+func (m *AppRole) TableName() string {
+	return "app_role"
+}
+
+// Team 团队, 绑定到团队的角色对所有成员生效
+type Team struct {
+	ID          uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	Name        string     `gorm:"column:name;type:varchar(64);not null;uniqueIndex" json:"name"`
+	Description string     `gorm:"column:description;type:varchar(255)" json:"description"`
+	CreatedAt   *time.Time `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+}
+
+// TableName table name
+func (m *Team) TableName() string {
+	return "team"
+}
+
+// TeamMember 团队成员
+type TeamMember struct {
+	ID     uint64 `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	TeamID uint64 `gorm:"column:team_id;type:bigint(20);not null;uniqueIndex:idx_team_user" json:"teamID"`
+	UserID uint64 `gorm:"column:user_id;type:bigint(20);not null;uniqueIndex:idx_team_user;index" json:"userID"`
+}
+
+// TableName table name
+func (m *TeamMember) TableName() string {
+	return "team_member"
+}
+
+// AppRoleBinding 把角色授予用户或团队, UserID 和 TeamID 只有一个不为0
+type AppRoleBinding struct {
+	ID        uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	RoleID    uint64     `gorm:"column:role_id;type:bigint(20);not null;index" json:"roleID"`
+	UserID    uint64     `gorm:"column:user_id;type:bigint(20);not null;default:0;index" json:"userID"`
+	TeamID    uint64     `gorm:"column:team_id;type:bigint(20);not null;default:0;index" json:"teamID"`
+	CreatedAt *time.Time `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+}
+
+// TableName table name
+func (m *AppRoleBinding) TableName() string {
+	return "app_role_binding"
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// Registry 额外的镜像仓库凭证, 用于生成托管的 imagePullSecret, 密码为加密后的密文
+type Registry struct {
+	ID        uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	Name      string     `gorm:"column:name;type:varchar(64);not null;uniqueIndex" json:"name"`
+	Server    string     `gorm:"column:server;type:varchar(255);not null" json:"server"` // 镜像地址中的仓库域名, 如 registry.example.com:5000
+	Username  string     `gorm:"column:username;type:varchar(255);not null" json:"username"`
+	Password  string     `gorm:"column:password;type:text" json:"password"`
+	CreatedAt *time.Time `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+}
+
+// TableName table name
+func (m *Registry) TableName() string {
+	return "registry"
+}
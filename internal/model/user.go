@@ -4,11 +4,30 @@ import (
 	"time"
 )
 
+// 用户状态
+const (
+	UserStatusActive   = 1
+	UserStatusDisabled = 2
+)
+
+// 用户类型, 管理员不受应用权限限制, 并且可以管理用户、集群和权限
+const (
+	UserTypeAdmin  = 1
+	UserTypeMember = 2
+)
+
 type User struct {
-	ID        uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
-	Name      string     `gorm:"column:name;type:varchar(100);not null" json:"name"`
-	CreatedAt *time.Time `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
-	UpdatedAt *time.Time `gorm:"column:updated_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+	ID                 uint64     `gorm:"column:id;type:bigint(20);primary_key;AUTO_INCREMENT" json:"id"`
+	Name               string     `gorm:"column:name;type:varchar(100);not null;uniqueIndex" json:"name"`
+	Password           string     `gorm:"column:password;type:varchar(100);not null;default:''" json:"password"`                    // bcrypt 哈希
+	Status             int        `gorm:"column:status;type:tinyint(4);not null;default:1" json:"status"`                           // 1:启用 2:禁用
+	Type               int        `gorm:"column:type;type:tinyint(4);not null;default:2" json:"type"`                               // 1:管理员 2:普通用户
+	MustChangePassword bool       `gorm:"column:must_change_password;type:tinyint(1);not null;default:0" json:"mustChangePassword"` // 为true时修改密码前只能修改密码和注销
+	PasswordChangedAt  *time.Time `gorm:"column:password_changed_at;type:datetime" json:"passwordChangedAt"`                        // 在此之前签发的令牌无效
+	LastLoginAt        *time.Time `gorm:"column:last_login_at;type:datetime" json:"lastLoginAt"`
+	LastLoginIP        string     `gorm:"column:last_login_ip;type:varchar(64)" json:"lastLoginIP"`
+	CreatedAt          *time.Time `gorm:"column:created_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt          *time.Time `gorm:"column:updated_at;type:datetime;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
 }
 
 // TableName table name
@@ -18,8 +37,11 @@ func (m *User) TableName() string {
 
 // UserColumnNames Whitelist for custom query fields to prevent sql injection attacks
 var UserColumnNames = map[string]bool{
-	"id":         true,
-	"name":       true,
-	"created_at": true,
-	"updated_at": true,
+	"id":            true,
+	"name":          true,
+	"status":        true,
+	"type":          true,
+	"last_login_at": true,
+	"created_at":    true,
+	"updated_at":    true,
 }
@@ -0,0 +1,156 @@
+// Package audit 记录k8s资源的变更操作, 用于追溯谁在什么时候修改或删除了资源
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+)
+
+const (
+	// 保存的请求体最大字节数, 超过时截断
+	maxBodySize = 64 << 10
+	// 只解析响应开头的 code/msg
+	maxResponseSize = 4 << 10
+	maxMessageLen   = 1024
+)
+
+// 不修改资源的POST接口
+var readOnlyKinds = map[string]bool{
+	"permission": true, // permission/check
+}
+
+// 任意资源的YAML/JSON接口
+const applyKind = "apply"
+
+// 测试时替换
+var saveLog = func(ctx context.Context, record *model.AuditLog) error {
+	return dao.NewAuditLogDao(database.GetDB()).Create(ctx, record)
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	if remain := maxResponseSize - w.body.Len(); remain > 0 {
+		w.body.Write(data[:min(remain, len(data))])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Middleware 记录变更(edit/delete)和exec操作, 必须在 auth.Middleware 之后、permission.Middleware 之前使用, 被拒绝的操作同样记录
+// basePath 为路由组的路径, 如 /api/v1/k8s/:cluster
+func Middleware(basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kind, verb := permission.ResolveRoute(c, basePath)
+		if c.FullPath() == "" || readOnlyKinds[kind] || verb == permission.VerbView || verb == permission.VerbSecretRead {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		body, err := peekBody(c)
+		if err != nil {
+			logger.Warn("read request body error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		}
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		record := &model.AuditLog{
+			RequestID:  middleware.GCtxRequestID(c),
+			Cluster:    c.Param("cluster"),
+			Namespace:  c.Param("namespace"),
+			Kind:       kind,
+			Name:       c.Param("name"),
+			Verb:       verb,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			ClientIP:   c.ClientIP(),
+			Body:       auditBody(body, kind),
+			StatusCode: c.Writer.Status(),
+			Latency:    time.Since(start).Milliseconds(),
+		}
+		if user, ok := auth.CurrentUser(c); ok {
+			record.UserID = user.ID
+			record.Username = user.Name
+		}
+		if record.Name == "" || record.Namespace == "" {
+			name, namespace := bodyTarget(body)
+			if record.Name == "" {
+				record.Name = name
+			}
+			if record.Namespace == "" {
+				record.Namespace = namespace
+			}
+		}
+		// apply 没有指定命名空间的对象使用查询参数中的命名空间
+		if record.Namespace == "" {
+			record.Namespace = c.Query("namespace")
+		}
+		setResult(record, recorder.body.Bytes())
+
+		// 请求可能已被取消, 审计记录仍需保存
+		err = saveLog(context.WithoutCancel(c.Request.Context()), record)
+		if err != nil {
+			logger.Error("save audit log error", logger.Err(err), logger.Any("record", record), middleware.GCtxRequestIDField(c))
+		}
+	}
+}
+
+// peekBody 读取请求体并还原, handler 中还需要绑定参数
+func peekBody(c *gin.Context) ([]byte, error) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBodySize))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	return body, err
+}
+
+func auditBody(body []byte, kind string) string {
+	if len(body) >= maxBodySize {
+		return "[body too large, omitted]"
+	}
+	// apply 的请求体可以是YAML, 且包含任意kind的对象
+	if kind == applyKind {
+		return redactManifests(body)
+	}
+	return redactBody(body, kind)
+}
+
+// setResult 根据http状态码和响应中的业务错误码判断操作是否成功
+func setResult(record *model.AuditLog, response []byte) {
+	resp := struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	_ = json.Unmarshal(response, &resp)
+	record.Code = resp.Code
+	record.Message = resp.Msg
+	if len(record.Message) > maxMessageLen {
+		record.Message = strings.ToValidUTF8(record.Message[:maxMessageLen], "")
+	}
+	if record.StatusCode < http.StatusBadRequest && resp.Code == 0 {
+		record.Result = model.AuditResultSuccess
+	} else {
+		record.Result = model.AuditResultFailure
+	}
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+func TestMiddleware(t *testing.T) {
+	var records []*model.AuditLog
+	saveLog = func(ctx context.Context, record *model.AuditLog) error {
+		records = append(records, record)
+		return nil
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	g := r.Group("/api/v1/k8s/:cluster")
+	g.Use(Middleware(g.BasePath()))
+	g.POST("/secret", func(c *gin.Context) {
+		// handler 仍然可以读取完整的请求体
+		data, _ := io.ReadAll(c.Request.Body)
+		assert.Contains(t, string(data), "s3cr3t")
+		response.Error(c, ecode.InvalidParams)
+	})
+	g.DELETE("/deployment/:namespace/:name", func(c *gin.Context) { response.Success(c) })
+	g.GET("/pod/:namespace", func(c *gin.Context) { response.Success(c) })
+	g.POST("/apply", func(c *gin.Context) { response.Success(c) })
+
+	do := func(method, path, body string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	do(http.MethodPost, "/api/v1/k8s/prod/secret", `{"name":"db","namespace":"app","data":[{"key":"password","value":"s3cr3t"}]}`)
+	do(http.MethodDelete, "/api/v1/k8s/prod/deployment/app/nginx", "")
+	do(http.MethodGet, "/api/v1/k8s/prod/pod/app", "")
+	do(http.MethodPost, "/api/v1/k8s/prod/apply?namespace=app", "kind: Secret\nmetadata:\n  name: db\ndata:\n  password: czNjcjN0\n")
+
+	require.Len(t, records, 3)
+	assert.Equal(t, "secret", records[0].Kind)
+	assert.Equal(t, "edit", records[0].Verb)
+	assert.Equal(t, "db", records[0].Name)
+	assert.Equal(t, "app", records[0].Namespace)
+	assert.Equal(t, model.AuditResultFailure, records[0].Result)
+	assert.Equal(t, ecode.InvalidParams.Code(), records[0].Code)
+	assert.NotContains(t, records[0].Body, "s3cr3t")
+
+	assert.Equal(t, "prod", records[1].Cluster)
+	assert.Equal(t, "deployment", records[1].Kind)
+	assert.Equal(t, "delete", records[1].Verb)
+	assert.Equal(t, "nginx", records[1].Name)
+	assert.Equal(t, model.AuditResultSuccess, records[1].Result)
+
+	assert.Equal(t, "apply", records[2].Kind)
+	assert.Equal(t, "app", records[2].Namespace)
+	assert.Contains(t, records[2].Body, "name: db")
+	assert.NotContains(t, records[2].Body, "czNjcjN0")
+}
+
+func TestRedactBody(t *testing.T) {
+	body := redactBody([]byte(`{"base":{"name":"web","env":[{"name":"DB_PASSWORD","value":"x"}]},"registry":{"password":"p","token":"t"}}`), "deployment")
+	assert.JSONEq(t, `{"base":{"name":"web","env":[{"name":"DB_PASSWORD","value":"x"}]},"registry":{"password":"******","token":"******"}}`, body)
+	assert.Equal(t, "[non-json body omitted]", redactBody([]byte("plain"), "pod"))
+
+	name, namespace := bodyTarget([]byte(`{"base":{"name":"web","namespace":"app"},"volume":{"name":"data"}}`))
+	assert.Equal(t, "web", name)
+	assert.Equal(t, "app", namespace)
+}
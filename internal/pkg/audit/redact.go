@@ -0,0 +1,173 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+const redactedValue = "******"
+
+// 字段名包含这些词时脱敏, 不区分大小写
+var sensitiveKeys = []string{"password", "passwd", "token", "privatekey", "credential", "apikey"}
+
+// redactBody 脱敏请求体, secret 的 data/stringData 整体脱敏, 非json请求体不保存内容
+func redactBody(body []byte, kind string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "[non-json body omitted]"
+	}
+	data, err := json.Marshal(redactValue(value, kind))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// redactManifests 脱敏 apply 的多文档YAML/JSON请求体, 每个对象转为YAML后用 --- 连接
+// kind 为 Secret 的对象(包括List中的)脱敏 data/stringData
+func redactManifests(body []byte) string {
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(body), 4096)
+	docs := make([]string, 0)
+	for {
+		var object map[string]any
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "[invalid manifest omitted]"
+		}
+		if len(object) == 0 {
+			continue
+		}
+		data, err := yaml.Marshal(redactValue(redactSecrets(object), ""))
+		if err != nil {
+			return "[invalid manifest omitted]"
+		}
+		docs = append(docs, string(data))
+	}
+	return strings.Join(docs, "---\n")
+}
+
+// redactSecrets 按对象的kind脱敏, List 递归处理 items
+func redactSecrets(object map[string]any) map[string]any {
+	kind, _ := object["kind"].(string)
+	if kind == "Secret" {
+		for key := range object {
+			if lower := strings.ToLower(key); lower == "data" || lower == "stringdata" {
+				object[key] = redactedValue
+			}
+		}
+	}
+	if items, ok := object["items"].([]any); ok && strings.HasSuffix(kind, "List") {
+		for _, item := range items {
+			if itemObject, ok := item.(map[string]any); ok {
+				redactSecrets(itemObject)
+			}
+		}
+	}
+	return object
+}
+
+func redactValue(value any, kind string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSensitiveKey(key, kind) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(item, kind)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, kind)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string, kind string) bool {
+	key = strings.ToLower(key)
+	if kind == "secret" && (key == "data" || key == "stringdata") {
+		return true
+	}
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyTarget 请求体中操作的资源名称和命名空间, 取顶层或第二层(如 base、metadata)中的 name/namespace
+func bodyTarget(body []byte) (string, string) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", ""
+	}
+	name, namespace := targetFields(fields)
+	// 优先取 base/metadata 中的字段, 其余按字段名排序, 结果稳定
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, k int) bool {
+		if targetPriority(keys[i]) != targetPriority(keys[k]) {
+			return targetPriority(keys[i]) < targetPriority(keys[k])
+		}
+		return keys[i] < keys[k]
+	})
+	for _, key := range keys {
+		if name != "" && namespace != "" {
+			break
+		}
+		nested := map[string]json.RawMessage{}
+		if err := json.Unmarshal(fields[key], &nested); err != nil {
+			continue
+		}
+		nestedName, nestedNamespace := targetFields(nested)
+		if name == "" && nestedName != "" {
+			name = nestedName
+		}
+		if namespace == "" && nestedNamespace != "" {
+			namespace = nestedNamespace
+		}
+	}
+	return name, namespace
+}
+
+func targetPriority(key string) int {
+	if strings.EqualFold(key, "base") || strings.EqualFold(key, "metadata") {
+		return 0
+	}
+	return 1
+}
+
+func targetFields(fields map[string]json.RawMessage) (string, string) {
+	var name, namespace string
+	for key, raw := range fields {
+		var value string
+		switch {
+		case strings.EqualFold(key, "name"):
+			if json.Unmarshal(raw, &value) == nil {
+				name = value
+			}
+		case strings.EqualFold(key, "namespace"):
+			if json.Unmarshal(raw, &value) == nil {
+				namespace = value
+			}
+		}
+	}
+	return name, namespace
+}
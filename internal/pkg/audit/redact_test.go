@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactManifests(t *testing.T) {
+	// JSON Secret
+	body := redactManifests([]byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"db"},"data":{"password":"czNjcjN0"},"stringData":{"user":"root"}}`))
+	assert.NotContains(t, body, "czNjcjN0")
+	assert.NotContains(t, body, "root")
+	assert.Contains(t, body, "name: db")
+
+	// YAML: Secret 和普通对象, 普通对象只脱敏敏感字段
+	body = redactManifests([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+stringData:
+  tls.key: PRIVATE
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  mode: prod
+  apiKey: k3y
+`))
+	assert.NotContains(t, body, "PRIVATE")
+	assert.NotContains(t, body, "k3y")
+	assert.Contains(t, body, "mode: prod")
+	assert.Contains(t, body, "---\n")
+
+	// List 中的 Secret
+	body = redactManifests([]byte(`{"apiVersion":"v1","kind":"List","items":[{"apiVersion":"v1","kind":"Secret","metadata":{"name":"db"},"data":{"password":"czNjcjN0"}},{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app"},"data":{"mode":"prod"}}]}`))
+	assert.NotContains(t, body, "czNjcjN0")
+	assert.Contains(t, body, "mode: prod")
+
+	assert.Equal(t, "[invalid manifest omitted]", redactManifests([]byte("kind: [")))
+}
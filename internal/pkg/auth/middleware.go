@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/jwt"
+
+	"github.com/xiaofan193/k8sadmin/internal/cache"
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+const (
+	// CtxUsernameKey 认证通过后写入的当前用户名
+	CtxUsernameKey = "username"
+	// CtxUserIDKey 认证通过后写入的当前用户id
+	CtxUserIDKey = "uid"
+	ctxUserKey   = "user"
+
+	// websocket 和 EventSource 不能设置请求头, 通过该查询参数传递 access token
+	queryTokenKey = "token"
+)
+
+var (
+	errTokenRevoked = errors.New("token has been revoked")
+	errUserDisabled = errors.New("user is disabled")
+	errMustChange   = errors.New("password must be changed")
+)
+
+// Middleware jwt 认证中间件, 只接受未注销的 access token, 且用户必须处于启用状态, 使用一次性密码的用户需要先修改密码
+func Middleware() gin.HandlerFunc {
+	return authMiddleware(false)
+}
+
+// PasswordMiddleware 同 Middleware, 但允许需要修改密码的用户通过, 只用于修改密码和注销
+func PasswordMiddleware() gin.HandlerFunc {
+	return authMiddleware(true)
+}
+
+func authMiddleware(allowMustChange bool) gin.HandlerFunc {
+	// 未配置签名密钥时所有请求都认证失败
+	key, _ := signKey()
+	userDao := dao.NewUserDao(database.GetDB(), cache.NewUserCache(database.GetCacheType()))
+	tokenCache := cache.NewTokenCache(database.GetCacheType())
+
+	verify := func(claims *jwt.Claims, c *gin.Context) error {
+		if !IsAccessToken(claims) {
+			return ErrInvalidTokenType
+		}
+		ctx := c.Request.Context()
+		revoked, err := tokenCache.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return errTokenRevoked
+		}
+		uid, err := UserID(claims)
+		if err != nil {
+			return err
+		}
+		user, err := userDao.GetByID(ctx, uid)
+		if err != nil {
+			return err
+		}
+		if user.Status == model.UserStatusDisabled {
+			return errUserDisabled
+		}
+		if IssuedBefore(claims, user.PasswordChangedAt) {
+			return errTokenRevoked
+		}
+		if user.MustChangePassword && !allowMustChange {
+			return errMustChange
+		}
+		c.Set(ctxUserKey, user)
+		c.Set(CtxUserIDKey, user.ID)
+		c.Set(CtxUsernameKey, user.Name)
+		return nil
+	}
+	authFn := middleware.Auth(middleware.WithSignKey(key), middleware.WithExtraVerify(verify))
+
+	return func(c *gin.Context) {
+		if c.GetHeader(middleware.HeaderAuthorizationKey) == "" {
+			if token := c.Query(queryTokenKey); token != "" {
+				c.Request.Header.Set(middleware.HeaderAuthorizationKey, "Bearer "+token)
+			}
+		}
+		authFn(c)
+	}
+}
+
+// CurrentUser 认证中间件加载的当前用户
+func CurrentUser(c *gin.Context) (*model.User, bool) {
+	user, ok := c.Get(ctxUserKey)
+	if !ok {
+		return nil, false
+	}
+	u, ok := user.(*model.User)
+	return u, ok
+}
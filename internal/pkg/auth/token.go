@@ -0,0 +1,185 @@
+// Package auth 登录令牌的签发、刷新和校验
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-dev-frame/sponge/pkg/jwt"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+const (
+	defaultAccessExpire  = 30 * time.Minute
+	defaultRefreshExpire = 7 * 24 * time.Hour
+
+	// 两种令牌的结构相同, 通过 aud 区分, 防止 refresh token 被当作 access token 使用
+	audienceAccess  = "access"
+	audienceRefresh = "refresh"
+
+	fieldUsername = "username"
+
+	// HS256 签名密钥不能短于哈希长度
+	minSignKeyLen = 32
+	// 旧版本配置文件中的示例密钥
+	exampleSignKey = "k8sadmin-jwt-change-me"
+)
+
+var (
+	// ErrInvalidTokenType 令牌类型不匹配
+	ErrInvalidTokenType = errors.New("invalid token type")
+	errNoSignKey        = errors.New("system.jwt.signKey 未配置")
+	errExampleSignKey   = errors.New("system.jwt.signKey 不能使用示例密钥")
+	errShortSignKey     = fmt.Errorf("system.jwt.signKey 长度不能少于 %d 字节", minSignKeyLen)
+)
+
+// Tokens 登录和刷新返回的令牌, ExpiresAt 为 access token 的过期时间
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    int64
+}
+
+// ValidateSignKey 启动时校验签名密钥, 拒绝空密钥、示例密钥和太短的密钥
+func ValidateSignKey(key string) error {
+	switch {
+	case key == "":
+		return errNoSignKey
+	case key == exampleSignKey:
+		return errExampleSignKey
+	case len(key) < minSignKeyLen:
+		return errShortSignKey
+	}
+	return nil
+}
+
+func signKey() ([]byte, error) {
+	if global.CONF == nil || global.CONF.System.Jwt.SignKey == "" {
+		return nil, errNoSignKey
+	}
+	return []byte(global.CONF.System.Jwt.SignKey), nil
+}
+
+// AccessExpire access token 有效期
+func AccessExpire() time.Duration {
+	if global.CONF != nil && global.CONF.System.Jwt.AccessExpire > 0 {
+		return time.Duration(global.CONF.System.Jwt.AccessExpire) * time.Minute
+	}
+	return defaultAccessExpire
+}
+
+// RefreshExpire refresh token 有效期, 也是注销记录的保留时间
+func RefreshExpire() time.Duration {
+	if global.CONF != nil && global.CONF.System.Jwt.RefreshExpire > 0 {
+		return time.Duration(global.CONF.System.Jwt.RefreshExpire) * time.Hour
+	}
+	return defaultRefreshExpire
+}
+
+// GenerateTokens 为用户签发一对令牌, 两个令牌的 jwt id 相同
+func GenerateTokens(user *model.User) (*Tokens, error) {
+	key, err := signKey()
+	if err != nil {
+		return nil, err
+	}
+	accessExpire := AccessExpire()
+	tokens, err := jwt.GenerateTwoTokens(
+		strconv.FormatUint(user.ID, 10),
+		jwt.WithGenerateTwoTokensSignKey(key),
+		jwt.WithGenerateTwoTokensFields(map[string]interface{}{fieldUsername: user.Name}),
+		jwt.WithGenerateTwoTokensAccessTokenClaims(jwt.WithExpires(accessExpire), jwt.WithAudience(audienceAccess)),
+		jwt.WithGenerateTwoTokensRefreshTokenClaims(jwt.WithExpires(RefreshExpire()), jwt.WithAudience(audienceRefresh)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    time.Now().Add(accessExpire).Unix(),
+	}, nil
+}
+
+// ParseRefreshToken 校验 refresh token 的签名、有效期和类型
+func ParseRefreshToken(refreshToken string) (*jwt.Claims, error) {
+	return parseToken(refreshToken, audienceRefresh)
+}
+
+// RefreshTokens 用已校验的 refresh token 签发新的 access token
+// 新令牌沿用原 jwt id, 注销时两者同时失效, refresh token 保持不变, 过期后需要重新登录
+func RefreshTokens(claims *jwt.Claims, refreshToken string) (*Tokens, error) {
+	key, err := signKey()
+	if err != nil {
+		return nil, err
+	}
+	accessExpire := AccessExpire()
+	_, accessToken, err := jwt.GenerateToken(
+		claims.UID,
+		jwt.WithGenerateTokenSignKey(key),
+		jwt.WithGenerateTokenFields(claims.Fields),
+		jwt.WithGenerateTokenClaims(jwt.WithExpires(accessExpire), jwt.WithAudience(audienceAccess), jwt.WithJwtID(claims.ID)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(accessExpire).Unix(),
+	}, nil
+}
+
+// IsAccessToken 认证中间件只接受 access token
+func IsAccessToken(claims *jwt.Claims) bool {
+	return hasAudience(claims, audienceAccess)
+}
+
+// IssuedBefore 令牌是否在 t 之前签发, 用于修改密码后使之前签发的令牌失效, t 为nil时总是false
+func IssuedBefore(claims *jwt.Claims, t *time.Time) bool {
+	if t == nil {
+		return false
+	}
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return claims.IssuedAt.Time.Before(*t)
+}
+
+// Username 令牌中的用户名
+func Username(claims *jwt.Claims) string {
+	name, _ := claims.GetString(fieldUsername)
+	return name
+}
+
+// UserID 令牌中的用户id
+func UserID(claims *jwt.Claims) (uint64, error) {
+	return strconv.ParseUint(claims.UID, 10, 64)
+}
+
+func parseToken(token, audience string) (*jwt.Claims, error) {
+	key, err := signKey()
+	if err != nil {
+		return nil, err
+	}
+	claims, err := jwt.ValidateToken(token, jwt.WithValidateTokenSignKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if !hasAudience(claims, audience) {
+		return nil, ErrInvalidTokenType
+	}
+	return claims, nil
+}
+
+func hasAudience(claims *jwt.Claims, audience string) bool {
+	for _, item := range claims.Audience {
+		if item == audience {
+			return true
+		}
+	}
+	return false
+}
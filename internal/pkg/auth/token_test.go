@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-dev-frame/sponge/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+func setSignKey(t *testing.T, key string) {
+	old := global.CONF
+	global.CONF = &global.Server{System: global.System{Jwt: global.Jwt{SignKey: key}}}
+	t.Cleanup(func() { global.CONF = old })
+}
+
+func TestGenerateTokens(t *testing.T) {
+	setSignKey(t, "test-sign-key")
+	tokens, err := GenerateTokens(&model.User{ID: 7, Name: "alice"})
+	require.NoError(t, err)
+
+	access, err := jwt.ValidateToken(tokens.AccessToken, jwt.WithValidateTokenSignKey([]byte("test-sign-key")))
+	require.NoError(t, err)
+	assert.True(t, IsAccessToken(access))
+	assert.Equal(t, "alice", Username(access))
+	uid, err := UserID(access)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), uid)
+
+	refresh, err := ParseRefreshToken(tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.False(t, IsAccessToken(refresh))
+	assert.Equal(t, access.ID, refresh.ID)
+
+	// access token 不能用于刷新
+	_, err = ParseRefreshToken(tokens.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidTokenType)
+}
+
+func TestRefreshTokens(t *testing.T) {
+	setSignKey(t, "test-sign-key")
+	tokens, err := GenerateTokens(&model.User{ID: 7, Name: "alice"})
+	require.NoError(t, err)
+	claims, err := ParseRefreshToken(tokens.RefreshToken)
+	require.NoError(t, err)
+
+	refreshed, err := RefreshTokens(claims, tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, tokens.RefreshToken, refreshed.RefreshToken)
+
+	access, err := jwt.ValidateToken(refreshed.AccessToken, jwt.WithValidateTokenSignKey([]byte("test-sign-key")))
+	require.NoError(t, err)
+	assert.True(t, IsAccessToken(access))
+	assert.Equal(t, claims.ID, access.ID)
+	assert.Equal(t, "alice", Username(access))
+
+	// 签名密钥变化后令牌失效
+	setSignKey(t, "other-sign-key")
+	_, err = ParseRefreshToken(tokens.RefreshToken)
+	assert.Error(t, err)
+}
+
+func TestSignKeyRequired(t *testing.T) {
+	setSignKey(t, "")
+	_, err := GenerateTokens(&model.User{ID: 1, Name: "alice"})
+	assert.Error(t, err)
+}
+
+func TestIssuedBefore(t *testing.T) {
+	setSignKey(t, "test-sign-key")
+	tokens, err := GenerateTokens(&model.User{ID: 7, Name: "alice"})
+	require.NoError(t, err)
+	claims, err := ParseRefreshToken(tokens.RefreshToken)
+	require.NoError(t, err)
+
+	assert.False(t, IssuedBefore(claims, nil))
+	// 同一秒内修改密码前签发的令牌仍然有效
+	issuedAt := claims.IssuedAt.Time
+	assert.False(t, IssuedBefore(claims, &issuedAt))
+	later := issuedAt.Add(time.Second)
+	assert.True(t, IssuedBefore(claims, &later))
+}
+
+func TestValidateSignKey(t *testing.T) {
+	assert.ErrorIs(t, ValidateSignKey(""), errNoSignKey)
+	assert.ErrorIs(t, ValidateSignKey(exampleSignKey), errExampleSignKey)
+	assert.ErrorIs(t, ValidateSignKey("test-sign-key"), errShortSignKey)
+	assert.NoError(t, ValidateSignKey("0123456789abcdef0123456789abcdef"))
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("secret123")
+	require.NoError(t, err)
+	assert.True(t, CheckPassword(hash, "secret123"))
+	assert.False(t, CheckPassword(hash, "secret124"))
+	assert.False(t, CheckPassword("", ""))
+
+	password, err := RandomPassword()
+	require.NoError(t, err)
+	assert.Len(t, password, 24)
+}
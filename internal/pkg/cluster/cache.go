@@ -0,0 +1,258 @@
+package cluster
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	networkinglisters "k8s.io/client-go/listers/networking/v1"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Cache 基于SharedInformerFactory的集群资源缓存
+// 列表接口优先从lister读取, 对应informer未同步完成时返回ok=false, 调用方回退到直接请求apiserver
+// lister返回的对象与缓存共享, 只读, 不能修改
+type Cache struct {
+	factory informers.SharedInformerFactory
+	stopCh  chan struct{}
+	synced  []cache.InformerSynced
+}
+
+func newCache(clientset kubernetes.Interface) *Cache {
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithTransform(stripManagedFields))
+	c := &Cache{
+		factory: factory,
+		stopCh:  make(chan struct{}),
+	}
+
+	// 启动前注册所有需要缓存的资源, Start 只会启动已注册的informer
+	for _, informer := range []cache.SharedIndexInformer{
+		factory.Core().V1().Pods().Informer(),
+		factory.Core().V1().Namespaces().Informer(),
+		factory.Core().V1().Nodes().Informer(),
+		factory.Core().V1().ConfigMaps().Informer(),
+		factory.Core().V1().Secrets().Informer(),
+		factory.Core().V1().Services().Informer(),
+		factory.Core().V1().ServiceAccounts().Informer(),
+		factory.Core().V1().PersistentVolumes().Informer(),
+		factory.Core().V1().PersistentVolumeClaims().Informer(),
+		factory.Storage().V1().StorageClasses().Informer(),
+		factory.Apps().V1().Deployments().Informer(),
+		factory.Apps().V1().DaemonSets().Informer(),
+		factory.Apps().V1().StatefulSets().Informer(),
+		factory.Batch().V1().Jobs().Informer(),
+		factory.Batch().V1().CronJobs().Informer(),
+		factory.Networking().V1().Ingresses().Informer(),
+		factory.Rbac().V1().Roles().Informer(),
+		factory.Rbac().V1().ClusterRoles().Informer(),
+		factory.Rbac().V1().RoleBindings().Informer(),
+		factory.Rbac().V1().ClusterRoleBindings().Informer(),
+	} {
+		c.synced = append(c.synced, informer.HasSynced)
+	}
+	factory.Start(c.stopCh)
+	return c
+}
+
+// Stop 停止所有informer
+func (c *Cache) Stop() {
+	if c == nil {
+		return
+	}
+	close(c.stopCh)
+	c.factory.Shutdown()
+}
+
+// Synced 所有informer是否都已完成首次同步
+func (c *Cache) Synced() bool {
+	if c == nil {
+		return false
+	}
+	for _, synced := range c.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitForSync 阻塞直到所有informer完成首次同步, 缓存被停止时返回false
+func (c *Cache) WaitForSync() bool {
+	if c == nil {
+		return false
+	}
+	return cache.WaitForCacheSync(c.stopCh, c.synced...)
+}
+
+func (c *Cache) Pods() (corelisters.PodLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().Pods()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) Namespaces() (corelisters.NamespaceLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().Namespaces()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) Nodes() (corelisters.NodeLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().Nodes()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) ConfigMaps() (corelisters.ConfigMapLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().ConfigMaps()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) Secrets() (corelisters.SecretLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().Secrets()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) Services() (corelisters.ServiceLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().Services()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) ServiceAccounts() (corelisters.ServiceAccountLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().ServiceAccounts()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) PersistentVolumes() (corelisters.PersistentVolumeLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().PersistentVolumes()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) PersistentVolumeClaims() (corelisters.PersistentVolumeClaimLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Core().V1().PersistentVolumeClaims()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) StorageClasses() (storagelisters.StorageClassLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Storage().V1().StorageClasses()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) Deployments() (appslisters.DeploymentLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Apps().V1().Deployments()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) DaemonSets() (appslisters.DaemonSetLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Apps().V1().DaemonSets()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) StatefulSets() (appslisters.StatefulSetLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Apps().V1().StatefulSets()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) Jobs() (batchlisters.JobLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Batch().V1().Jobs()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) CronJobs() (batchlisters.CronJobLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Batch().V1().CronJobs()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) Ingresses() (networkinglisters.IngressLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Networking().V1().Ingresses()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) Roles() (rbaclisters.RoleLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Rbac().V1().Roles()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) ClusterRoles() (rbaclisters.ClusterRoleLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Rbac().V1().ClusterRoles()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) RoleBindings() (rbaclisters.RoleBindingLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Rbac().V1().RoleBindings()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+func (c *Cache) ClusterRoleBindings() (rbaclisters.ClusterRoleBindingLister, bool) {
+	if c == nil {
+		return nil, false
+	}
+	informer := c.factory.Rbac().V1().ClusterRoleBindings()
+	return informer.Lister(), informer.Informer().HasSynced()
+}
+
+// stripManagedFields managedFields 对列表接口无用且占用大量内存, 入缓存前去掉
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
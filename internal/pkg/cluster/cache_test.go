@@ -0,0 +1,41 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCache(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "nginx",
+			Namespace:     "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+	})
+	c := newCache(clientset)
+	defer c.Stop()
+
+	assert.True(t, c.WaitForSync())
+	assert.True(t, c.Synced())
+
+	lister, ok := c.Pods()
+	assert.True(t, ok)
+	pods, err := lister.Pods("default").List(labels.Everything())
+	assert.NoError(t, err)
+	assert.Len(t, pods, 1)
+	assert.Empty(t, pods[0].ManagedFields)
+}
+
+func TestCacheNil(t *testing.T) {
+	var c *Cache
+	_, ok := c.Pods()
+	assert.False(t, ok)
+	assert.False(t, c.Synced())
+	c.Stop()
+}
@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+// 集群凭证(kubeconfig/token/ca)入库前使用AES-GCM加密, 密钥取自 system.encryptKey
+
+const (
+	minEncryptKeyLen = 32
+	// 旧版本配置文件中的示例密钥
+	exampleEncryptKey = "k8sadmin-change-me"
+)
+
+var (
+	errNoEncryptKey      = errors.New("system.encryptKey 未配置")
+	errExampleEncryptKey = errors.New("system.encryptKey 不能使用示例密钥")
+	errShortEncryptKey   = fmt.Errorf("system.encryptKey 长度不能少于 %d 字节", minEncryptKeyLen)
+)
+
+// ValidateEncryptKey 启动时校验加密密钥, 拒绝空密钥、示例密钥和太短的密钥
+func ValidateEncryptKey(key string) error {
+	switch {
+	case key == "":
+		return errNoEncryptKey
+	case key == exampleEncryptKey:
+		return errExampleEncryptKey
+	case len(key) < minEncryptKeyLen:
+		return errShortEncryptKey
+	}
+	return nil
+}
+
+func getCipher() (cipher.AEAD, error) {
+	if global.CONF == nil || global.CONF.System.EncryptKey == "" {
+		return nil, errNoEncryptKey
+	}
+	key := sha256.Sum256([]byte(global.CONF.System.EncryptKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt 加密明文, 返回base64编码的密文
+func Encrypt(plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+	gcm, err := getCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密由Encrypt生成的密文
+func Decrypt(encrypted string) (string, error) {
+	if encrypted == "" {
+		return "", nil
+	}
+	gcm, err := getCipher()
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("密文格式错误")
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,31 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+func TestValidateEncryptKey(t *testing.T) {
+	assert.ErrorIs(t, ValidateEncryptKey(""), errNoEncryptKey)
+	assert.ErrorIs(t, ValidateEncryptKey(exampleEncryptKey), errExampleEncryptKey)
+	assert.ErrorIs(t, ValidateEncryptKey("short-key"), errShortEncryptKey)
+	assert.NoError(t, ValidateEncryptKey("0123456789abcdef0123456789abcdef"))
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	old := global.CONF
+	defer func() { global.CONF = old }()
+	global.CONF = &global.Server{}
+	global.CONF.System.EncryptKey = "0123456789abcdef0123456789abcdef"
+
+	ciphertext, err := Encrypt("kubeconfig")
+	require.NoError(t, err)
+	assert.NotEqual(t, "kubeconfig", ciphertext)
+	plaintext, err := Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "kubeconfig", plaintext)
+}
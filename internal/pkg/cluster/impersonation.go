@@ -0,0 +1,79 @@
+package cluster
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const ctxImpersonatedKey = "k8s_impersonated"
+
+// Identity 模拟的k8s身份, 服务自身的凭证需要有对应 users/groups/serviceaccounts 的 impersonate 权限
+type Identity struct {
+	Username string
+	Groups   []string
+}
+
+func (i Identity) key() string {
+	return i.Username + "\n" + strings.Join(i.Groups, "\n")
+}
+
+type impersonatedClient struct {
+	config    *rest.Config
+	clientset *kubernetes.Clientset
+}
+
+// Impersonate 以指定身份访问集群的客户端, 同一身份复用客户端
+func (c *Cluster) Impersonate(identity Identity) (*kubernetes.Clientset, *rest.Config, error) {
+	key := identity.key()
+	c.impersonatedMu.Lock()
+	defer c.impersonatedMu.Unlock()
+	if client, ok := c.impersonated[key]; ok {
+		return client.clientset, client.config, nil
+	}
+
+	config := rest.CopyConfig(c.Config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: identity.Username,
+		Groups:   identity.Groups,
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.impersonated == nil {
+		c.impersonated = make(map[string]*impersonatedClient)
+	}
+	c.impersonated[key] = &impersonatedClient{config: config, clientset: clientset}
+	return clientset, config, nil
+}
+
+// UseIdentity 当前请求改为以指定身份访问选中的集群, 必须在 Select 之后使用
+// 模拟身份时不使用informer缓存, 所有读取都经过apiserver鉴权
+func UseIdentity(c *gin.Context, identity Identity) error {
+	clientset, config, err := FromContext(c).Impersonate(identity)
+	if err != nil {
+		return err
+	}
+	c.Set(ctxImpersonatedKey, &impersonatedClient{config: config, clientset: clientset})
+	return nil
+}
+
+// ClientConfig 当前请求访问集群使用的rest config, 用于exec等需要直接使用config的场景
+func ClientConfig(c *gin.Context) *rest.Config {
+	if client, ok := impersonatedFromContext(c); ok {
+		return client.config
+	}
+	return FromContext(c).Config
+}
+
+func impersonatedFromContext(c *gin.Context) (*impersonatedClient, bool) {
+	value, ok := c.Get(ctxImpersonatedKey)
+	if !ok {
+		return nil, false
+	}
+	client, ok := value.(*impersonatedClient)
+	return client, ok
+}
@@ -0,0 +1,68 @@
+package cluster
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+)
+
+const ctxClusterKey = "k8s_cluster"
+
+// Select 根据路由参数 :cluster 选择目标集群, 未注册的集群直接返回404
+func Select() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("cluster")
+		cls, err := Get(name)
+		if err != nil {
+			logger.Warn("select cluster error", logger.Err(err), logger.String("cluster", name), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound, err.Error())
+			c.Abort()
+			return
+		}
+		c.Set(ctxClusterKey, cls)
+		c.Next()
+	}
+}
+
+// CheckReady 就绪检查, 默认集群的informer缓存完成首次同步前返回503
+func CheckReady(c *gin.Context) {
+	defaultCluster, err := Get(DefaultName)
+	if err != nil || !defaultCluster.Cache.Synced() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "cache not synced"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+// FromContext 获取当前请求选中的集群
+func FromContext(c *gin.Context) *Cluster {
+	return c.MustGet(ctxClusterKey).(*Cluster)
+}
+
+// KubeConfigSet 获取当前请求选中集群的clientset, 模拟身份时返回该身份的clientset
+func KubeConfigSet(c *gin.Context) *kubernetes.Clientset {
+	if client, ok := impersonatedFromContext(c); ok {
+		return client.clientset
+	}
+	return FromContext(c).KubeConfigSet
+}
+
+// InformerCache 获取当前请求选中集群的informer缓存, 模拟身份时返回nil, 列表直接请求apiserver
+func InformerCache(c *gin.Context) *Cache {
+	if _, ok := impersonatedFromContext(c); ok {
+		return nil
+	}
+	return FromContext(c).Cache
+}
+
+// DynamicClient 当前请求访问集群的dynamic客户端, 用于没有类型定义的任意资源, 模拟身份时使用该身份
+func DynamicClient(c *gin.Context) (dynamic.Interface, error) {
+	return dynamic.NewForConfig(ClientConfig(c))
+}
@@ -0,0 +1,149 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+const (
+	// DefaultName 启动时从本地 .kube/config 加载的集群名称
+	DefaultName = "default"
+
+	AuthTypeKubeConfig = "kubeconfig"
+	AuthTypeToken      = "token"
+)
+
+// ErrClusterNotFound 集群未注册
+var ErrClusterNotFound = errors.New("cluster not found")
+
+// Cluster 已连接的集群
+type Cluster struct {
+	Name          string
+	Config        *rest.Config
+	KubeConfigSet *kubernetes.Clientset
+	Cache         *Cache
+	// 按需从discovery加载, 用于把任意资源类型映射为API路径
+	Mapper *restmapper.DeferredDiscoveryRESTMapper
+
+	// 按模拟身份缓存的客户端
+	impersonatedMu sync.Mutex
+	impersonated   map[string]*impersonatedClient
+}
+
+var (
+	clusters   = make(map[string]*Cluster)
+	clustersMu sync.RWMutex
+)
+
+// Register 根据rest config 创建clientset并启动informer缓存, 同名集群会被覆盖
+func Register(name string, config *rest.Config) (*Cluster, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cluster{
+		Name:          name,
+		Config:        config,
+		KubeConfigSet: clientset,
+		Cache:         newCache(clientset),
+		Mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery())),
+	}
+	clustersMu.Lock()
+	old := clusters[name]
+	clusters[name] = c
+	clustersMu.Unlock()
+	if old != nil {
+		old.Cache.Stop()
+	}
+	return c, nil
+}
+
+// Remove 注销集群并停止informer缓存
+func Remove(name string) {
+	clustersMu.Lock()
+	old := clusters[name]
+	delete(clusters, name)
+	clustersMu.Unlock()
+	if old != nil {
+		old.Cache.Stop()
+	}
+}
+
+// Get 获取已注册的集群
+func Get(name string) (*Cluster, error) {
+	clustersMu.RLock()
+	defer clustersMu.RUnlock()
+	c, ok := clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrClusterNotFound, name)
+	}
+	return c, nil
+}
+
+// List 获取所有已注册的集群, 按名称排序
+func List() []*Cluster {
+	clustersMu.RLock()
+	list := make([]*Cluster, 0, len(clusters))
+	for _, c := range clusters {
+		list = append(list, c)
+	}
+	clustersMu.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// RestConfig 解密集群凭证并生成rest config
+func RestConfig(m *model.Cluster) (*rest.Config, error) {
+	switch m.AuthType {
+	case AuthTypeKubeConfig:
+		kubeConfig, err := Decrypt(m.KubeConfig)
+		if err != nil {
+			return nil, err
+		}
+		return clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfig))
+	case AuthTypeToken:
+		token, err := Decrypt(m.Token)
+		if err != nil {
+			return nil, err
+		}
+		caData, err := Decrypt(m.CAData)
+		if err != nil {
+			return nil, err
+		}
+		return &rest.Config{
+			Host:        m.Server,
+			BearerToken: token,
+			TLSClientConfig: rest.TLSClientConfig{
+				CAData:   []byte(caData),
+				Insecure: m.Insecure,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的认证类型: %s", m.AuthType)
+	}
+}
+
+// Ping 测试集群连通性, 返回apiserver版本信息
+func Ping(config *rest.Config) (*version.Info, error) {
+	testConfig := rest.CopyConfig(config)
+	testConfig.Timeout = 5 * time.Second
+	clientset, err := kubernetes.NewForConfig(testConfig)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.Discovery().ServerVersion()
+}
@@ -0,0 +1,130 @@
+// Package impersonation 把 k8sadmin 用户映射为k8s身份, 以该身份访问集群, 由集群自身的RBAC鉴权
+package impersonation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+const (
+	// AllClusters 对所有集群生效
+	AllClusters = "*"
+
+	// 用户映射的缓存时间, 映射变化时会主动清空
+	recordsCacheTTL = time.Minute
+)
+
+type recordsEntry struct {
+	records  []*model.Impersonation
+	expireAt time.Time
+}
+
+var (
+	recordsMu    sync.Mutex
+	recordsCache = map[uint64]recordsEntry{}
+
+	// 测试时替换
+	loadRecords = func(ctx context.Context, userID uint64) ([]*model.Impersonation, error) {
+		return dao.NewImpersonationDao(database.GetDB()).GetByUserID(ctx, userID)
+	}
+)
+
+// ServiceAccountUsername serviceaccount 的用户名, 模拟时apiserver会自动补充 serviceaccount 的用户组
+func ServiceAccountUsername(namespace, name string) string {
+	return "system:serviceaccount:" + namespace + ":" + name
+}
+
+// Lookup 用户在集群中模拟的身份, 指定集群的映射优先于 *, 没有映射时返回nil
+func Lookup(ctx context.Context, userID uint64, clusterName string) (*cluster.Identity, error) {
+	recordsMu.Lock()
+	entry, ok := recordsCache[userID]
+	recordsMu.Unlock()
+	if !ok || !time.Now().Before(entry.expireAt) {
+		records, err := loadRecords(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		entry = recordsEntry{records: records, expireAt: time.Now().Add(recordsCacheTTL)}
+		recordsMu.Lock()
+		recordsCache[userID] = entry
+		recordsMu.Unlock()
+	}
+	return selectIdentity(entry.records, clusterName), nil
+}
+
+// Invalidate 映射变化后清空缓存, 立即生效
+func Invalidate() {
+	recordsMu.Lock()
+	recordsCache = map[uint64]recordsEntry{}
+	recordsMu.Unlock()
+}
+
+func selectIdentity(records []*model.Impersonation, clusterName string) *cluster.Identity {
+	var matched *model.Impersonation
+	for _, record := range records {
+		if record.Cluster == clusterName {
+			matched = record
+			break
+		}
+		if record.Cluster == AllClusters && matched == nil {
+			matched = record
+		}
+	}
+	if matched == nil {
+		return nil
+	}
+	return &cluster.Identity{Username: matched.Username, Groups: matched.Groups}
+}
+
+// Middleware 当前用户映射了k8s身份时, 之后的handler以该身份访问集群, 必须在 cluster.Select 之后使用
+// system.impersonation.required 为true时, 没有映射的普通用户不能访问集群, 管理员仍使用服务自身的身份
+func Middleware() gin.HandlerFunc {
+	required := global.CONF != nil && global.CONF.System.Impersonation.Required
+	return func(c *gin.Context) {
+		user, ok := auth.CurrentUser(c)
+		if !ok {
+			response.Error(c, ecode.Unauthorized)
+			c.Abort()
+			return
+		}
+		clusterName := cluster.FromContext(c).Name
+		identity, err := Lookup(c.Request.Context(), user.ID, clusterName)
+		if err != nil {
+			logger.Error("lookup impersonation error", logger.Err(err), logger.Any("uid", user.ID), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrLoadImpersonation)
+			c.Abort()
+			return
+		}
+		if identity == nil {
+			if required && user.Type != model.UserTypeAdmin {
+				response.Error(c, ecode.ErrNoImpersonation)
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if err = cluster.UseIdentity(c, *identity); err != nil {
+			logger.Error("impersonate error", logger.Err(err), logger.String("cluster", clusterName), logger.String("username", identity.Username), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package impersonation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+)
+
+func TestLookup(t *testing.T) {
+	loads := 0
+	loadRecords = func(ctx context.Context, userID uint64) ([]*model.Impersonation, error) {
+		loads++
+		return []*model.Impersonation{
+			{UserID: userID, Cluster: AllClusters, Username: "alice", Groups: []string{"dev"}},
+			{UserID: userID, Cluster: "prod", Username: ServiceAccountUsername("ops", "viewer")},
+		}, nil
+	}
+	defer Invalidate()
+
+	identity, err := Lookup(context.Background(), 1, "prod")
+	require.NoError(t, err)
+	// 指定集群的映射优先于 *
+	assert.Equal(t, &cluster.Identity{Username: "system:serviceaccount:ops:viewer"}, identity)
+
+	identity, err = Lookup(context.Background(), 1, "test")
+	require.NoError(t, err)
+	assert.Equal(t, &cluster.Identity{Username: "alice", Groups: []string{"dev"}}, identity)
+	assert.Equal(t, 1, loads)
+
+	Invalidate()
+	_, err = Lookup(context.Background(), 1, "test")
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+
+	assert.Nil(t, selectIdentity(nil, "prod"))
+}
@@ -0,0 +1,252 @@
+package listquery
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+const (
+	SortByName      = "name"
+	SortByNamespace = "namespace"
+	SortByAge       = "age"
+
+	orderAsc  = "asc"
+	orderDesc = "desc"
+)
+
+// ErrUnsupportedField fieldSelector 使用了该资源不支持的字段, 不支持的字段取值为空, 会静默地匹配或过滤掉所有对象
+var ErrUnsupportedField = errors.New("fieldSelector 不支持的字段")
+
+// Object 可以被分页的资源, k8s内置资源嵌入的 ObjectMeta 已经实现
+type Object[T any] interface {
+	*T
+	metav1.ObjectMetaAccessor
+}
+
+// Validate 校验查询参数格式, 供handler在调用controller前使用
+func Validate(query *types.ListQuery) error {
+	if _, _, err := parseSort(query.Sort); err != nil {
+		return err
+	}
+	if _, err := labels.Parse(query.LabelSelector); err != nil {
+		return fmt.Errorf("labelSelector 格式错误: %w", err)
+	}
+	if _, err := fields.ParseSelector(query.FieldSelector); err != nil {
+		return fmt.Errorf("fieldSelector 格式错误: %w", err)
+	}
+	if _, err := decodeContinue(query.Continue); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Apply 依次执行 keyword/labelSelector/fieldSelector 过滤、排序、分页
+// items 需要已经按 namespace/name 排序, 与apiserver List 的默认顺序一致
+func Apply[T any, PT Object[T]](items []T, query *types.ListQuery) ([]T, *types.ListMeta, error) {
+	if query == nil {
+		query = &types.ListQuery{}
+	}
+	labelSelector, err := labels.Parse(query.LabelSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldSelector, err := fields.ParseSelector(query.FieldSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = validateFields(fieldSelector, fieldSet(PT(new(T)))); err != nil {
+		return nil, nil, err
+	}
+
+	filtered := make([]T, 0, len(items))
+	for i := range items {
+		obj := PT(&items[i])
+		meta := obj.GetObjectMeta()
+		if query.Keyword != "" && !strings.Contains(meta.GetName(), query.Keyword) {
+			continue
+		}
+		if !labelSelector.Matches(labels.Set(meta.GetLabels())) {
+			continue
+		}
+		if !fieldSelector.Empty() && !fieldSelector.Matches(fieldSet(obj)) {
+			continue
+		}
+		filtered = append(filtered, items[i])
+	}
+
+	field, order, err := parseSort(query.Sort)
+	if err != nil {
+		return nil, nil, err
+	}
+	if field != "" {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			less := compare(PT(&filtered[i]).GetObjectMeta(), PT(&filtered[j]).GetObjectMeta(), field)
+			if order == orderDesc {
+				return less > 0
+			}
+			return less < 0
+		})
+	}
+
+	listMeta := &types.ListMeta{Total: len(filtered)}
+	if query.Limit <= 0 {
+		return filtered, listMeta, nil
+	}
+
+	offset, err := decodeContinue(query.Continue)
+	if err != nil {
+		return nil, nil, err
+	}
+	if query.Continue == "" && query.Page > 1 {
+		offset = (query.Page - 1) * query.Limit
+	}
+	if offset >= len(filtered) {
+		return []T{}, listMeta, nil
+	}
+	end := offset + query.Limit
+	if end < len(filtered) {
+		listMeta.Continue = encodeContinue(end)
+	} else {
+		end = len(filtered)
+	}
+	return filtered[offset:end], listMeta, nil
+}
+
+func parseSort(s string) (string, string, error) {
+	if s == "" {
+		return "", "", nil
+	}
+	field, order, _ := strings.Cut(s, ":")
+	if order == "" {
+		order = orderAsc
+	}
+	switch field {
+	case SortByName, SortByNamespace, SortByAge:
+	default:
+		return "", "", fmt.Errorf("sort 不支持的字段: %s", field)
+	}
+	if order != orderAsc && order != orderDesc {
+		return "", "", fmt.Errorf("sort 不支持的排序方式: %s", order)
+	}
+	return field, order, nil
+}
+
+func compare(a, b metav1.Object, field string) int {
+	switch field {
+	case SortByNamespace:
+		if c := strings.Compare(a.GetNamespace(), b.GetNamespace()); c != 0 {
+			return c
+		}
+	case SortByAge:
+		// age 越大创建时间越早
+		ta, tb := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+		if !ta.Equal(&tb) {
+			if tb.Before(&ta) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a.GetName(), b.GetName())
+}
+
+// validateFields 检查字段选择器只使用了 supported 中的字段
+func validateFields(selector fields.Selector, supported fields.Set) error {
+	for _, requirement := range selector.Requirements() {
+		if _, ok := supported[requirement.Field]; ok {
+			continue
+		}
+		keys := make([]string, 0, len(supported))
+		for key := range supported {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("%w %s, 可用的字段: %s", ErrUnsupportedField, requirement.Field, strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// fieldSet 字段选择器可用的字段, 所有资源都支持 metadata.name 和 metadata.namespace
+// 零值对象返回的字段即为该资源支持的所有字段
+func fieldSet(obj metav1.ObjectMetaAccessor) fields.Set {
+	meta := obj.GetObjectMeta()
+	set := fields.Set{
+		"metadata.name":      meta.GetName(),
+		"metadata.namespace": meta.GetNamespace(),
+	}
+	switch item := obj.(type) {
+	case *corev1.Pod:
+		set["spec.nodeName"] = item.Spec.NodeName
+		set["spec.restartPolicy"] = string(item.Spec.RestartPolicy)
+		set["spec.serviceAccountName"] = item.Spec.ServiceAccountName
+		set["status.phase"] = string(item.Status.Phase)
+		set["status.podIP"] = item.Status.PodIP
+	case *corev1.Node:
+		set["spec.unschedulable"] = strconv.FormatBool(item.Spec.Unschedulable)
+	case *corev1.Secret:
+		set["type"] = string(item.Type)
+	case *corev1.Service:
+		set["spec.type"] = string(item.Spec.Type)
+		set["spec.clusterIP"] = item.Spec.ClusterIP
+	case *corev1.Namespace:
+		set["status.phase"] = string(item.Status.Phase)
+	case *corev1.PersistentVolume:
+		set["status.phase"] = string(item.Status.Phase)
+		set["spec.storageClassName"] = item.Spec.StorageClassName
+	case *corev1.PersistentVolumeClaim:
+		set["status.phase"] = string(item.Status.Phase)
+		set["spec.volumeName"] = item.Spec.VolumeName
+	case *appsv1.Deployment:
+		set["spec.replicas"] = replicas(item.Spec.Replicas)
+	case *appsv1.StatefulSet:
+		set["spec.replicas"] = replicas(item.Spec.Replicas)
+		set["spec.serviceName"] = item.Spec.ServiceName
+	case *batchv1.Job:
+		set["status.successful"] = strconv.Itoa(int(item.Status.Succeeded))
+	case *batchv1.CronJob:
+		set["spec.schedule"] = item.Spec.Schedule
+		set["spec.suspend"] = strconv.FormatBool(item.Spec.Suspend != nil && *item.Spec.Suspend)
+	}
+	return set
+}
+
+// replicas 未设置时与apiserver的默认值一致为1
+func replicas(value *int32) string {
+	if value == nil {
+		return "1"
+	}
+	return strconv.Itoa(int(*value))
+}
+
+// continue token 只是对偏移量的编码, 数据变化时翻页结果可能有重复或遗漏
+func encodeContinue(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeContinue(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("continue token 无效")
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("continue token 无效")
+	}
+	return offset, nil
+}
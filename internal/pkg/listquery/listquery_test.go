@@ -0,0 +1,108 @@
+package listquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+func newPod(name string, age time.Duration, labels map[string]string, phase corev1.PodPhase) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func testPods() []corev1.Pod {
+	return []corev1.Pod{
+		newPod("api-0", 3*time.Hour, map[string]string{"app": "api"}, corev1.PodRunning),
+		newPod("api-1", time.Hour, map[string]string{"app": "api"}, corev1.PodPending),
+		newPod("nginx-0", 2*time.Hour, map[string]string{"app": "nginx"}, corev1.PodRunning),
+	}
+}
+
+func names(pods []corev1.Pod) []string {
+	list := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		list = append(list, pod.Name)
+	}
+	return list
+}
+
+func TestApply(t *testing.T) {
+	pods, meta, err := Apply(testPods(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, meta.Total)
+	assert.Equal(t, []string{"api-0", "api-1", "nginx-0"}, names(pods))
+
+	pods, meta, err = Apply(testPods(), &types.ListQuery{Keyword: "api", FieldSelector: "status.phase=Running"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, meta.Total)
+	assert.Equal(t, []string{"api-0"}, names(pods))
+
+	pods, _, err = Apply(testPods(), &types.ListQuery{LabelSelector: "app!=api"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx-0"}, names(pods))
+
+	pods, _, err = Apply(testPods(), &types.ListQuery{Sort: "age:desc"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api-0", "nginx-0", "api-1"}, names(pods))
+
+	pods, _, err = Apply(testPods(), &types.ListQuery{Sort: "name:desc"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx-0", "api-1", "api-0"}, names(pods))
+}
+
+func TestApplyPagination(t *testing.T) {
+	pods, meta, err := Apply(testPods(), &types.ListQuery{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, meta.Total)
+	assert.Equal(t, []string{"api-0", "api-1"}, names(pods))
+	assert.NotEmpty(t, meta.Continue)
+
+	pods, meta, err = Apply(testPods(), &types.ListQuery{Limit: 2, Continue: meta.Continue})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx-0"}, names(pods))
+	assert.Empty(t, meta.Continue)
+
+	pods, _, err = Apply(testPods(), &types.ListQuery{Limit: 2, Page: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx-0"}, names(pods))
+
+	pods, _, err = Apply(testPods(), &types.ListQuery{Limit: 2, Page: 5})
+	assert.NoError(t, err)
+	assert.Empty(t, pods)
+}
+
+func TestApplyUnsupportedField(t *testing.T) {
+	pods, _, err := Apply(testPods(), &types.ListQuery{FieldSelector: "status.phase=Running,spec.nodeName!=n1"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pods)
+
+	// 不支持的字段取值为空, 不校验时 != 会匹配所有对象
+	_, _, err = Apply(testPods(), &types.ListQuery{FieldSelector: "spec.hostNetwork!=true"})
+	assert.ErrorIs(t, err, ErrUnsupportedField)
+	assert.Contains(t, err.Error(), "spec.hostNetwork")
+
+	// 空列表同样校验
+	_, _, err = Apply([]corev1.Secret{}, &types.ListQuery{FieldSelector: "status.phase=Running"})
+	assert.ErrorIs(t, err, ErrUnsupportedField)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(&types.ListQuery{Sort: "age", LabelSelector: "app in (a,b)", FieldSelector: "spec.nodeName=n1"}))
+	assert.Error(t, Validate(&types.ListQuery{Sort: "status:asc"}))
+	assert.Error(t, Validate(&types.ListQuery{Sort: "name:up"}))
+	assert.Error(t, Validate(&types.ListQuery{LabelSelector: "app in ("}))
+	assert.Error(t, Validate(&types.ListQuery{Continue: "!!"}))
+}
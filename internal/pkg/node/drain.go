@@ -0,0 +1,314 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+const (
+	// 静态pod在apiserver中对应的镜像pod带有该注解, 不能通过apiserver驱逐
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+	drainDefaultTimeout = 600 * time.Second
+	drainPollInterval   = 2 * time.Second
+)
+
+// ErrDrainRunning 同一个节点同时只能有一个驱逐任务
+var ErrDrainRunning = errors.New("node is already being drained")
+
+// 每个集群每个节点只保留最近一次驱逐任务
+var drainTasks = struct {
+	sync.Mutex
+	tasks map[string]*DrainTask
+}{tasks: make(map[string]*DrainTask)}
+
+// DrainOptions 驱逐参数
+type DrainOptions struct {
+	DeleteEmptyDirData bool
+	Force              bool
+	GracePeriodSeconds *int64
+	Timeout            time.Duration
+}
+
+// DrainTask 后台执行的驱逐任务
+type DrainTask struct {
+	mu     sync.Mutex
+	task   types.NodeDrainTask
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Snapshot 当前进度的拷贝
+func (t *DrainTask) Snapshot() *types.NodeDrainTask {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	task := t.task
+	task.Pods = make([]*types.NodeDrainPod, 0, len(t.task.Pods))
+	for _, item := range t.task.Pods {
+		podItem := *item
+		task.Pods = append(task.Pods, &podItem)
+	}
+	return &task
+}
+
+// Cancel 取消驱逐, 已经提交驱逐的pod不会恢复, 节点保持不可调度
+func (t *DrainTask) Cancel() {
+	t.cancel()
+}
+
+// Done 任务结束时关闭
+func (t *DrainTask) Done() <-chan struct{} {
+	return t.done
+}
+
+func (t *DrainTask) update(fn func(task *types.NodeDrainTask)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn(&t.task)
+}
+
+func (t *DrainTask) updatePod(item *types.NodeDrainPod, status, reason string) {
+	t.update(func(task *types.NodeDrainTask) {
+		item.Status = status
+		item.Reason = reason
+		switch status {
+		case types.DrainPodEvicted:
+			task.Evicted++
+		case types.DrainPodFailed:
+			task.Failed++
+		}
+	})
+}
+
+// StartDrain 标记节点不可调度后在后台驱逐节点上的pod, clusterName 用于区分不同集群的同名节点
+func StartDrain(client kubernetes.Interface, clusterName, nodeName string, opts DrainOptions) (*DrainTask, error) {
+	key := clusterName + "/" + nodeName
+	drainTasks.Lock()
+	defer drainTasks.Unlock()
+	if task, ok := drainTasks.tasks[key]; ok && task.Snapshot().Status == types.DrainStatusRunning {
+		return nil, ErrDrainRunning
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = drainDefaultTimeout
+	}
+	// 任务不随请求结束而取消
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	task := &DrainTask{
+		task: types.NodeDrainTask{
+			ID:        uuid.NewString(),
+			Node:      nodeName,
+			Status:    types.DrainStatusRunning,
+			Pods:      make([]*types.NodeDrainPod, 0),
+			StartTime: time.Now().Unix(),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	drainTasks.tasks[key] = task
+
+	d := &drainer{client: client, opts: opts, pollInterval: drainPollInterval}
+	go func() {
+		defer close(task.done)
+		defer cancel()
+		d.run(ctx, task)
+	}()
+	return task, nil
+}
+
+// GetDrainTask 节点最近一次驱逐任务
+func GetDrainTask(clusterName, nodeName string) (*DrainTask, bool) {
+	drainTasks.Lock()
+	defer drainTasks.Unlock()
+	task, ok := drainTasks.tasks[clusterName+"/"+nodeName]
+	return task, ok
+}
+
+type drainer struct {
+	client       kubernetes.Interface
+	opts         DrainOptions
+	pollInterval time.Duration
+}
+
+func (d *drainer) run(ctx context.Context, task *DrainTask) {
+	status, message := d.drain(ctx, task)
+	task.update(func(t *types.NodeDrainTask) {
+		t.Status = status
+		t.Message = message
+		t.EndTime = time.Now().Unix()
+	})
+}
+
+func (d *drainer) drain(ctx context.Context, task *DrainTask) (string, string) {
+	nodeName := task.Snapshot().Node
+	if err := Cordon(ctx, d.client, nodeName, true); err != nil {
+		return types.DrainStatusFailed, "设置节点不可调度失败: " + err.Error()
+	}
+
+	podList, err := d.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return types.DrainStatusFailed, "获取节点上的pod失败: " + err.Error()
+	}
+
+	// 与 kubectl drain 相同, 存在不能驱逐的pod时不驱逐任何pod
+	pods := make([]corev1.Pod, 0, len(podList.Items))
+	podItems := make([]*types.NodeDrainPod, 0, len(podList.Items))
+	items := make([]*types.NodeDrainPod, 0, len(podList.Items))
+	blocked := make([]string, 0)
+	for _, k8sPod := range podList.Items {
+		item := &types.NodeDrainPod{Namespace: k8sPod.Namespace, Name: k8sPod.Name, Status: types.DrainPodPending}
+		skip, block := drainFilter(&k8sPod, d.opts)
+		switch {
+		case skip != "":
+			item.Status, item.Reason = types.DrainPodSkipped, skip
+		case block != "":
+			item.Status, item.Reason = types.DrainPodFailed, block
+			blocked = append(blocked, k8sPod.Namespace+"/"+k8sPod.Name)
+		default:
+			pods = append(pods, k8sPod)
+			podItems = append(podItems, item)
+		}
+		items = append(items, item)
+	}
+	task.update(func(t *types.NodeDrainTask) {
+		t.Pods = items
+		t.Total = len(pods)
+		t.Failed = len(blocked)
+	})
+	if len(blocked) > 0 {
+		return types.DrainStatusFailed, "存在不能驱逐的pod: " + strings.Join(blocked, ", ")
+	}
+
+	var wg sync.WaitGroup
+	for i := range pods {
+		wg.Add(1)
+		go func(k8sPod *corev1.Pod, item *types.NodeDrainPod) {
+			defer wg.Done()
+			d.evictPod(ctx, task, k8sPod, item)
+		}(&pods[i], podItems[i])
+	}
+	wg.Wait()
+
+	snapshot := task.Snapshot()
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return types.DrainStatusCanceled, "驱逐已取消"
+	case snapshot.Failed > 0:
+		return types.DrainStatusFailed, fmt.Sprintf("%d 个pod驱逐失败", snapshot.Failed)
+	}
+	return types.DrainStatusSucceeded, fmt.Sprintf("已驱逐 %d 个pod", snapshot.Evicted)
+}
+
+// evictPod 提交驱逐并等待pod删除, 被 PodDisruptionBudget 阻止时重试直到超时
+func (d *drainer) evictPod(ctx context.Context, task *DrainTask, k8sPod *corev1.Pod, item *types.NodeDrainPod) {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: k8sPod.Name, Namespace: k8sPod.Namespace},
+	}
+	if d.opts.GracePeriodSeconds != nil {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: d.opts.GracePeriodSeconds}
+	}
+
+	for {
+		task.update(func(*types.NodeDrainTask) { item.Attempts++ })
+		err := d.client.PolicyV1().Evictions(k8sPod.Namespace).Evict(ctx, eviction)
+		if err == nil {
+			task.updatePod(item, types.DrainPodEvicting, "")
+			break
+		}
+		if apierrors.IsNotFound(err) {
+			task.updatePod(item, types.DrainPodEvicted, "")
+			return
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			task.updatePod(item, types.DrainPodFailed, err.Error())
+			return
+		}
+		task.updatePod(item, types.DrainPodPending, "被 PodDisruptionBudget 阻止, 等待重试: "+err.Error())
+		if !sleep(ctx, d.pollInterval) {
+			task.updatePod(item, types.DrainPodFailed, drainStopReason(ctx, item.Reason))
+			return
+		}
+	}
+
+	for {
+		current, err := d.client.CoreV1().Pods(k8sPod.Namespace).Get(ctx, k8sPod.Name, metav1.GetOptions{})
+		// 同名pod重建(如statefulset)时uid不同
+		if apierrors.IsNotFound(err) || (err == nil && current.UID != k8sPod.UID) {
+			task.updatePod(item, types.DrainPodEvicted, "")
+			return
+		}
+		if !sleep(ctx, d.pollInterval) {
+			task.updatePod(item, types.DrainPodFailed, drainStopReason(ctx, "等待pod删除"))
+			return
+		}
+	}
+}
+
+// drainFilter 返回跳过的原因, 或者阻止驱逐的原因
+func drainFilter(k8sPod *corev1.Pod, opts DrainOptions) (skip string, block string) {
+	if _, ok := k8sPod.Annotations[mirrorPodAnnotation]; ok {
+		return "静态pod", ""
+	}
+	// 已结束的pod直接驱逐
+	if k8sPod.Status.Phase == corev1.PodSucceeded || k8sPod.Status.Phase == corev1.PodFailed {
+		return "", ""
+	}
+	owner := metav1.GetControllerOf(k8sPod)
+	if owner != nil && owner.Kind == "DaemonSet" {
+		return "daemonset 管理的pod", ""
+	}
+	if owner == nil && !opts.Force {
+		return "", "没有控制器管理, 驱逐后不会重建, 需要设置 force"
+	}
+	if !opts.DeleteEmptyDirData {
+		for _, volume := range k8sPod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				return "", "使用了 emptyDir, 驱逐后数据会丢失, 需要设置 deleteEmptyDirData"
+			}
+		}
+	}
+	return "", ""
+}
+
+func drainStopReason(ctx context.Context, reason string) string {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return "已取消: " + reason
+	}
+	return "超时: " + reason
+}
+
+// sleep 等待d, ctx 结束时返回false
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Cordon 设置节点是否可调度
+func Cordon(ctx context.Context, client kubernetes.Interface, nodeName string, unschedulable bool) error {
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	_, err := client.CoreV1().Nodes().Patch(ctx, nodeName, k8stypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
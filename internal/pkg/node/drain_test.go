@@ -0,0 +1,113 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+func drainTestPod(name, ownerKind string) *corev1.Pod {
+	k8sPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: k8stypes.UID("uid-" + name)},
+		Spec:       corev1.PodSpec{NodeName: "node1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if ownerKind != "" {
+		controller := true
+		k8sPod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: name + "-owner", Controller: &controller}}
+	}
+	return k8sPod
+}
+
+func runTestDrain(client *fake.Clientset, opts DrainOptions) *types.NodeDrainTask {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	task := &DrainTask{task: types.NodeDrainTask{Node: "node1", Status: types.DrainStatusRunning}, cancel: cancel, done: make(chan struct{})}
+	d := &drainer{client: client, opts: opts, pollInterval: 10 * time.Millisecond}
+	d.run(ctx, task)
+	return task.Snapshot()
+}
+
+func TestDrain(t *testing.T) {
+	mirror := drainTestPod("kube-apiserver-node1", "Node")
+	mirror.Annotations = map[string]string{mirrorPodAnnotation: "hash"}
+	client := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		drainTestPod("web-1", "ReplicaSet"),
+		drainTestPod("web-2", "ReplicaSet"),
+		drainTestPod("fluentd-abcde", "DaemonSet"),
+		mirror,
+	)
+	// web-1 第一次驱逐被 PodDisruptionBudget 阻止
+	blocked := false
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if eviction.Name == "web-1" && !blocked {
+			blocked = true
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		return true, nil, client.Tracker().Delete(action.GetResource(), eviction.Namespace, eviction.Name)
+	})
+
+	task := runTestDrain(client, DrainOptions{})
+	assert.Equal(t, types.DrainStatusSucceeded, task.Status, task.Message)
+	assert.Equal(t, 2, task.Total)
+	assert.Equal(t, 2, task.Evicted)
+	status := make(map[string]string)
+	attempts := make(map[string]int)
+	for _, item := range task.Pods {
+		status[item.Name] = item.Status
+		attempts[item.Name] = item.Attempts
+	}
+	assert.Equal(t, map[string]string{
+		"web-1":                types.DrainPodEvicted,
+		"web-2":                types.DrainPodEvicted,
+		"fluentd-abcde":        types.DrainPodSkipped,
+		"kube-apiserver-node1": types.DrainPodSkipped,
+	}, status)
+	assert.Equal(t, 2, attempts["web-1"])
+
+	nodeK8s, err := client.CoreV1().Nodes().Get(context.Background(), "node1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, nodeK8s.Spec.Unschedulable)
+}
+
+func TestDrainBlocked(t *testing.T) {
+	standalone := drainTestPod("debug", "")
+	withEmptyDir := drainTestPod("cache-1", "ReplicaSet")
+	withEmptyDir.Spec.Volumes = []corev1.Volume{{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	client := fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}, standalone, withEmptyDir)
+
+	task := runTestDrain(client, DrainOptions{})
+	assert.Equal(t, types.DrainStatusFailed, task.Status)
+	assert.Equal(t, 2, task.Failed)
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "eviction", action.GetSubresource())
+	}
+
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		return true, nil, client.Tracker().Delete(action.GetResource(), eviction.Namespace, eviction.Name)
+	})
+	task = runTestDrain(client, DrainOptions{Force: true, DeleteEmptyDirData: true})
+	assert.Equal(t, types.DrainStatusSucceeded, task.Status, task.Message)
+	assert.Equal(t, 2, task.Evicted)
+}
@@ -60,5 +60,21 @@ func (*NodeK8s2Res) GetNodeResItem(nodeK8s *corev1.Node) *types.Node {
 		KernelVersion:    nodeInfo.KernelVersion,
 		ContainerRuntime: nodeInfo.ContainerRuntimeVersion,
 		Labels:           mapToList(nodeK8s.Labels),
+		Unschedulable:    nodeK8s.Spec.Unschedulable,
+		Conditions:       getNodeConditions(nodeK8s.Status.Conditions),
 	}
 }
+
+func getNodeConditions(nodeConditions []corev1.NodeCondition) []types.NodeCondition {
+	conditions := make([]types.NodeCondition, 0, len(nodeConditions))
+	for _, condition := range nodeConditions {
+		conditions = append(conditions, types.NodeCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Unix(),
+		})
+	}
+	return conditions
+}
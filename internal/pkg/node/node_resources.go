@@ -0,0 +1,131 @@
+package node
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+const mebibyte = 1024 * 1024
+
+// GetNodeResources 汇总节点上未结束的pod的 requests 和 limits, 与 kubectl describe node 的计算方式相同
+func (*NodeK8s2Res) GetNodeResources(nodeK8s *corev1.Node, pods []corev1.Pod) *types.NodeResources {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	podCount := int64(0)
+	for i := range pods {
+		if isPodTerminated(&pods[i]) {
+			continue
+		}
+		podCount++
+		podRequests, podLimits := podRequestsAndLimits(&pods[i])
+		addResourceList(requests, podRequests)
+		addResourceList(limits, podLimits)
+	}
+
+	capacity, allocatable := nodeK8s.Status.Capacity, nodeK8s.Status.Allocatable
+	return &types.NodeResources{
+		Cpu: nodeResource(capacity.Cpu().MilliValue(), allocatable.Cpu().MilliValue(),
+			requests.Cpu().MilliValue(), limits.Cpu().MilliValue()),
+		Memory: nodeResource(capacity.Memory().Value()/mebibyte, allocatable.Memory().Value()/mebibyte,
+			requests.Memory().Value()/mebibyte, limits.Memory().Value()/mebibyte),
+		EphemeralStorage: nodeResource(capacity.StorageEphemeral().Value()/mebibyte, allocatable.StorageEphemeral().Value()/mebibyte,
+			requests.StorageEphemeral().Value()/mebibyte, limits.StorageEphemeral().Value()/mebibyte),
+		Pods: nodeResource(capacity.Pods().Value(), allocatable.Pods().Value(), podCount, podCount),
+	}
+}
+
+// GetNodePods 节点上的pod及其资源配额
+func (*NodeK8s2Res) GetNodePods(pods []corev1.Pod) []*types.NodePod {
+	podList := make([]*types.NodePod, 0, len(pods))
+	for i := range pods {
+		requests, limits := podRequestsAndLimits(&pods[i])
+		podList = append(podList, &types.NodePod{
+			Namespace:  pods[i].Namespace,
+			Name:       pods[i].Name,
+			Phase:      string(pods[i].Status.Phase),
+			CpuRequest: requests.Cpu().MilliValue(),
+			CpuLimit:   limits.Cpu().MilliValue(),
+			MemRequest: requests.Memory().Value() / mebibyte,
+			MemLimit:   limits.Memory().Value() / mebibyte,
+			Age:        pods[i].CreationTimestamp.Unix(),
+		})
+	}
+	return podList
+}
+
+func nodeResource(capacity, allocatable, requests, limits int64) types.NodeResource {
+	res := types.NodeResource{
+		Capacity:    capacity,
+		Allocatable: allocatable,
+		Requests:    requests,
+		Limits:      limits,
+	}
+	if allocatable > 0 {
+		res.RequestsPercent = float64(requests) * 100 / float64(allocatable)
+		res.LimitsPercent = float64(limits) * 100 / float64(allocatable)
+	}
+	return res
+}
+
+func isPodTerminated(k8sPod *corev1.Pod) bool {
+	return k8sPod.Status.Phase == corev1.PodSucceeded || k8sPod.Status.Phase == corev1.PodFailed
+}
+
+// podRequestsAndLimits pod 实际占用的资源:
+// 普通容器与 sidecar(restartPolicy 为 Always 的 init 容器) 之和, 与 init 容器运行时的最大值取较大者, 再加上 overhead
+func podRequestsAndLimits(k8sPod *corev1.Pod) (corev1.ResourceList, corev1.ResourceList) {
+	requests := podResourceList(k8sPod, func(container *corev1.Container) corev1.ResourceList {
+		return container.Resources.Requests
+	})
+	limits := podResourceList(k8sPod, func(container *corev1.Container) corev1.ResourceList {
+		return container.Resources.Limits
+	})
+	return requests, limits
+}
+
+func podResourceList(k8sPod *corev1.Pod, get func(container *corev1.Container) corev1.ResourceList) corev1.ResourceList {
+	res := corev1.ResourceList{}
+	for i := range k8sPod.Spec.Containers {
+		addResourceList(res, get(&k8sPod.Spec.Containers[i]))
+	}
+
+	sidecars, initMax := corev1.ResourceList{}, corev1.ResourceList{}
+	for i := range k8sPod.Spec.InitContainers {
+		container := &k8sPod.Spec.InitContainers[i]
+		running := corev1.ResourceList{}
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			// sidecar 与普通容器同时运行
+			addResourceList(res, get(container))
+			addResourceList(sidecars, get(container))
+			addResourceList(running, sidecars)
+		} else {
+			addResourceList(running, get(container))
+			addResourceList(running, sidecars)
+		}
+		maxResourceList(initMax, running)
+	}
+	maxResourceList(res, initMax)
+
+	addResourceList(res, k8sPod.Spec.Overhead)
+	return res
+}
+
+func addResourceList(list, add corev1.ResourceList) {
+	for name, quantity := range add {
+		if value, ok := list[name]; ok {
+			value.Add(quantity)
+			list[name] = value
+		} else {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// maxResourceList list 中每项资源取与 other 的较大值
+func maxResourceList(list, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if value, ok := list[name]; !ok || quantity.Cmp(value) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
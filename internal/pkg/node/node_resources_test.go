@@ -0,0 +1,56 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func resourceList(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func TestGetNodeResources(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	nodeK8s := &corev1.Node{Status: corev1.NodeStatus{
+		Capacity:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("8Gi"), corev1.ResourcePods: resource.MustParse("110")},
+		Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3800m"), corev1.ResourceMemory: resource.MustParse("7Gi"), corev1.ResourcePods: resource.MustParse("110")},
+	}}
+	pods := []corev1.Pod{
+		{
+			Spec: corev1.PodSpec{
+				// init 容器运行时需要 2 核, 大于普通容器与 sidecar 之和
+				InitContainers: []corev1.Container{
+					{Name: "proxy", RestartPolicy: &always, Resources: corev1.ResourceRequirements{Requests: resourceList("100m", "64Mi")}},
+					{Name: "migrate", Resources: corev1.ResourceRequirements{Requests: resourceList("1900m", "128Mi")}},
+				},
+				Containers: []corev1.Container{
+					{Name: "app", Resources: corev1.ResourceRequirements{Requests: resourceList("500m", "512Mi"), Limits: resourceList("1", "1Gi")}},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "job", Resources: corev1.ResourceRequirements{Requests: resourceList("1", "1Gi")}}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+
+	res := (&NodeK8s2Res{}).GetNodeResources(nodeK8s, pods)
+	assert.Equal(t, int64(4000), res.Cpu.Capacity)
+	assert.Equal(t, int64(3800), res.Cpu.Allocatable)
+	assert.Equal(t, int64(2000), res.Cpu.Requests)
+	assert.Equal(t, int64(1000), res.Cpu.Limits)
+	assert.Equal(t, int64(576), res.Memory.Requests)
+	assert.Equal(t, int64(7168), res.Memory.Allocatable)
+	assert.Equal(t, int64(1), res.Pods.Requests)
+	assert.InDelta(t, 52.63, res.Cpu.RequestsPercent, 0.01)
+	assert.Equal(t, float64(0), res.EphemeralStorage.RequestsPercent)
+}
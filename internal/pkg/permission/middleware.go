@@ -0,0 +1,91 @@
+package permission
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+
+	"github.com/xiaofan193/k8sadmin/internal/ecode"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+)
+
+const ctxPermissionsKey = "permissions"
+
+// Middleware 按路由检查当前用户对k8s资源的权限, 必须在 auth.Middleware 之后使用
+// basePath 为路由组的路径, 如 /api/v1/k8s/:cluster
+func Middleware(basePath string) gin.HandlerFunc {
+	prefix := strings.TrimSuffix(basePath, "/") + "/"
+	return func(c *gin.Context) {
+		perms, ok := loadPermissions(c)
+		if !ok {
+			return
+		}
+		c.Set(ctxPermissionsKey, perms)
+
+		rel := strings.TrimPrefix(c.FullPath(), prefix)
+		if perms.IsAdmin() || exemptRoutes[rel] {
+			c.Next()
+			return
+		}
+
+		kind, verb := resolveRoute(c.Request.Method, rel, c.Param("kind"))
+		namespaces, err := requestNamespaces(c)
+		if err != nil {
+			logger.Warn("read request body error", logger.Err(err), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			c.Abort()
+			return
+		}
+		clusterName := c.Param("cluster")
+		for _, namespace := range namespaces {
+			if !perms.Allowed(clusterName, namespace, kind, verb) {
+				logger.Warn("permission denied", logger.String("user", c.GetString(auth.CtxUsernameKey)), logger.String("cluster", clusterName),
+					logger.String("namespace", namespace), logger.String("kind", kind), logger.String("verb", verb), middleware.GCtxRequestIDField(c))
+				response.Error(c, ecode.ErrPermissionDenied, fmt.Sprintf("%s %s in namespace %q", verb, kind, namespace))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin 只允许管理员访问, 用于用户、集群、镜像仓库和权限的管理接口, 必须在 auth.Middleware 之后使用
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := auth.CurrentUser(c)
+		if !ok || user.Type != model.UserTypeAdmin {
+			response.Error(c, ecode.ErrAdminRequired)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// FromContext 当前用户的权限, 只能在 Middleware 之后的handler中使用
+func FromContext(c *gin.Context) *Permissions {
+	return c.MustGet(ctxPermissionsKey).(*Permissions)
+}
+
+func loadPermissions(c *gin.Context) (*Permissions, bool) {
+	user, ok := auth.CurrentUser(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		c.Abort()
+		return nil, false
+	}
+	perms, err := Load(c.Request.Context(), user)
+	if err != nil {
+		logger.Error("load permissions error", logger.Err(err), logger.Any("uid", user.ID), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrLoadPermission)
+		c.Abort()
+		return nil, false
+	}
+	return perms, true
+}
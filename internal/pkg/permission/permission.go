@@ -0,0 +1,151 @@
+// Package permission 应用级的权限控制, 按集群、命名空间、资源类型和动作授权
+package permission
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+// 授权规则中的动作
+const (
+	VerbView       = "view"
+	VerbEdit       = "edit"
+	VerbDelete     = "delete"
+	VerbExec       = "exec"
+	VerbSecretRead = "secret-read" // 查看secret的内容, 列表只需要 view
+
+	// Wildcard 匹配全部
+	Wildcard = "*"
+)
+
+// 集群级资源, 授权这些资源不会使命名空间可见
+var clusterScopedKinds = map[string]bool{
+	"node":          true,
+	"pv":            true,
+	"sc":            true,
+	"priorityclass": true,
+}
+
+// 用户的规则缓存时间, 角色、团队和授权变化时会主动清空
+const rulesCacheTTL = time.Minute
+
+type rulesEntry struct {
+	rules    []model.PermissionRule
+	expireAt time.Time
+}
+
+var (
+	rulesMu    sync.Mutex
+	rulesCache = map[uint64]rulesEntry{}
+
+	// 测试时替换
+	loadRoles = func(ctx context.Context, userID uint64) ([]*model.AppRole, error) {
+		return dao.NewAppRoleDao(database.GetDB()).GetByUserID(ctx, userID)
+	}
+)
+
+// Permissions 用户在所有集群中的权限
+type Permissions struct {
+	admin bool
+	rules []model.PermissionRule
+}
+
+// NewPermissions admin 为true时拥有全部权限
+func NewPermissions(admin bool, rules []model.PermissionRule) *Permissions {
+	return &Permissions{admin: admin, rules: rules}
+}
+
+// Load 加载用户的权限, 包括直接授予和通过团队授予的角色
+func Load(ctx context.Context, user *model.User) (*Permissions, error) {
+	if user.Type == model.UserTypeAdmin {
+		return NewPermissions(true, nil), nil
+	}
+
+	rulesMu.Lock()
+	entry, ok := rulesCache[user.ID]
+	rulesMu.Unlock()
+	if ok && time.Now().Before(entry.expireAt) {
+		return NewPermissions(false, entry.rules), nil
+	}
+
+	roles, err := loadRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]model.PermissionRule, 0)
+	for _, role := range roles {
+		rules = append(rules, role.Rules...)
+	}
+
+	rulesMu.Lock()
+	rulesCache[user.ID] = rulesEntry{rules: rules, expireAt: time.Now().Add(rulesCacheTTL)}
+	rulesMu.Unlock()
+	return NewPermissions(false, rules), nil
+}
+
+// Invalidate 角色、团队或授权变化后清空缓存, 立即生效
+func Invalidate() {
+	rulesMu.Lock()
+	rulesCache = map[uint64]rulesEntry{}
+	rulesMu.Unlock()
+}
+
+// IsAdmin 是否为管理员
+func (p *Permissions) IsAdmin() bool {
+	return p.admin
+}
+
+// Allowed 是否允许在集群的命名空间中对资源执行动作, 集群级资源的 namespace 为空
+func (p *Permissions) Allowed(cluster, namespace, kind, verb string) bool {
+	if p.admin {
+		return true
+	}
+	for _, rule := range p.rules {
+		if match(rule.Cluster, cluster) && matchAny(rule.Namespaces, namespace) &&
+			matchAny(rule.Kinds, kind) && matchAny(rule.Verbs, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+// NamespaceVisible 在命名空间中可以查看任意命名空间级资源时, 该命名空间出现在命名空间列表中
+func (p *Permissions) NamespaceVisible(cluster, namespace string) bool {
+	if p.admin {
+		return true
+	}
+	for _, rule := range p.rules {
+		if match(rule.Cluster, cluster) && matchAny(rule.Namespaces, namespace) &&
+			matchAny(rule.Verbs, VerbView) && hasNamespacedKind(rule.Kinds) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasNamespacedKind(kinds []string) bool {
+	for _, kind := range kinds {
+		if !clusterScopedKinds[kind] {
+			return true
+		}
+	}
+	return false
+}
+
+func match(pattern, value string) bool {
+	return pattern == Wildcard || pattern == value
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package permission
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xiaofan193/k8sadmin/internal/model"
+)
+
+func TestPermissionsAllowed(t *testing.T) {
+	perms := NewPermissions(false, []model.PermissionRule{
+		{Cluster: "prod", Namespaces: []string{"app"}, Kinds: []string{"pod", "deployment"}, Verbs: []string{VerbView, VerbEdit}},
+		{Cluster: "*", Namespaces: []string{"*"}, Kinds: []string{"node"}, Verbs: []string{VerbView}},
+	})
+
+	assert.True(t, perms.Allowed("prod", "app", "pod", VerbView))
+	assert.True(t, perms.Allowed("prod", "app", "deployment", VerbEdit))
+	assert.False(t, perms.Allowed("prod", "app", "pod", VerbDelete))
+	assert.False(t, perms.Allowed("prod", "app", "secret", VerbView))
+	assert.False(t, perms.Allowed("prod", "other", "pod", VerbView))
+	assert.False(t, perms.Allowed("test", "app", "pod", VerbView))
+	// 集群级资源只匹配命名空间为 * 的规则
+	assert.True(t, perms.Allowed("test", "", "node", VerbView))
+	assert.False(t, perms.Allowed("prod", "", "pod", VerbView))
+
+	assert.True(t, perms.NamespaceVisible("prod", "app"))
+	// 只授权了集群级资源的规则不会使命名空间可见
+	assert.False(t, perms.NamespaceVisible("prod", "other"))
+	assert.False(t, NewPermissions(false, nil).NamespaceVisible("prod", "app"))
+
+	admin := NewPermissions(true, nil)
+	assert.True(t, admin.Allowed("prod", "kube-system", "secret", VerbSecretRead))
+	assert.True(t, admin.NamespaceVisible("prod", "kube-system"))
+}
+
+func TestLoad(t *testing.T) {
+	old := loadRoles
+	t.Cleanup(func() {
+		loadRoles = old
+		Invalidate()
+	})
+	calls := 0
+	loadRoles = func(ctx context.Context, userID uint64) ([]*model.AppRole, error) {
+		calls++
+		return []*model.AppRole{{Rules: []model.PermissionRule{
+			{Cluster: "prod", Namespaces: []string{"app"}, Kinds: []string{"*"}, Verbs: []string{VerbView}},
+		}}}, nil
+	}
+
+	user := &model.User{ID: 3, Type: model.UserTypeMember}
+	perms, err := Load(context.Background(), user)
+	require.NoError(t, err)
+	assert.True(t, perms.Allowed("prod", "app", "svc", VerbView))
+	_, err = Load(context.Background(), user)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	Invalidate()
+	_, err = Load(context.Background(), user)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// 管理员不查询角色
+	perms, err = Load(context.Background(), &model.User{ID: 1, Type: model.UserTypeAdmin})
+	require.NoError(t, err)
+	assert.True(t, perms.IsAdmin())
+	assert.Equal(t, 2, calls)
+}
+
+func TestResolveRoute(t *testing.T) {
+	tests := []struct {
+		method, rel, kindParam string
+		kind, verb             string
+	}{
+		{http.MethodGet, "pod/:namespace", "", "pod", VerbView},
+		{http.MethodPost, "pod", "", "pod", VerbEdit},
+		{http.MethodDelete, "pod/:namespace/:name", "", "pod", VerbDelete},
+		{http.MethodGet, "pod/:namespace/:name/exec", "", "pod", VerbExec},
+		{http.MethodGet, "pod/:namespace/:name/logs", "", "pod", VerbView},
+		{http.MethodGet, "secret/:namespace", "", "secret", VerbView},
+		{http.MethodGet, "secret/:namespace/:name", "", "secret", VerbSecretRead},
+		{http.MethodGet, "secret/:namespace/:name/yaml", "", "secret", VerbSecretRead},
+		{http.MethodGet, "role/binding/:name/yaml", "", "rolebinding", VerbView},
+		{http.MethodPut, "deployment/:namespace/:name/scale", "", "deployment", VerbEdit},
+		{http.MethodPost, "role/binding", "", "rolebinding", VerbEdit},
+		{http.MethodGet, "role/:namespace", "", "role", VerbView},
+		{http.MethodGet, "metrics/deployment/:namespace/:name", "", "deployment", VerbView},
+		{http.MethodGet, "watch/:kind/:namespace", "svc", "svc", VerbView},
+		{http.MethodPost, "lint/pod", "", "pod", VerbView},
+		{http.MethodPost, "node/:name/drain", "", "node", VerbEdit},
+	}
+	for _, tt := range tests {
+		kind, verb := resolveRoute(tt.method, tt.rel, tt.kindParam)
+		assert.Equal(t, tt.kind, kind, tt.rel)
+		assert.Equal(t, tt.verb, verb, tt.rel)
+	}
+}
+
+func TestRequestNamespaces(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"namespace":"app","base":{"Namespace":"kube-system","name":"web"},"labels":[{"key":"a"}]}`
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/pod?namespace=app", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "text/plain")
+
+	namespaces, err := requestNamespaces(c)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "kube-system"}, namespaces)
+	// handler 仍然可以读取完整的请求体
+	data, err := io.ReadAll(c.Request.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data))
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/node", nil)
+	namespaces, err = requestNamespaces(c)
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, namespaces)
+}
@@ -0,0 +1,166 @@
+package permission
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求体最多读取的字节数, 超过时不再解析其中的命名空间
+const maxBodyNamespaceSize = 4 << 20
+
+// 不按路由检查权限的k8s接口, 由接口自身按权限过滤结果
+var exemptRoutes = map[string]bool{
+	"namespace":         true, // 只返回可见的命名空间
+	"permission/check":  true,
+	"apply":             true, // 按每个对象的类型和命名空间检查
+	"export/:namespace": true, // 只导出有权限的资源类型
+}
+
+// 资源类型与路由名称不同的k8s kind
+var kindAliases = map[string]string{
+	"PersistentVolume":      "pv",
+	"PersistentVolumeClaim": "pvc",
+	"StorageClass":          "sc",
+	"Service":               "svc",
+	"ServiceAccount":        "sa",
+	"ClusterRole":           "role",
+	"ClusterRoleBinding":    "rolebinding",
+	"IngressRoute":          "ingroute",
+}
+
+// ObjectKind k8s kind 对应的资源类型, 有路由的资源与路由名称一致, 其余为小写的kind, 如 horizontalpodautoscaler
+func ObjectKind(kind string) string {
+	if alias, ok := kindAliases[kind]; ok {
+		return alias
+	}
+	return strings.ToLower(kind)
+}
+
+// ResolveRoute 请求对应的资源类型和动作, basePath 与 Middleware 相同
+func ResolveRoute(c *gin.Context, basePath string) (string, string) {
+	rel := strings.TrimPrefix(c.FullPath(), strings.TrimSuffix(basePath, "/")+"/")
+	return resolveRoute(c.Request.Method, rel, c.Param("kind"))
+}
+
+// resolveRoute 根据路由推导资源类型和动作, rel 为去掉 /api/v1/k8s/:cluster/ 后的路由
+// 资源类型与路由第一段相同, 如 pod、svc、sa、ingroute, 与 watch 接口的 kind 一致
+func resolveRoute(method, rel, kindParam string) (string, string) {
+	segments := strings.Split(rel, "/")
+	kind := segments[0]
+	// lint 接口只检查请求体, 不修改资源
+	if kind == "lint" && len(segments) > 1 {
+		return segments[1], VerbView
+	}
+	switch {
+	case kind == "role" && len(segments) > 1 && segments[1] == "binding":
+		kind = "rolebinding"
+	case kind == "metrics" && len(segments) > 1:
+		kind = segments[1]
+	case kind == "watch":
+		kind = kindParam
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		last := segments[len(segments)-1]
+		if last == "exec" {
+			return kind, VerbExec
+		}
+		// 只有详情和YAML接口返回secret的内容
+		if kind == "secret" && (last == ":name" || last == "yaml") {
+			return kind, VerbSecretRead
+		}
+		return kind, VerbView
+	case http.MethodDelete:
+		return kind, VerbDelete
+	default:
+		return kind, VerbEdit
+	}
+}
+
+// requestNamespaces 请求涉及的所有命名空间, 来自路径参数、查询参数和json请求体
+// 请求体中顶层和第二层的 namespace 都会返回, 防止用一个有权限的命名空间掩盖实际操作的命名空间
+// 没有命名空间时返回空字符串, 只能匹配命名空间为 * 的规则
+func requestNamespaces(c *gin.Context) ([]string, error) {
+	namespaces := make([]string, 0, 1)
+	add := func(namespace string) {
+		for _, item := range namespaces {
+			if item == namespace {
+				return
+			}
+		}
+		namespaces = append(namespaces, namespace)
+	}
+
+	if namespace := c.Param("namespace"); namespace != "" {
+		add(namespace)
+	}
+	if namespace, ok := c.GetQuery("namespace"); ok {
+		add(namespace)
+	}
+	// 不判断 Content-Type, ShouldBindJSON 不检查请求头
+	if c.Request.Body != nil && c.Request.Body != http.NoBody && c.Request.ContentLength != 0 {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBodyNamespaceSize))
+		if err != nil {
+			return nil, err
+		}
+		// 还原请求体, handler 中还需要绑定参数
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		for _, namespace := range bodyNamespaces(body) {
+			add(namespace)
+		}
+	}
+
+	if len(namespaces) == 0 {
+		add("")
+	}
+	return namespaces, nil
+}
+
+// bodyNamespaces 与 json 绑定一致, 字段名不区分大小写
+func bodyNamespaces(body []byte) []string {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	namespaces := namespaceFields(fields, keys)
+	for _, key := range keys {
+		nested := map[string]json.RawMessage{}
+		if err := json.Unmarshal(fields[key], &nested); err != nil {
+			continue
+		}
+		nestedKeys := make([]string, 0, len(nested))
+		for nestedKey := range nested {
+			nestedKeys = append(nestedKeys, nestedKey)
+		}
+		sort.Strings(nestedKeys)
+		namespaces = append(namespaces, namespaceFields(nested, nestedKeys)...)
+	}
+	return namespaces
+}
+
+func namespaceFields(fields map[string]json.RawMessage, keys []string) []string {
+	namespaces := make([]string, 0)
+	for _, key := range keys {
+		if !strings.EqualFold(key, "namespace") {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(fields[key], &value); err == nil {
+			namespaces = append(namespaces, value)
+		}
+	}
+	return namespaces
+}
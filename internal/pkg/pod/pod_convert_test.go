@@ -0,0 +1,196 @@
+package pod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+func TestSecurityContextRoundTrip(t *testing.T) {
+	podReq := &types.Pod{
+		Base: types.Base{Name: "nginx", Namespace: "default"},
+		SecurityContext: &types.PodSecurityContext{
+			RunAsUser:      ptr.To[int64](1000),
+			RunAsNonRoot:   ptr.To(true),
+			FSGroup:        ptr.To[int64](2000),
+			SeccompProfile: &types.SeccompProfile{Type: "RuntimeDefault"},
+		},
+		Containers: []types.Container{{
+			Name:  "nginx",
+			Image: "nginx:1.25",
+			SecurityContext: &types.ContainerSecurityContext{
+				ReadOnlyRootFilesystem:   ptr.To(true),
+				AllowPrivilegeEscalation: ptr.To(false),
+				Capabilities:             &types.Capabilities{Add: []string{"NET_BIND_SERVICE"}, Drop: []string{"ALL"}},
+				AppArmorProfile:          &types.AppArmorProfile{Type: "Localhost", LocalhostProfile: "k8s-nginx"},
+			},
+		}, {
+			Name:       "sidecar",
+			Image:      "busybox",
+			Privileged: true,
+		}},
+	}
+
+	podK8s := (&Req2K8sConvert{}).PodReq2K8s(podReq)
+	require.NotNil(t, podK8s.Spec.SecurityContext)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, podK8s.Spec.SecurityContext.SeccompProfile.Type)
+	assert.Nil(t, podK8s.Spec.SecurityContext.SeccompProfile.LocalhostProfile)
+	containerCtx := podK8s.Spec.Containers[0].SecurityContext
+	assert.Equal(t, []corev1.Capability{"ALL"}, containerCtx.Capabilities.Drop)
+	assert.Equal(t, "k8s-nginx", *containerCtx.AppArmorProfile.LocalhostProfile)
+	assert.False(t, *containerCtx.Privileged)
+
+	podRes := (&K8s2ReqConvert{}).PodK8s2Req(*podK8s)
+	assert.Equal(t, podReq.SecurityContext, podRes.SecurityContext)
+	assert.Equal(t, podReq.Containers[0].SecurityContext, podRes.Containers[0].SecurityContext)
+	// 只设置了特权模式时不返回 securityContext
+	assert.True(t, podRes.Containers[1].Privileged)
+	assert.Nil(t, podRes.Containers[1].SecurityContext)
+
+	// apiserver 填充的空 securityContext
+	podK8s.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	podK8s.Spec.Containers[1].SecurityContext = &corev1.SecurityContext{}
+	podRes = (&K8s2ReqConvert{}).PodK8s2Req(*podK8s)
+	assert.Nil(t, podRes.SecurityContext)
+	assert.False(t, podRes.Containers[1].Privileged)
+}
+
+func TestVolumeRoundTrip(t *testing.T) {
+	podReq := &types.Pod{
+		Base: types.Base{Name: "nginx", Namespace: "default"},
+		Volumes: []types.Volume{
+			{Name: "cache", Type: "emptyDir", EmptyDirVolume: types.EmptyDirVolume{Medium: corev1.StorageMediumMemory, SizeLimit: "256Mi"}},
+			{Name: "conf", Type: "configMap", ConfigMapRefVolume: types.ConfigMapRefVolume{
+				Name:        "nginx-conf",
+				Items:       []types.KeyToPath{{Key: "nginx.conf", Path: "conf.d/default.conf"}},
+				DefaultMode: ptr.To[int32](0400),
+			}},
+			{Name: "tls", Type: "secret", SecretRefVolume: types.SecretRefVolume{
+				Name:     "nginx-tls",
+				Optional: true,
+				Items:    []types.KeyToPath{{Key: "tls.key", Path: "server.key", Mode: ptr.To[int32](0400)}},
+			}},
+			{Name: "combined", Type: "projected", ProjectedVolume: types.ProjectedVolume{Sources: []types.ProjectedVolumeSource{
+				{Type: "configMap", ConfigMap: types.ConfigMapRefVolume{Name: "nginx-conf"}},
+				{Type: "secret", Secret: types.SecretRefVolume{Name: "nginx-tls", Items: []types.KeyToPath{{Key: "tls.crt", Path: "server.crt"}}}},
+				{Type: "serviceAccountToken", ServiceAccountToken: types.ServiceAccountTokenProjection{Audience: "vault", ExpirationSeconds: ptr.To[int64](600), Path: "token"}},
+			}}},
+			{Name: "share", Type: "nfs", NFSVolume: types.NFSVolume{Server: "10.0.0.2", Path: "/exports", ReadOnly: true}},
+			{Name: "secrets-store", Type: "csi", CSIVolume: types.CSIVolume{
+				Driver:           "secrets-store.csi.k8s.io",
+				ReadOnly:         true,
+				VolumeAttributes: []types.ListMapItem{{Key: "secretProviderClass", Value: "vault"}},
+			}},
+			{Name: "scratch", Type: "ephemeral", EphemeralVolume: types.EphemeralVolume{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: "standard",
+				Storage:          "1Gi",
+			}},
+		},
+		Containers: []types.Container{{
+			Name:  "nginx",
+			Image: "nginx:1.25",
+			VolumeMounts: []types.VolumeMount{
+				{MountName: "conf", MountPath: "/etc/nginx/conf.d", SubPath: "conf.d"},
+				{MountName: "scratch", MountPath: "/data", SubPathExpr: "$(POD_NAME)"},
+			},
+		}},
+	}
+
+	podK8s := (&Req2K8sConvert{}).PodReq2K8s(podReq)
+	require.Len(t, podK8s.Spec.Volumes, len(podReq.Volumes))
+	assert.Equal(t, "256Mi", podK8s.Spec.Volumes[0].EmptyDir.SizeLimit.String())
+	assert.Equal(t, []corev1.KeyToPath{{Key: "nginx.conf", Path: "conf.d/default.conf"}}, podK8s.Spec.Volumes[1].ConfigMap.Items)
+	assert.Len(t, podK8s.Spec.Volumes[3].Projected.Sources, 3)
+	assert.Equal(t, "1Gi", podK8s.Spec.Volumes[6].Ephemeral.VolumeClaimTemplate.Spec.Resources.Requests.Storage().String())
+	assert.Equal(t, "conf.d", podK8s.Spec.Containers[0].VolumeMounts[0].SubPath)
+
+	// apiserver 填充的默认权限和自动注入的serviceAccount token卷
+	podK8s.Spec.Volumes[2].Secret.DefaultMode = ptr.To(corev1.SecretVolumeSourceDefaultMode)
+	podK8s.Spec.Volumes = append(podK8s.Spec.Volumes, corev1.Volume{
+		Name:         "kube-api-access-x7k2p",
+		VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{}},
+	})
+	podK8s.Spec.Containers[0].VolumeMounts = append(podK8s.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      "kube-api-access-x7k2p",
+		MountPath: "/var/run/secrets/kubernetes.io/serviceaccount",
+	})
+
+	podRes := (&K8s2ReqConvert{}).PodK8s2Req(*podK8s)
+	assert.Equal(t, podReq.Volumes, podRes.Volumes)
+	assert.Equal(t, podReq.Containers[0].VolumeMounts, podRes.Containers[0].VolumeMounts)
+}
+
+func TestSchedulingRoundTrip(t *testing.T) {
+	appSelector := types.LabelSelector{MatchLabels: []types.ListMapItem{{Key: "app", Value: "nginx"}}}
+	podReq := &types.Pod{
+		Base: types.Base{Name: "nginx", Namespace: "default"},
+		NodeScheduling: types.NodeScheduling{
+			Type:         "nodeSelector",
+			NodeSelector: []types.ListMapItem{{Key: "disktype", Value: "ssd"}},
+			PreferredNodeAffinity: []types.PreferredNodeAffinity{{
+				Weight: 80,
+				MatchExpressions: []types.NodeSelectorTermExpressions{
+					{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Value: "zone-a,zone-b"},
+				},
+			}},
+			PodAffinity: types.PodAffinity{
+				Preferred: []types.WeightedPodAffinityTerm{{
+					Weight: 50,
+					PodAffinityTerm: types.PodAffinityTerm{
+						LabelSelector: types.LabelSelector{MatchLabels: []types.ListMapItem{{Key: "app", Value: "redis"}}},
+						TopologyKey:   "kubernetes.io/hostname",
+					},
+				}},
+			},
+			PodAntiAffinity: types.PodAffinity{
+				Required: []types.PodAffinityTerm{{
+					LabelSelector: types.LabelSelector{MatchExpressions: []types.LabelSelectorExpression{
+						{Key: "app", Operator: metav1.LabelSelectorOpIn, Value: "nginx"},
+						{Key: "canary", Operator: metav1.LabelSelectorOpDoesNotExist},
+					}},
+					Namespaces:  []string{"default", "web"},
+					TopologyKey: "kubernetes.io/hostname",
+				}},
+			},
+			TopologySpreadConstraints: []types.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     appSelector,
+				MinDomains:        ptr.To[int32](2),
+			}},
+			PriorityClassName: "high-priority",
+			SchedulerName:     "default-scheduler",
+		},
+		Containers: []types.Container{{Name: "nginx", Image: "nginx:1.25"}},
+	}
+
+	podK8s := (&Req2K8sConvert{}).PodReq2K8s(podReq)
+	affinity := podK8s.Spec.Affinity
+	require.NotNil(t, affinity)
+	assert.Nil(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	assert.Equal(t, []string{"zone-a", "zone-b"}, affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].Preference.MatchExpressions[0].Values)
+	assert.Nil(t, affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector.MatchExpressions[1].Values)
+	assert.Equal(t, "high-priority", podK8s.Spec.PriorityClassName)
+
+	podRes := (&K8s2ReqConvert{}).PodK8s2Req(*podK8s)
+	assert.Equal(t, podReq.NodeScheduling, podRes.NodeScheduling)
+
+	// 只有反亲和性时不是节点亲和性调度
+	podK8s.Spec.NodeSelector = nil
+	podK8s.Spec.Affinity.NodeAffinity = nil
+	podRes = (&K8s2ReqConvert{}).PodK8s2Req(*podK8s)
+	assert.Equal(t, "nodeAny", podRes.NodeScheduling.Type)
+	assert.Equal(t, podReq.NodeScheduling.PodAntiAffinity, podRes.NodeScheduling.PodAntiAffinity)
+
+	// 没有调度配置时不设置 affinity
+	podReq.NodeScheduling = types.NodeScheduling{Type: "nodeAny"}
+	assert.Nil(t, (&Req2K8sConvert{}).PodReq2K8s(podReq).Spec.Affinity)
+}
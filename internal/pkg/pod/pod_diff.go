@@ -0,0 +1,187 @@
+package pod
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+// serviceAccount token 卷由准入控制自动注入, 名称带随机后缀, 比较时忽略
+const serviceAccountVolumePrefix = "kube-api-access-"
+
+// Diff 比较线上pod与期望的pod, desired 需要是经过apiserver默认值填充的对象(如 dry-run 创建的返回值)
+// 返回变化的字段, 以及只包含可原地更新字段的新对象
+// 允许原地更新: labels、annotations、容器镜像、设置或减小activeDeadlineSeconds、新增tolerations
+// withAnnotations 为false时忽略annotations
+func Diff(current, desired *corev1.Pod, withAnnotations bool) (*corev1.Pod, []types.PodFieldChange) {
+	patched := current.DeepCopy()
+	changes := make([]types.PodFieldChange, 0)
+	add := func(field string, oldValue, newValue interface{}, mutable bool) {
+		changes = append(changes, types.PodFieldChange{
+			Field:   field,
+			Old:     toJSON(oldValue),
+			New:     toJSON(newValue),
+			Mutable: mutable,
+		})
+	}
+
+	if !maps.Equal(current.Labels, desired.Labels) {
+		add("metadata.labels", current.Labels, desired.Labels, true)
+		patched.Labels = desired.Labels
+	}
+	if withAnnotations && !maps.Equal(current.Annotations, desired.Annotations) {
+		add("metadata.annotations", current.Annotations, desired.Annotations, true)
+		patched.Annotations = desired.Annotations
+	}
+
+	cur := current.Spec.DeepCopy()
+	want := desired.Spec.DeepCopy()
+	stripServiceAccountVolumes(cur)
+	stripServiceAccountVolumes(want)
+	// 未指定节点时由调度器分配
+	if want.NodeName == "" {
+		want.NodeName = cur.NodeName
+	}
+
+	diffContainers("spec.initContainers", cur.InitContainers, want.InitContainers, patched.Spec.InitContainers, add)
+	want.InitContainers = cur.InitContainers
+	diffContainers("spec.containers", cur.Containers, want.Containers, patched.Spec.Containers, add)
+	want.Containers = cur.Containers
+
+	// activeDeadlineSeconds 只能从未设置改为设置, 或者减小; 增大和清除需要重建
+	if !equality.Semantic.DeepEqual(cur.ActiveDeadlineSeconds, want.ActiveDeadlineSeconds) {
+		mutable := want.ActiveDeadlineSeconds != nil &&
+			(cur.ActiveDeadlineSeconds == nil || *want.ActiveDeadlineSeconds <= *cur.ActiveDeadlineSeconds)
+		add("spec.activeDeadlineSeconds", cur.ActiveDeadlineSeconds, want.ActiveDeadlineSeconds, mutable)
+		if mutable {
+			patched.Spec.ActiveDeadlineSeconds = want.ActiveDeadlineSeconds
+		}
+		want.ActiveDeadlineSeconds = cur.ActiveDeadlineSeconds
+	}
+
+	// tolerations 只允许新增
+	if !equality.Semantic.DeepEqual(cur.Tolerations, want.Tolerations) {
+		mutable := containsTolerations(want.Tolerations, cur.Tolerations)
+		add("spec.tolerations", cur.Tolerations, want.Tolerations, mutable)
+		if mutable {
+			patched.Spec.Tolerations = want.Tolerations
+		}
+		want.Tolerations = cur.Tolerations
+	}
+
+	// 其余字段都不能原地更新
+	curValue, wantValue := reflect.ValueOf(*cur), reflect.ValueOf(*want)
+	for i := 0; i < curValue.NumField(); i++ {
+		if equality.Semantic.DeepEqual(curValue.Field(i).Interface(), wantValue.Field(i).Interface()) {
+			continue
+		}
+		name, _, _ := strings.Cut(curValue.Type().Field(i).Tag.Get("json"), ",")
+		add("spec."+name, curValue.Field(i).Interface(), wantValue.Field(i).Interface(), false)
+	}
+
+	return patched, changes
+}
+
+// NeedRecreate 变化中是否有不能原地更新的字段
+func NeedRecreate(changes []types.PodFieldChange) bool {
+	for _, change := range changes {
+		if !change.Mutable {
+			return true
+		}
+	}
+	return false
+}
+
+// diffContainers 容器数量和名称不变时逐个比较, 镜像变化写入patched
+func diffContainers(field string, cur, want, patched []corev1.Container,
+	add func(field string, oldValue, newValue interface{}, mutable bool)) {
+	if !sameContainerNames(cur, want) {
+		add(field, containerNames(cur), containerNames(want), false)
+		return
+	}
+	for i := range cur {
+		itemField := fmt.Sprintf("%s[%s]", field, cur[i].Name)
+		wantItem := want[i].DeepCopy()
+		if cur[i].Image != wantItem.Image {
+			add(itemField+".image", cur[i].Image, wantItem.Image, true)
+			patched[i].Image = wantItem.Image
+			wantItem.Image = cur[i].Image
+		}
+		if !equality.Semantic.DeepEqual(cur[i], *wantItem) {
+			add(itemField, cur[i], *wantItem, false)
+		}
+	}
+}
+
+func sameContainerNames(a, b []corev1.Container) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+func containerNames(containers []corev1.Container) []string {
+	names := make([]string, 0, len(containers))
+	for _, container := range containers {
+		names = append(names, container.Name)
+	}
+	return names
+}
+
+// containsTolerations all 是否包含 sub 中的所有toleration
+func containsTolerations(all, sub []corev1.Toleration) bool {
+	for i := range sub {
+		found := false
+		for j := range all {
+			if equality.Semantic.DeepEqual(sub[i], all[j]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func stripServiceAccountVolumes(spec *corev1.PodSpec) {
+	volumes := make([]corev1.Volume, 0, len(spec.Volumes))
+	for _, volume := range spec.Volumes {
+		if !strings.HasPrefix(volume.Name, serviceAccountVolumePrefix) {
+			volumes = append(volumes, volume)
+		}
+	}
+	spec.Volumes = volumes
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			mounts := make([]corev1.VolumeMount, 0, len(containers[i].VolumeMounts))
+			for _, mount := range containers[i].VolumeMounts {
+				if !strings.HasPrefix(mount.Name, serviceAccountVolumePrefix) {
+					mounts = append(mounts, mount)
+				}
+			}
+			containers[i].VolumeMounts = mounts
+		}
+	}
+}
+
+func toJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
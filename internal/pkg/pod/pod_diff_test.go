@@ -0,0 +1,150 @@
+package pod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func testPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nginx",
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "nginx"},
+			Annotations: map[string]string{"owner": "ops"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node1",
+			Containers: []corev1.Container{{
+				Name:         "nginx",
+				Image:        "nginx:1.25",
+				VolumeMounts: []corev1.VolumeMount{{Name: "kube-api-access-abcde", MountPath: "/var/run/secrets"}},
+			}},
+			Volumes:     []corev1.Volume{{Name: "kube-api-access-abcde"}},
+			Tolerations: []corev1.Toleration{{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists}},
+		},
+	}
+}
+
+// desiredPod 模拟 dry-run 返回的对象: 未调度, serviceAccount 卷名不同
+func desiredPod() *corev1.Pod {
+	desired := testPod()
+	desired.Spec.NodeName = ""
+	desired.Spec.Volumes[0].Name = "kube-api-access-xyz12"
+	desired.Spec.Containers[0].VolumeMounts[0].Name = "kube-api-access-xyz12"
+	return desired
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	_, changes := Diff(testPod(), desiredPod(), true)
+	assert.Empty(t, changes)
+}
+
+func TestDiffMutable(t *testing.T) {
+	desired := desiredPod()
+	desired.Labels["version"] = "v2"
+	desired.Annotations = nil
+	desired.Spec.Containers[0].Image = "nginx:1.27"
+	desired.Spec.Tolerations = append(desired.Spec.Tolerations, corev1.Toleration{Key: "gpu", Operator: corev1.TolerationOpExists})
+
+	patched, changes := Diff(testPod(), desired, false)
+	assert.False(t, NeedRecreate(changes))
+	fields := make([]string, 0)
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	assert.Equal(t, []string{"metadata.labels", "spec.containers[nginx].image", "spec.tolerations"}, fields)
+
+	assert.Equal(t, "v2", patched.Labels["version"])
+	assert.Equal(t, "ops", patched.Annotations["owner"])
+	assert.Equal(t, "nginx:1.27", patched.Spec.Containers[0].Image)
+	assert.Len(t, patched.Spec.Tolerations, 2)
+	assert.Equal(t, "node1", patched.Spec.NodeName)
+}
+
+func TestDiffImmutable(t *testing.T) {
+	desired := desiredPod()
+	desired.Spec.Containers[0].Command = []string{"sleep", "3600"}
+	desired.Spec.Tolerations = nil
+	desired.Spec.HostNetwork = true
+
+	_, changes := Diff(testPod(), desired, true)
+	assert.True(t, NeedRecreate(changes))
+	mutable := make(map[string]bool)
+	for _, change := range changes {
+		mutable[change.Field] = change.Mutable
+	}
+	assert.Equal(t, map[string]bool{
+		"spec.containers[nginx]": false,
+		"spec.tolerations":       false,
+		"spec.hostNetwork":       false,
+	}, mutable)
+
+	desired = desiredPod()
+	desired.Spec.Containers = append(desired.Spec.Containers, corev1.Container{Name: "sidecar", Image: "busybox"})
+	_, changes = Diff(testPod(), desired, true)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "spec.containers", changes[0].Field)
+	assert.Equal(t, `["nginx","sidecar"]`, changes[0].New)
+}
+
+func TestDiffActiveDeadlineSeconds(t *testing.T) {
+	tests := []struct {
+		name      string
+		cur, want *int64
+		mutable   bool
+	}{
+		{"set from nil", nil, ptr.To[int64](600), true},
+		{"decrease", ptr.To[int64](600), ptr.To[int64](300), true},
+		{"increase", ptr.To[int64](300), ptr.To[int64](600), false},
+		{"unset", ptr.To[int64](600), nil, false},
+	}
+	for _, tt := range tests {
+		current := testPod()
+		current.Spec.ActiveDeadlineSeconds = tt.cur
+		desired := desiredPod()
+		desired.Spec.ActiveDeadlineSeconds = tt.want
+
+		patched, changes := Diff(current, desired, true)
+		if assert.Len(t, changes, 1, tt.name) {
+			assert.Equal(t, "spec.activeDeadlineSeconds", changes[0].Field, tt.name)
+			assert.Equal(t, tt.mutable, changes[0].Mutable, tt.name)
+		}
+		assert.Equal(t, !tt.mutable, NeedRecreate(changes), tt.name)
+		if tt.mutable {
+			assert.Equal(t, tt.want, patched.Spec.ActiveDeadlineSeconds, tt.name)
+		} else {
+			assert.Equal(t, tt.cur, patched.Spec.ActiveDeadlineSeconds, tt.name)
+		}
+	}
+}
+
+func TestTemplateDiff(t *testing.T) {
+	from := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "nginx", "pod-template-hash": "abc"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.25"}}},
+	}
+	to := from.DeepCopy()
+	to.Labels["pod-template-hash"] = "def"
+	to.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "2024-01-01T00:00:00Z"}
+	to.Spec.Containers[0].Image = "nginx:1.27"
+	to.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "MODE", Value: "prod"}}
+	to.Spec.HostNetwork = true
+
+	changes := TemplateDiff(from, to)
+	fields := make([]string, 0)
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	assert.Equal(t, []string{
+		"metadata.annotations",
+		"spec.containers[nginx].image",
+		"spec.containers[nginx].env",
+		"spec.hostNetwork",
+	}, fields)
+	assert.Equal(t, `"nginx:1.27"`, changes[1].New)
+}
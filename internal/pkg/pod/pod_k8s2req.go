@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/xiaofan193/k8sadmin/internal/types"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"strings"
 )
 
@@ -42,8 +44,32 @@ func (k *K8s2ReqConvert) PodK8s2ItemRes(pod corev1.Pod) types.PodListItem {
 
 func getNodeReqScheduling(podK8s corev1.Pod) types.NodeScheduling {
 	nodeScheduling := types.NodeScheduling{
-		Type: scheduling_nodeany,
+		Type:                      scheduling_nodeany,
+		TopologySpreadConstraints: getReqTopologySpreadConstraints(podK8s.Spec.TopologySpreadConstraints),
+		PriorityClassName:         podK8s.Spec.PriorityClassName,
+		SchedulerName:             podK8s.Spec.SchedulerName,
 	}
+	affinity := podK8s.Spec.Affinity
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity != nil {
+		for _, item := range affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			nodeScheduling.PreferredNodeAffinity = append(nodeScheduling.PreferredNodeAffinity, types.PreferredNodeAffinity{
+				Weight:           item.Weight,
+				MatchExpressions: getReqNodeSelectorExpressions(item.Preference.MatchExpressions),
+			})
+		}
+	}
+	if affinity.PodAffinity != nil {
+		nodeScheduling.PodAffinity = getReqPodAffinity(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	}
+	if affinity.PodAntiAffinity != nil {
+		nodeScheduling.PodAntiAffinity = getReqPodAffinity(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	}
+
 	if podK8s.Spec.NodeSelector != nil {
 		nodeScheduling.Type = scheduling_nodeselector
 		labels := make([]types.ListMapItem, 0)
@@ -56,18 +82,12 @@ func getNodeReqScheduling(podK8s corev1.Pod) types.NodeScheduling {
 		nodeScheduling.NodeSelector = labels
 		return nodeScheduling
 	}
-	if podK8s.Spec.Affinity != nil {
+	// 只支持第一个 nodeSelectorTerm
+	if affinity.NodeAffinity != nil && affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+		len(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) > 0 {
 		nodeScheduling.Type = scheduling_nodeaffinity
-		term := podK8s.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0]
-		matchExpressions := make([]types.NodeSelectorTermExpressions, 0)
-		for _, expression := range term.MatchExpressions {
-			matchExpressions = append(matchExpressions, types.NodeSelectorTermExpressions{
-				Key:      expression.Key,
-				Value:    strings.Join(expression.Values, ","),
-				Operator: expression.Operator,
-			})
-		}
-		nodeScheduling.NodeAffinity = matchExpressions
+		term := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0]
+		nodeScheduling.NodeAffinity = getReqNodeSelectorExpressions(term.MatchExpressions)
 		return nodeScheduling
 	}
 	if podK8s.Spec.NodeName != "" {
@@ -78,17 +98,97 @@ func getNodeReqScheduling(podK8s corev1.Pod) types.NodeScheduling {
 	return nodeScheduling
 }
 
+func getReqNodeSelectorExpressions(expressions []corev1.NodeSelectorRequirement) []types.NodeSelectorTermExpressions {
+	matchExpressions := make([]types.NodeSelectorTermExpressions, 0)
+	for _, expression := range expressions {
+		matchExpressions = append(matchExpressions, types.NodeSelectorTermExpressions{
+			Key:      expression.Key,
+			Value:    strings.Join(expression.Values, ","),
+			Operator: expression.Operator,
+		})
+	}
+	return matchExpressions
+}
+
+func getReqLabelSelector(selector *metav1.LabelSelector) types.LabelSelector {
+	selectorReq := types.LabelSelector{}
+	if selector == nil {
+		return selectorReq
+	}
+	if len(selector.MatchLabels) > 0 {
+		selectorReq.MatchLabels = getReqLabels(selector.MatchLabels)
+	}
+	for _, expression := range selector.MatchExpressions {
+		selectorReq.MatchExpressions = append(selectorReq.MatchExpressions, types.LabelSelectorExpression{
+			Key:      expression.Key,
+			Operator: expression.Operator,
+			Value:    strings.Join(expression.Values, ","),
+		})
+	}
+	return selectorReq
+}
+
+func getReqPodAffinityTerm(term corev1.PodAffinityTerm) types.PodAffinityTerm {
+	return types.PodAffinityTerm{
+		LabelSelector: getReqLabelSelector(term.LabelSelector),
+		Namespaces:    term.Namespaces,
+		TopologyKey:   term.TopologyKey,
+	}
+}
+
+func getReqPodAffinity(required []corev1.PodAffinityTerm, preferred []corev1.WeightedPodAffinityTerm) types.PodAffinity {
+	podAffinity := types.PodAffinity{}
+	for _, term := range required {
+		podAffinity.Required = append(podAffinity.Required, getReqPodAffinityTerm(term))
+	}
+	for _, term := range preferred {
+		podAffinity.Preferred = append(podAffinity.Preferred, types.WeightedPodAffinityTerm{
+			Weight:          term.Weight,
+			PodAffinityTerm: getReqPodAffinityTerm(term.PodAffinityTerm),
+		})
+	}
+	return podAffinity
+}
+
+func getReqTopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint) []types.TopologySpreadConstraint {
+	if len(constraints) == 0 {
+		return nil
+	}
+	constraintsReq := make([]types.TopologySpreadConstraint, 0, len(constraints))
+	for _, constraint := range constraints {
+		constraintsReq = append(constraintsReq, types.TopologySpreadConstraint{
+			MaxSkew:           constraint.MaxSkew,
+			TopologyKey:       constraint.TopologyKey,
+			WhenUnsatisfiable: constraint.WhenUnsatisfiable,
+			LabelSelector:     getReqLabelSelector(constraint.LabelSelector),
+			MinDomains:        constraint.MinDomains,
+		})
+	}
+	return constraintsReq
+}
+
 func (k *K8s2ReqConvert) PodK8s2Req(podK8s corev1.Pod) types.Pod {
 	return types.Pod{
-		Base:           getReqBase(podK8s),
-		Tolerations:    podK8s.Spec.Tolerations,
-		NodeScheduling: getNodeReqScheduling(podK8s),
-		NetWorking:     getReqNetworking(podK8s),
-		Volumes:        k.getReqVolumes(podK8s.Spec.Volumes),
-		Containers:     k.getReqContainers(podK8s.Spec.Containers),
-		InitContainers: k.getReqContainers(podK8s.Spec.InitContainers),
+		Base:                  getReqBase(podK8s),
+		ActiveDeadlineSeconds: podK8s.Spec.ActiveDeadlineSeconds,
+		Tolerations:           podK8s.Spec.Tolerations,
+		NodeScheduling:        getNodeReqScheduling(podK8s),
+		NetWorking:            getReqNetworking(podK8s),
+		Volumes:               k.getReqVolumes(podK8s.Spec.Volumes),
+		Containers:            k.getReqContainers(podK8s.Spec.Containers),
+		InitContainers:        k.getReqContainers(podK8s.Spec.InitContainers),
+		SecurityContext:       getReqPodSecurityContext(podK8s.Spec.SecurityContext),
+		ImagePullSecrets:      getReqImagePullSecrets(podK8s.Spec.ImagePullSecrets),
 	}
 }
+
+func getReqImagePullSecrets(refs []corev1.LocalObjectReference) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
 func (k *K8s2ReqConvert) getReqContainers(containersK8s []corev1.Container) []types.Container {
 	podReqContainers := make([]types.Container, 0)
 	for _, item := range containersK8s {
@@ -108,15 +208,16 @@ func (k *K8s2ReqConvert) getReqContainer(container corev1.Container) types.Conta
 		Command:         container.Command,
 		Args:            container.Args,
 		////
-		Ports:          getReqContainerPorts(container.Ports),
-		Envs:           getReqContainersEnvs(container.Env),
-		EnvsFrom:       getReqContainersEnvsFrom(container.EnvFrom),
-		Privileged:     getReqContainerPrivileged(container.SecurityContext),
-		Resources:      getReqContainerResources(container.Resources),
-		VolumeMounts:   k.getReqContainerVolumeMounts(container.VolumeMounts),
-		StartupProbe:   getReqContainerProbe(container.StartupProbe),
-		LivenessProbe:  getReqContainerProbe(container.LivenessProbe),
-		ReadinessProbe: getReqContainerProbe(container.ReadinessProbe),
+		Ports:           getReqContainerPorts(container.Ports),
+		Envs:            getReqContainersEnvs(container.Env),
+		EnvsFrom:        getReqContainersEnvsFrom(container.EnvFrom),
+		Privileged:      getReqContainerPrivileged(container.SecurityContext),
+		SecurityContext: getReqContainerSecurityContext(container.SecurityContext),
+		Resources:       getReqContainerResources(container.Resources),
+		VolumeMounts:    k.getReqContainerVolumeMounts(container.VolumeMounts),
+		StartupProbe:    getReqContainerProbe(container.StartupProbe),
+		LivenessProbe:   getReqContainerProbe(container.LivenessProbe),
+		ReadinessProbe:  getReqContainerProbe(container.ReadinessProbe),
 	}
 }
 func getReqContainerProbe(probeK8s *corev1.Probe) types.ContainerProbe {
@@ -173,9 +274,11 @@ func (k *K8s2ReqConvert) getReqContainerVolumeMounts(volumeMountsK8s []corev1.Vo
 		_, ok := k.volumeMap[item.Name]
 		if ok {
 			volumesReq = append(volumesReq, types.VolumeMount{
-				MountName: item.Name,
-				MountPath: item.MountPath,
-				ReadOnly:  item.ReadOnly,
+				MountName:   item.Name,
+				MountPath:   item.MountPath,
+				ReadOnly:    item.ReadOnly,
+				SubPath:     item.SubPath,
+				SubPathExpr: item.SubPathExpr,
 			})
 		}
 	}
@@ -203,11 +306,84 @@ func getReqContainerResources(requirements corev1.ResourceRequirements) types.Re
 }
 
 func getReqContainerPrivileged(ctx *corev1.SecurityContext) (privileged bool) {
-	if ctx != nil {
+	if ctx != nil && ctx.Privileged != nil {
 		privileged = *ctx.Privileged
 	}
 	return
 }
+
+// getReqPodSecurityContext apiserver 会把未设置的 securityContext 填充为空对象, 此时返回nil
+func getReqPodSecurityContext(ctx *corev1.PodSecurityContext) *types.PodSecurityContext {
+	if ctx == nil {
+		return nil
+	}
+	reqCtx := &types.PodSecurityContext{
+		RunAsUser:       ctx.RunAsUser,
+		RunAsGroup:      ctx.RunAsGroup,
+		RunAsNonRoot:    ctx.RunAsNonRoot,
+		FSGroup:         ctx.FSGroup,
+		SeccompProfile:  getReqSeccompProfile(ctx.SeccompProfile),
+		AppArmorProfile: getReqAppArmorProfile(ctx.AppArmorProfile),
+	}
+	if *reqCtx == (types.PodSecurityContext{}) {
+		return nil
+	}
+	return reqCtx
+}
+
+// getReqContainerSecurityContext 特权模式单独返回, 其余字段都未设置时返回nil
+func getReqContainerSecurityContext(ctx *corev1.SecurityContext) *types.ContainerSecurityContext {
+	if ctx == nil {
+		return nil
+	}
+	reqCtx := &types.ContainerSecurityContext{
+		RunAsUser:                ctx.RunAsUser,
+		RunAsGroup:               ctx.RunAsGroup,
+		RunAsNonRoot:             ctx.RunAsNonRoot,
+		ReadOnlyRootFilesystem:   ctx.ReadOnlyRootFilesystem,
+		AllowPrivilegeEscalation: ctx.AllowPrivilegeEscalation,
+		SeccompProfile:           getReqSeccompProfile(ctx.SeccompProfile),
+		AppArmorProfile:          getReqAppArmorProfile(ctx.AppArmorProfile),
+	}
+	if ctx.Capabilities != nil {
+		reqCtx.Capabilities = &types.Capabilities{
+			Add:  make([]string, 0, len(ctx.Capabilities.Add)),
+			Drop: make([]string, 0, len(ctx.Capabilities.Drop)),
+		}
+		for _, capability := range ctx.Capabilities.Add {
+			reqCtx.Capabilities.Add = append(reqCtx.Capabilities.Add, string(capability))
+		}
+		for _, capability := range ctx.Capabilities.Drop {
+			reqCtx.Capabilities.Drop = append(reqCtx.Capabilities.Drop, string(capability))
+		}
+	}
+	if *reqCtx == (types.ContainerSecurityContext{}) {
+		return nil
+	}
+	return reqCtx
+}
+
+func getReqSeccompProfile(profile *corev1.SeccompProfile) *types.SeccompProfile {
+	if profile == nil {
+		return nil
+	}
+	reqProfile := &types.SeccompProfile{Type: string(profile.Type)}
+	if profile.LocalhostProfile != nil {
+		reqProfile.LocalhostProfile = *profile.LocalhostProfile
+	}
+	return reqProfile
+}
+
+func getReqAppArmorProfile(profile *corev1.AppArmorProfile) *types.AppArmorProfile {
+	if profile == nil {
+		return nil
+	}
+	reqProfile := &types.AppArmorProfile{Type: string(profile.Type)}
+	if profile.LocalhostProfile != nil {
+		reqProfile.LocalhostProfile = *profile.LocalhostProfile
+	}
+	return reqProfile
+}
 func getReqContainersEnvsFrom(envsFromK8s []corev1.EnvFromSource) []types.EnvVarFromResource {
 	podReqEnvsFromList := make([]types.EnvVarFromResource, 0)
 	for _, envK8sItem := range envsFromK8s {
@@ -270,94 +446,190 @@ func (this *K8s2ReqConvert) getReqVolumes(volumes []corev1.Volume) []types.Volum
 		this.volumeMap = make(map[string]string)
 	}
 	for _, volume := range volumes {
-		//if volume.EmptyDir == nil {
-		//	continue
-		//}
-		var volumeReq *types.Volume
-		if volume.EmptyDir != nil {
-			volumeReq = &types.Volume{
-				Type: volume_emptyDir,
-				Name: volume.Name,
-			}
+		// 准入控制自动注入的serviceAccount token卷, 提交时会重新注入
+		if volume.Projected != nil && strings.HasPrefix(volume.Name, serviceAccountVolumePrefix) {
+			continue
 		}
-
-		if volume.ConfigMap != nil {
-			var optional bool
-			if volume.ConfigMap.Optional != nil {
-				optional = *volume.ConfigMap.Optional
+		volumeReq := types.Volume{Name: volume.Name}
+		switch {
+		case volume.EmptyDir != nil:
+			volumeReq.Type = volume_emptyDir
+			volumeReq.EmptyDirVolume = types.EmptyDirVolume{
+				Medium: volume.EmptyDir.Medium,
 			}
-			volumeReq = &types.Volume{
-				Type: volume_configMap,
-				Name: volume.Name,
-				ConfigMapRefVolume: types.ConfigMapRefVolume{
-					Name:     volume.ConfigMap.Name,
-					Optional: optional,
-				},
+			if volume.EmptyDir.SizeLimit != nil {
+				volumeReq.EmptyDirVolume.SizeLimit = volume.EmptyDir.SizeLimit.String()
 			}
-		}
-
-		if volume.Secret != nil {
-			var optional bool
-			if volume.Secret.Optional != nil {
-				optional = *volume.Secret.Optional
+		case volume.ConfigMap != nil:
+			volumeReq.Type = volume_configMap
+			volumeReq.ConfigMapRefVolume = types.ConfigMapRefVolume{
+				Name:        volume.ConfigMap.Name,
+				Optional:    ptr.Deref(volume.ConfigMap.Optional, false),
+				Items:       getReqKeyToPaths(volume.ConfigMap.Items),
+				DefaultMode: getReqDefaultMode(volume.ConfigMap.DefaultMode),
 			}
-			volumeReq = &types.Volume{
-				Type: volume_secret,
-				Name: volume.Name,
-				SecretRefVolume: types.SecretRefVolume{
-					Name:     volume.Secret.SecretName,
-					Optional: optional,
-				},
+		case volume.Secret != nil:
+			volumeReq.Type = volume_secret
+			volumeReq.SecretRefVolume = types.SecretRefVolume{
+				Name:        volume.Secret.SecretName,
+				Optional:    ptr.Deref(volume.Secret.Optional, false),
+				Items:       getReqKeyToPaths(volume.Secret.Items),
+				DefaultMode: getReqDefaultMode(volume.Secret.DefaultMode),
 			}
-		}
-
-		if volume.HostPath != nil {
-			volumeReq = &types.Volume{
-				Type: volume_hostPath,
-				Name: volume.Name,
-				HostPathVolume: types.HostPathVolume{
-					Path: volume.HostPath.Path,
-					Type: *volume.HostPath.Type,
-				},
+		case volume.HostPath != nil:
+			volumeReq.Type = volume_hostPath
+			volumeReq.HostPathVolume = types.HostPathVolume{
+				Path: volume.HostPath.Path,
+				Type: ptr.Deref(volume.HostPath.Type, corev1.HostPathUnset),
 			}
-		}
-
-		if volume.PersistentVolumeClaim != nil {
-			volumeReq = &types.Volume{
-				Type: volume_pvc,
-				Name: volume.Name,
-				PVCVolume: types.PVCVolume{
-					Name: volume.PersistentVolumeClaim.ClaimName,
-				},
+		case volume.PersistentVolumeClaim != nil:
+			volumeReq.Type = volume_pvc
+			volumeReq.PVCVolume = types.PVCVolume{
+				Name: volume.PersistentVolumeClaim.ClaimName,
 			}
-		}
-
-		if volume.DownwardAPI != nil {
-			items := make([]types.DownwardAPIVolumeItem, 0)
-			for _, item := range volume.DownwardAPI.Items {
-				items = append(items, types.DownwardAPIVolumeItem{
-					Path:         item.Path,
-					FieldRefPath: item.FieldRef.FieldPath,
-				})
+		case volume.DownwardAPI != nil:
+			volumeReq.Type = volume_downward
+			volumeReq.DownwardAPIVolume = types.DownwardAPIVolume{
+				Items: getReqDownwardAPIItems(volume.DownwardAPI.Items),
 			}
-			volumeReq = &types.Volume{
-				Type: volume_downward,
-				Name: volume.Name,
-				DownwardAPIVolume: types.DownwardAPIVolume{
-					Items: items,
-				},
+		case volume.Projected != nil:
+			volumeReq.Type = volume_projected
+			volumeReq.ProjectedVolume = types.ProjectedVolume{
+				Sources:     getReqProjections(volume.Projected.Sources),
+				DefaultMode: getReqDefaultMode(volume.Projected.DefaultMode),
 			}
-		}
-
-		if volumeReq == nil {
+		case volume.NFS != nil:
+			volumeReq.Type = volume_nfs
+			volumeReq.NFSVolume = types.NFSVolume{
+				Server:   volume.NFS.Server,
+				Path:     volume.NFS.Path,
+				ReadOnly: volume.NFS.ReadOnly,
+			}
+		case volume.CSI != nil:
+			volumeReq.Type = volume_csi
+			volumeReq.CSIVolume = getReqCSIVolume(volume.CSI)
+		case volume.Ephemeral != nil && volume.Ephemeral.VolumeClaimTemplate != nil:
+			volumeReq.Type = volume_ephemeral
+			volumeReq.EphemeralVolume = getReqEphemeralVolume(volume.Ephemeral.VolumeClaimTemplate)
+		default:
 			continue
 		}
 		this.volumeMap[volume.Name] = ""
-		volumesReq = append(volumesReq, *volumeReq)
+		volumesReq = append(volumesReq, volumeReq)
 	}
 	return volumesReq
 }
 
+// getReqDefaultMode apiserver 默认填充的 0644 不返回, 与请求时不传一致
+func getReqDefaultMode(mode *int32) *int32 {
+	if mode == nil || *mode == corev1.ConfigMapVolumeSourceDefaultMode {
+		return nil
+	}
+	return mode
+}
+
+func getReqKeyToPaths(items []corev1.KeyToPath) []types.KeyToPath {
+	if len(items) == 0 {
+		return nil
+	}
+	reqItems := make([]types.KeyToPath, 0, len(items))
+	for _, item := range items {
+		reqItems = append(reqItems, types.KeyToPath{
+			Key:  item.Key,
+			Path: item.Path,
+			Mode: item.Mode,
+		})
+	}
+	return reqItems
+}
+
+// getReqDownwardAPIItems 只支持 fieldRef, resourceFieldRef 的项忽略
+func getReqDownwardAPIItems(items []corev1.DownwardAPIVolumeFile) []types.DownwardAPIVolumeItem {
+	reqItems := make([]types.DownwardAPIVolumeItem, 0)
+	for _, item := range items {
+		if item.FieldRef == nil {
+			continue
+		}
+		reqItems = append(reqItems, types.DownwardAPIVolumeItem{
+			Path:         item.Path,
+			FieldRefPath: item.FieldRef.FieldPath,
+		})
+	}
+	return reqItems
+}
+
+func getReqProjections(projections []corev1.VolumeProjection) []types.ProjectedVolumeSource {
+	sources := make([]types.ProjectedVolumeSource, 0)
+	for _, projection := range projections {
+		switch {
+		case projection.ConfigMap != nil:
+			sources = append(sources, types.ProjectedVolumeSource{
+				Type: projection_configMap,
+				ConfigMap: types.ConfigMapRefVolume{
+					Name:     projection.ConfigMap.Name,
+					Optional: ptr.Deref(projection.ConfigMap.Optional, false),
+					Items:    getReqKeyToPaths(projection.ConfigMap.Items),
+				},
+			})
+		case projection.Secret != nil:
+			sources = append(sources, types.ProjectedVolumeSource{
+				Type: projection_secret,
+				Secret: types.SecretRefVolume{
+					Name:     projection.Secret.Name,
+					Optional: ptr.Deref(projection.Secret.Optional, false),
+					Items:    getReqKeyToPaths(projection.Secret.Items),
+				},
+			})
+		case projection.DownwardAPI != nil:
+			sources = append(sources, types.ProjectedVolumeSource{
+				Type: projection_downward,
+				DownwardAPI: types.DownwardAPIVolume{
+					Items: getReqDownwardAPIItems(projection.DownwardAPI.Items),
+				},
+			})
+		case projection.ServiceAccountToken != nil:
+			sources = append(sources, types.ProjectedVolumeSource{
+				Type: projection_serviceAccountToken,
+				ServiceAccountToken: types.ServiceAccountTokenProjection{
+					Audience:          projection.ServiceAccountToken.Audience,
+					ExpirationSeconds: projection.ServiceAccountToken.ExpirationSeconds,
+					Path:              projection.ServiceAccountToken.Path,
+				},
+			})
+		}
+	}
+	return sources
+}
+
+func getReqCSIVolume(csi *corev1.CSIVolumeSource) types.CSIVolume {
+	csiReq := types.CSIVolume{
+		Driver:   csi.Driver,
+		ReadOnly: ptr.Deref(csi.ReadOnly, false),
+		FSType:   ptr.Deref(csi.FSType, ""),
+	}
+	if len(csi.VolumeAttributes) > 0 {
+		csiReq.VolumeAttributes = getReqLabels(csi.VolumeAttributes)
+	}
+	if csi.NodePublishSecretRef != nil {
+		csiReq.NodePublishSecretRef = csi.NodePublishSecretRef.Name
+	}
+	return csiReq
+}
+
+func getReqEphemeralVolume(template *corev1.PersistentVolumeClaimTemplate) types.EphemeralVolume {
+	ephemeralReq := types.EphemeralVolume{
+		AccessModes:      template.Spec.AccessModes,
+		StorageClassName: ptr.Deref(template.Spec.StorageClassName, ""),
+	}
+	if len(template.Labels) > 0 {
+		ephemeralReq.Labels = getReqLabels(template.Labels)
+	}
+	if storage, ok := template.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		ephemeralReq.Storage = storage.String()
+	}
+	return ephemeralReq
+}
+
 func getReqHostAliases(hostAlias []corev1.HostAlias) []types.ListMapItem {
 	hostAliasReq := make([]types.ListMapItem, 0)
 	for _, alias := range hostAlias {
@@ -405,6 +677,7 @@ func getReqBase(pod corev1.Pod) types.Base {
 		Name:          pod.Name,
 		Namespace:     pod.Namespace,
 		Labels:        getReqLabels(pod.Labels),
+		Annotations:   getReqLabels(pod.Annotations),
 		RestartPolicy: string(pod.Spec.RestartPolicy),
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"strconv"
 	"strings"
 )
@@ -23,6 +24,18 @@ const (
 	volume_hostPath  = "hostPath"
 	volume_downward  = "downwardAPI"
 	volume_pvc       = "pvc"
+	volume_projected = "projected"
+	volume_nfs       = "nfs"
+	volume_csi       = "csi"
+	volume_ephemeral = "ephemeral"
+)
+
+// projected 卷的来源类型
+const (
+	projection_configMap           = "configMap"
+	projection_secret              = "secret"
+	projection_downward            = "downwardAPI"
+	projection_serviceAccountToken = "serviceAccountToken"
 )
 
 const (
@@ -43,34 +56,22 @@ type Req2K8sConvert struct {
 
 func getNodeK8sScheduling(podReq *types.Pod) (affinity *corev1.Affinity, nodeSelector map[string]string, nodeName string) {
 	nodeScheduling := podReq.NodeScheduling
+	affinity = &corev1.Affinity{}
 	switch nodeScheduling.Type {
 	case scheduling_nodename:
 		nodeName = nodeScheduling.NodeName
-		return
 	case scheduling_nodeselector:
 		nodeSelectorMap := make(map[string]string)
 		for _, item := range nodeScheduling.NodeSelector {
 			nodeSelectorMap[item.Key] = item.Value
 		}
 		nodeSelector = nodeSelectorMap
-		return
 	case scheduling_nodeaffinity:
-		nodeSelectorTermExpressions := nodeScheduling.NodeAffinity
-		matchExpression := make([]corev1.NodeSelectorRequirement, 0)
-		for _, expression := range nodeSelectorTermExpressions {
-			matchExpression = append(matchExpression, corev1.NodeSelectorRequirement{
-				Key:      expression.Key,
-				Values:   strings.Split(expression.Value, ","),
-				Operator: expression.Operator,
-			})
-		}
-		affinity = &corev1.Affinity{
-			NodeAffinity: &corev1.NodeAffinity{
-				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
-					NodeSelectorTerms: []corev1.NodeSelectorTerm{
-						{
-							MatchExpressions: matchExpression,
-						},
+		affinity.NodeAffinity = &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: getK8sNodeSelectorRequirements(nodeScheduling.NodeAffinity),
 					},
 				},
 			},
@@ -80,37 +81,172 @@ func getNodeK8sScheduling(podReq *types.Pod) (affinity *corev1.Affinity, nodeSel
 	default:
 		//do nothing
 	}
+
+	if len(nodeScheduling.PreferredNodeAffinity) > 0 {
+		if affinity.NodeAffinity == nil {
+			affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		preferred := make([]corev1.PreferredSchedulingTerm, 0, len(nodeScheduling.PreferredNodeAffinity))
+		for _, item := range nodeScheduling.PreferredNodeAffinity {
+			preferred = append(preferred, corev1.PreferredSchedulingTerm{
+				Weight: item.Weight,
+				Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: getK8sNodeSelectorRequirements(item.MatchExpressions),
+				},
+			})
+		}
+		affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = preferred
+	}
+	if required, preferred := getK8sPodAffinityTerms(nodeScheduling.PodAffinity); required != nil || preferred != nil {
+		affinity.PodAffinity = &corev1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution:  required,
+			PreferredDuringSchedulingIgnoredDuringExecution: preferred,
+		}
+	}
+	if required, preferred := getK8sPodAffinityTerms(nodeScheduling.PodAntiAffinity); required != nil || preferred != nil {
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution:  required,
+			PreferredDuringSchedulingIgnoredDuringExecution: preferred,
+		}
+	}
+	if *affinity == (corev1.Affinity{}) {
+		affinity = nil
+	}
+	return
+}
+
+// splitValues 多个值用逗号分隔, Exists/DoesNotExist 没有值
+func splitValues(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func getK8sNodeSelectorRequirements(expressions []types.NodeSelectorTermExpressions) []corev1.NodeSelectorRequirement {
+	matchExpression := make([]corev1.NodeSelectorRequirement, 0)
+	for _, expression := range expressions {
+		matchExpression = append(matchExpression, corev1.NodeSelectorRequirement{
+			Key:      expression.Key,
+			Values:   splitValues(expression.Value),
+			Operator: expression.Operator,
+		})
+	}
+	return matchExpression
+}
+
+// getK8sLabelSelector 没有条件时返回nil
+func getK8sLabelSelector(selector types.LabelSelector) *metav1.LabelSelector {
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return nil
+	}
+	k8sSelector := &metav1.LabelSelector{}
+	if len(selector.MatchLabels) > 0 {
+		k8sSelector.MatchLabels = make(map[string]string, len(selector.MatchLabels))
+		for _, item := range selector.MatchLabels {
+			k8sSelector.MatchLabels[item.Key] = item.Value
+		}
+	}
+	for _, expression := range selector.MatchExpressions {
+		k8sSelector.MatchExpressions = append(k8sSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      expression.Key,
+			Operator: expression.Operator,
+			Values:   splitValues(expression.Value),
+		})
+	}
+	return k8sSelector
+}
+
+func getK8sPodAffinityTerm(term types.PodAffinityTerm) corev1.PodAffinityTerm {
+	return corev1.PodAffinityTerm{
+		LabelSelector: getK8sLabelSelector(term.LabelSelector),
+		Namespaces:    term.Namespaces,
+		TopologyKey:   term.TopologyKey,
+	}
+}
+
+// getK8sPodAffinityTerms podAffinity 和 podAntiAffinity 的结构相同, 没有配置时返回nil
+func getK8sPodAffinityTerms(podAffinity types.PodAffinity) (required []corev1.PodAffinityTerm, preferred []corev1.WeightedPodAffinityTerm) {
+	for _, term := range podAffinity.Required {
+		required = append(required, getK8sPodAffinityTerm(term))
+	}
+	for _, term := range podAffinity.Preferred {
+		preferred = append(preferred, corev1.WeightedPodAffinityTerm{
+			Weight:          term.Weight,
+			PodAffinityTerm: getK8sPodAffinityTerm(term.PodAffinityTerm),
+		})
+	}
 	return
 }
 
+func getK8sTopologySpreadConstraints(constraints []types.TopologySpreadConstraint) []corev1.TopologySpreadConstraint {
+	if len(constraints) == 0 {
+		return nil
+	}
+	k8sConstraints := make([]corev1.TopologySpreadConstraint, 0, len(constraints))
+	for _, constraint := range constraints {
+		k8sConstraints = append(k8sConstraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           constraint.MaxSkew,
+			TopologyKey:       constraint.TopologyKey,
+			WhenUnsatisfiable: constraint.WhenUnsatisfiable,
+			LabelSelector:     getK8sLabelSelector(constraint.LabelSelector),
+			MinDomains:        constraint.MinDomains,
+		})
+	}
+	return k8sConstraints
+}
+
 // 将pod 的 请求格式的数据 转换为 k8s 结构的数据
 func (pc *Req2K8sConvert) PodReq2K8s(podReq *types.Pod) *corev1.Pod {
 	nodeAffinity, nodeSelector, nodeName := getNodeK8sScheduling(podReq)
+	var annotations map[string]string
+	if podReq.Base.Annotations != nil {
+		annotations = pc.getK8sLabels(podReq.Base.Annotations)
+	}
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      podReq.Base.Name,
-			Namespace: podReq.Base.Namespace,
-			Labels:    pc.getK8sLabels(podReq.Base.Labels),
+			Name:        podReq.Base.Name,
+			Namespace:   podReq.Base.Namespace,
+			Labels:      pc.getK8sLabels(podReq.Base.Labels),
+			Annotations: annotations,
 		},
 		Spec: corev1.PodSpec{
-			NodeName:       nodeName,
-			NodeSelector:   nodeSelector,
-			Affinity:       nodeAffinity,
-			Tolerations:    podReq.Tolerations,
-			InitContainers: pc.getK8sContainers(podReq.InitContainers),
-			Containers:     pc.getK8sContainers(podReq.Containers),
-			Volumes:        pc.getK8sVolumes(podReq.Volumes),
+			ActiveDeadlineSeconds:     podReq.ActiveDeadlineSeconds,
+			NodeName:                  nodeName,
+			NodeSelector:              nodeSelector,
+			Affinity:                  nodeAffinity,
+			Tolerations:               podReq.Tolerations,
+			TopologySpreadConstraints: getK8sTopologySpreadConstraints(podReq.NodeScheduling.TopologySpreadConstraints),
+			PriorityClassName:         podReq.NodeScheduling.PriorityClassName,
+			SchedulerName:             podReq.NodeScheduling.SchedulerName,
+			InitContainers:            pc.getK8sContainers(podReq.InitContainers),
+			Containers:                pc.getK8sContainers(podReq.Containers),
+			Volumes:                   pc.getK8sVolumes(podReq.Volumes),
 			DNSConfig: &corev1.PodDNSConfig{
 				Nameservers: podReq.NetWorking.DnsConfig.Nameservers,
 			},
-			DNSPolicy:     corev1.DNSPolicy(podReq.NetWorking.DnsPolicy),
-			HostAliases:   pc.getK8sHostAlias(podReq.NetWorking.HostAliases),
-			Hostname:      podReq.NetWorking.HostName,
-			RestartPolicy: corev1.RestartPolicy(podReq.Base.RestartPolicy),
+			DNSPolicy:        corev1.DNSPolicy(podReq.NetWorking.DnsPolicy),
+			HostAliases:      pc.getK8sHostAlias(podReq.NetWorking.HostAliases),
+			Hostname:         podReq.NetWorking.HostName,
+			RestartPolicy:    corev1.RestartPolicy(podReq.Base.RestartPolicy),
+			SecurityContext:  pc.getK8sPodSecurityContext(podReq.SecurityContext),
+			ImagePullSecrets: pc.getK8sImagePullSecrets(podReq.ImagePullSecrets),
 		},
 	}
 }
 
+// getK8sImagePullSecrets 请求中为nil时返回nil, 由调用方决定默认值
+func (pc *Req2K8sConvert) getK8sImagePullSecrets(names []string) []corev1.LocalObjectReference {
+	if names == nil {
+		return nil
+	}
+	refs := make([]corev1.LocalObjectReference, 0, len(names))
+	for _, name := range names {
+		refs = append(refs, corev1.LocalObjectReference{Name: name})
+	}
+	return refs
+}
+
 func (pc *Req2K8sConvert) getK8sHostAlias(podReqHostAliases []types.ListMapItem) []corev1.HostAlias {
 	podK8sHostAliases := make([]corev1.HostAlias, 0)
 	for _, item := range podReqHostAliases {
@@ -125,14 +261,14 @@ func (pc *Req2K8sConvert) getK8sHostAlias(podReqHostAliases []types.ListMapItem)
 func (pc *Req2K8sConvert) getK8sVolumes(podReqVolumes []types.Volume) []corev1.Volume {
 	podK8sVolumes := make([]corev1.Volume, 0)
 	for _, volume := range podReqVolumes {
-		//if volume.Type != volume_emptyDir {
-		//	continue
-		//}
 		source := corev1.VolumeSource{}
 		switch volume.Type {
 		case volume_emptyDir:
 			source = corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    volume.EmptyDirVolume.Medium,
+					SizeLimit: getK8sQuantity(volume.EmptyDirVolume.SizeLimit),
+				},
 			}
 		case volume_hostPath:
 			pathType := volume.HostPathVolume.Type
@@ -143,37 +279,29 @@ func (pc *Req2K8sConvert) getK8sVolumes(podReqVolumes []types.Volume) []corev1.V
 				},
 			}
 		case volume_configMap:
-			optional := volume.ConfigMapRefVolume.Optional
 			source = corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
 						Name: volume.ConfigMapRefVolume.Name,
 					},
-					Optional: &optional,
+					Items:       getK8sKeyToPaths(volume.ConfigMapRefVolume.Items),
+					DefaultMode: volume.ConfigMapRefVolume.DefaultMode,
+					Optional:    ptr.To(volume.ConfigMapRefVolume.Optional),
 				},
 			}
 		case volume_secret:
-			optional := volume.SecretRefVolume.Optional
 			source = corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: volume.SecretRefVolume.Name,
-					Optional:   &optional,
+					SecretName:  volume.SecretRefVolume.Name,
+					Items:       getK8sKeyToPaths(volume.SecretRefVolume.Items),
+					DefaultMode: volume.SecretRefVolume.DefaultMode,
+					Optional:    ptr.To(volume.SecretRefVolume.Optional),
 				},
 			}
 		case volume_downward:
-			items := make([]corev1.DownwardAPIVolumeFile, 0)
-			for _, item := range volume.DownwardAPIVolume.Items {
-				items = append(items, corev1.DownwardAPIVolumeFile{
-					//容器内的文件访问路径
-					Path: item.Path,
-					FieldRef: &corev1.ObjectFieldSelector{
-						FieldPath: item.FieldRefPath,
-					},
-				})
-			}
 			source = corev1.VolumeSource{
 				DownwardAPI: &corev1.DownwardAPIVolumeSource{
-					Items: items,
+					Items: getK8sDownwardAPIItems(volume.DownwardAPIVolume.Items),
 				},
 			}
 		case volume_pvc:
@@ -182,6 +310,29 @@ func (pc *Req2K8sConvert) getK8sVolumes(podReqVolumes []types.Volume) []corev1.V
 					ClaimName: volume.PVCVolume.Name,
 				},
 			}
+		case volume_projected:
+			source = corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources:     pc.getK8sProjections(volume.ProjectedVolume.Sources),
+					DefaultMode: volume.ProjectedVolume.DefaultMode,
+				},
+			}
+		case volume_nfs:
+			source = corev1.VolumeSource{
+				NFS: &corev1.NFSVolumeSource{
+					Server:   volume.NFSVolume.Server,
+					Path:     volume.NFSVolume.Path,
+					ReadOnly: volume.NFSVolume.ReadOnly,
+				},
+			}
+		case volume_csi:
+			source = corev1.VolumeSource{
+				CSI: pc.getK8sCSIVolume(volume.CSIVolume),
+			}
+		case volume_ephemeral:
+			source = corev1.VolumeSource{
+				Ephemeral: pc.getK8sEphemeralVolume(volume.EphemeralVolume),
+			}
 		default:
 			continue
 		}
@@ -193,6 +344,122 @@ func (pc *Req2K8sConvert) getK8sVolumes(podReqVolumes []types.Volume) []corev1.V
 	return podK8sVolumes
 }
 
+// getK8sQuantity 容量在 PodValidate 中已校验, 为空或无法解析时不设置
+func getK8sQuantity(value string) *resource.Quantity {
+	if value == "" {
+		return nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil
+	}
+	return &quantity
+}
+
+func getK8sKeyToPaths(items []types.KeyToPath) []corev1.KeyToPath {
+	if len(items) == 0 {
+		return nil
+	}
+	k8sItems := make([]corev1.KeyToPath, 0, len(items))
+	for _, item := range items {
+		k8sItems = append(k8sItems, corev1.KeyToPath{
+			Key:  item.Key,
+			Path: item.Path,
+			Mode: item.Mode,
+		})
+	}
+	return k8sItems
+}
+
+func getK8sDownwardAPIItems(items []types.DownwardAPIVolumeItem) []corev1.DownwardAPIVolumeFile {
+	k8sItems := make([]corev1.DownwardAPIVolumeFile, 0)
+	for _, item := range items {
+		k8sItems = append(k8sItems, corev1.DownwardAPIVolumeFile{
+			//容器内的文件访问路径
+			Path: item.Path,
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: item.FieldRefPath,
+			},
+		})
+	}
+	return k8sItems
+}
+
+func (pc *Req2K8sConvert) getK8sProjections(sources []types.ProjectedVolumeSource) []corev1.VolumeProjection {
+	projections := make([]corev1.VolumeProjection, 0)
+	for _, source := range sources {
+		switch source.Type {
+		case projection_configMap:
+			projections = append(projections, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: source.ConfigMap.Name},
+					Items:                getK8sKeyToPaths(source.ConfigMap.Items),
+					Optional:             ptr.To(source.ConfigMap.Optional),
+				},
+			})
+		case projection_secret:
+			projections = append(projections, corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: source.Secret.Name},
+					Items:                getK8sKeyToPaths(source.Secret.Items),
+					Optional:             ptr.To(source.Secret.Optional),
+				},
+			})
+		case projection_downward:
+			projections = append(projections, corev1.VolumeProjection{
+				DownwardAPI: &corev1.DownwardAPIProjection{
+					Items: getK8sDownwardAPIItems(source.DownwardAPI.Items),
+				},
+			})
+		case projection_serviceAccountToken:
+			projections = append(projections, corev1.VolumeProjection{
+				ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+					Audience:          source.ServiceAccountToken.Audience,
+					ExpirationSeconds: source.ServiceAccountToken.ExpirationSeconds,
+					Path:              source.ServiceAccountToken.Path,
+				},
+			})
+		}
+	}
+	return projections
+}
+
+func (pc *Req2K8sConvert) getK8sCSIVolume(csi types.CSIVolume) *corev1.CSIVolumeSource {
+	k8sCSI := &corev1.CSIVolumeSource{
+		Driver:   csi.Driver,
+		ReadOnly: ptr.To(csi.ReadOnly),
+	}
+	if csi.FSType != "" {
+		k8sCSI.FSType = ptr.To(csi.FSType)
+	}
+	if len(csi.VolumeAttributes) > 0 {
+		k8sCSI.VolumeAttributes = pc.getK8sLabels(csi.VolumeAttributes)
+	}
+	if csi.NodePublishSecretRef != "" {
+		k8sCSI.NodePublishSecretRef = &corev1.LocalObjectReference{Name: csi.NodePublishSecretRef}
+	}
+	return k8sCSI
+}
+
+func (pc *Req2K8sConvert) getK8sEphemeralVolume(ephemeral types.EphemeralVolume) *corev1.EphemeralVolumeSource {
+	spec := corev1.PersistentVolumeClaimSpec{
+		AccessModes: ephemeral.AccessModes,
+	}
+	if ephemeral.StorageClassName != "" {
+		spec.StorageClassName = ptr.To(ephemeral.StorageClassName)
+	}
+	if storage := getK8sQuantity(ephemeral.Storage); storage != nil {
+		spec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: *storage,
+		}
+	}
+	template := &corev1.PersistentVolumeClaimTemplate{Spec: spec}
+	if len(ephemeral.Labels) > 0 {
+		template.Labels = pc.getK8sLabels(ephemeral.Labels)
+	}
+	return &corev1.EphemeralVolumeSource{VolumeClaimTemplate: template}
+}
+
 func (pc *Req2K8sConvert) getK8sContainers(podReqContainers []types.Container) []corev1.Container {
 	podK8sContainers := make([]corev1.Container, 0)
 	for _, item := range podReqContainers {
@@ -210,19 +477,80 @@ func (pc *Req2K8sConvert) getK8sContainer(podReqContainer types.Container) corev
 		Command:         podReqContainer.Command,
 		Args:            podReqContainer.Args,
 		WorkingDir:      podReqContainer.WorkingDir,
-		SecurityContext: &corev1.SecurityContext{
-			Privileged: &podReqContainer.Privileged,
-		},
-		Ports:          pc.getK8sPorts(podReqContainer.Ports),
-		Env:            pc.getK8sEnv(podReqContainer.Envs),
-		EnvFrom:        pc.getK8sEnvFrom(podReqContainer.EnvsFrom),
-		VolumeMounts:   pc.getK8sVolumeMounts(podReqContainer.VolumeMounts),
-		StartupProbe:   pc.getK8sContainerProbe(podReqContainer.StartupProbe),
-		LivenessProbe:  pc.getK8sContainerProbe(podReqContainer.LivenessProbe),
-		ReadinessProbe: pc.getK8sContainerProbe(podReqContainer.ReadinessProbe),
-		Resources:      pc.getK8sResources(podReqContainer.Resources),
+		SecurityContext: pc.getK8sContainerSecurityContext(podReqContainer.Privileged, podReqContainer.SecurityContext),
+		Ports:           pc.getK8sPorts(podReqContainer.Ports),
+		Env:             pc.getK8sEnv(podReqContainer.Envs),
+		EnvFrom:         pc.getK8sEnvFrom(podReqContainer.EnvsFrom),
+		VolumeMounts:    pc.getK8sVolumeMounts(podReqContainer.VolumeMounts),
+		StartupProbe:    pc.getK8sContainerProbe(podReqContainer.StartupProbe),
+		LivenessProbe:   pc.getK8sContainerProbe(podReqContainer.LivenessProbe),
+		ReadinessProbe:  pc.getK8sContainerProbe(podReqContainer.ReadinessProbe),
+		Resources:       pc.getK8sResources(podReqContainer.Resources),
+	}
+}
+func (pc *Req2K8sConvert) getK8sPodSecurityContext(podReqCtx *types.PodSecurityContext) *corev1.PodSecurityContext {
+	if podReqCtx == nil {
+		return nil
+	}
+	return &corev1.PodSecurityContext{
+		RunAsUser:       podReqCtx.RunAsUser,
+		RunAsGroup:      podReqCtx.RunAsGroup,
+		RunAsNonRoot:    podReqCtx.RunAsNonRoot,
+		FSGroup:         podReqCtx.FSGroup,
+		SeccompProfile:  getK8sSeccompProfile(podReqCtx.SeccompProfile),
+		AppArmorProfile: getK8sAppArmorProfile(podReqCtx.AppArmorProfile),
+	}
+}
+
+func (pc *Req2K8sConvert) getK8sContainerSecurityContext(privileged bool, podReqCtx *types.ContainerSecurityContext) *corev1.SecurityContext {
+	k8sCtx := &corev1.SecurityContext{
+		Privileged: &privileged,
+	}
+	if podReqCtx == nil {
+		return k8sCtx
+	}
+	k8sCtx.RunAsUser = podReqCtx.RunAsUser
+	k8sCtx.RunAsGroup = podReqCtx.RunAsGroup
+	k8sCtx.RunAsNonRoot = podReqCtx.RunAsNonRoot
+	k8sCtx.ReadOnlyRootFilesystem = podReqCtx.ReadOnlyRootFilesystem
+	k8sCtx.AllowPrivilegeEscalation = podReqCtx.AllowPrivilegeEscalation
+	k8sCtx.SeccompProfile = getK8sSeccompProfile(podReqCtx.SeccompProfile)
+	k8sCtx.AppArmorProfile = getK8sAppArmorProfile(podReqCtx.AppArmorProfile)
+	if podReqCtx.Capabilities != nil {
+		k8sCtx.Capabilities = &corev1.Capabilities{}
+		for _, capability := range podReqCtx.Capabilities.Add {
+			k8sCtx.Capabilities.Add = append(k8sCtx.Capabilities.Add, corev1.Capability(capability))
+		}
+		for _, capability := range podReqCtx.Capabilities.Drop {
+			k8sCtx.Capabilities.Drop = append(k8sCtx.Capabilities.Drop, corev1.Capability(capability))
+		}
+	}
+	return k8sCtx
+}
+
+func getK8sSeccompProfile(profile *types.SeccompProfile) *corev1.SeccompProfile {
+	if profile == nil || profile.Type == "" {
+		return nil
+	}
+	k8sProfile := &corev1.SeccompProfile{Type: corev1.SeccompProfileType(profile.Type)}
+	// 只有 Localhost 类型可以设置 localhostProfile
+	if k8sProfile.Type == corev1.SeccompProfileTypeLocalhost {
+		k8sProfile.LocalhostProfile = &profile.LocalhostProfile
+	}
+	return k8sProfile
+}
+
+func getK8sAppArmorProfile(profile *types.AppArmorProfile) *corev1.AppArmorProfile {
+	if profile == nil || profile.Type == "" {
+		return nil
 	}
+	k8sProfile := &corev1.AppArmorProfile{Type: corev1.AppArmorProfileType(profile.Type)}
+	if k8sProfile.Type == corev1.AppArmorProfileTypeLocalhost {
+		k8sProfile.LocalhostProfile = &profile.LocalhostProfile
+	}
+	return k8sProfile
 }
+
 func (pc *Req2K8sConvert) getK8sPorts(podReqPorts []types.ContainerPort) []corev1.ContainerPort {
 	podK8sContainerPorts := make([]corev1.ContainerPort, 0)
 	for _, item := range podReqPorts {
@@ -296,9 +624,11 @@ func (pc *Req2K8sConvert) getK8sVolumeMounts(podReqMounts []types.VolumeMount) [
 	podK8sVolumeMounts := make([]corev1.VolumeMount, 0)
 	for _, mount := range podReqMounts {
 		podK8sVolumeMounts = append(podK8sVolumeMounts, corev1.VolumeMount{
-			Name:      mount.MountName,
-			MountPath: mount.MountPath,
-			ReadOnly:  mount.ReadOnly,
+			Name:        mount.MountName,
+			MountPath:   mount.MountPath,
+			ReadOnly:    mount.ReadOnly,
+			SubPath:     mount.SubPath,
+			SubPathExpr: mount.SubPathExpr,
 		})
 	}
 	return podK8sVolumeMounts
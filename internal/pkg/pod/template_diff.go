@@ -0,0 +1,67 @@
+package pod
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+// TemplateDiff 比较两个版本的pod模板, 用于展示版本之间的变化
+// 容器按名称逐个字段比较, 忽略控制器添加的 pod-template-hash 标签
+func TemplateDiff(from, to *corev1.PodTemplateSpec) []types.TemplateChange {
+	changes := make([]types.TemplateChange, 0)
+	add := func(field string, oldValue, newValue interface{}) {
+		changes = append(changes, types.TemplateChange{
+			Field: field,
+			Old:   toJSON(oldValue),
+			New:   toJSON(newValue),
+		})
+	}
+
+	fromLabels, toLabels := maps.Clone(from.Labels), maps.Clone(to.Labels)
+	delete(fromLabels, appsv1.DefaultDeploymentUniqueLabelKey)
+	delete(toLabels, appsv1.DefaultDeploymentUniqueLabelKey)
+	if !maps.Equal(fromLabels, toLabels) {
+		add("metadata.labels", fromLabels, toLabels)
+	}
+	if !maps.Equal(from.Annotations, to.Annotations) {
+		add("metadata.annotations", from.Annotations, to.Annotations)
+	}
+
+	diffTemplateContainers("spec.initContainers", from.Spec.InitContainers, to.Spec.InitContainers, add)
+	diffTemplateContainers("spec.containers", from.Spec.Containers, to.Spec.Containers, add)
+
+	fromSpec, toSpec := from.Spec.DeepCopy(), to.Spec.DeepCopy()
+	fromSpec.InitContainers, toSpec.InitContainers = nil, nil
+	fromSpec.Containers, toSpec.Containers = nil, nil
+	diffFields("spec", reflect.ValueOf(*fromSpec), reflect.ValueOf(*toSpec), add)
+	return changes
+}
+
+func diffTemplateContainers(field string, from, to []corev1.Container, add func(field string, oldValue, newValue interface{})) {
+	if !sameContainerNames(from, to) {
+		add(field, containerNames(from), containerNames(to))
+		return
+	}
+	for i := range from {
+		diffFields(fmt.Sprintf("%s[%s]", field, from[i].Name), reflect.ValueOf(from[i]), reflect.ValueOf(to[i]), add)
+	}
+}
+
+// diffFields 逐个比较结构体的一级字段, 字段名使用json名称
+func diffFields(prefix string, from, to reflect.Value, add func(field string, oldValue, newValue interface{})) {
+	for i := 0; i < from.NumField(); i++ {
+		if equality.Semantic.DeepEqual(from.Field(i).Interface(), to.Field(i).Interface()) {
+			continue
+		}
+		name, _, _ := strings.Cut(from.Type().Field(i).Tag.Get("json"), ",")
+		add(prefix+"."+name, from.Field(i).Interface(), to.Field(i).Interface())
+	}
+}
@@ -0,0 +1,384 @@
+// Package podsecurity 按 Kubernetes Pod Security Standards 检查pod定义
+package podsecurity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// pod安全标准级别
+const (
+	LevelPrivileged = "privileged"
+	LevelBaseline   = "baseline"
+	LevelRestricted = "restricted"
+)
+
+// 命名空间上的pod安全准入标签
+const (
+	EnforceLabel = "pod-security.kubernetes.io/enforce"
+	AuditLabel   = "pod-security.kubernetes.io/audit"
+	WarnLabel    = "pod-security.kubernetes.io/warn"
+)
+
+const (
+	// 与 PodSecurity 准入插件的默认配置一致, 命名空间没有 enforce 标签时不拒绝任何pod
+	defaultEnforceLevel = LevelPrivileged
+	// 命名空间没有标签且未指定级别时按 baseline 检查, 结果只作为警告
+	defaultWarnLevel = LevelBaseline
+)
+
+var levelRank = map[string]int{
+	LevelPrivileged: 0,
+	LevelBaseline:   1,
+	LevelRestricted: 2,
+}
+
+// ValidLevel 是否为合法的级别
+func ValidLevel(level string) bool {
+	_, ok := levelRank[level]
+	return ok
+}
+
+// Evaluate 检查pod, level 为空时按命名空间标签中最严格的级别检查
+// 强制级别为 level 与 enforce 标签中较严格的一个, 两者都没有时为 privileged, 违反项只作为警告返回
+func Evaluate(k8sPod *corev1.Pod, namespaceLabels map[string]string, level string) *types.PodSecurityResult {
+	result := &types.PodSecurityResult{
+		Enforce: labelLevel(namespaceLabels, EnforceLabel),
+		Audit:   labelLevel(namespaceLabels, AuditLabel),
+		Warn:    labelLevel(namespaceLabels, WarnLabel),
+	}
+	result.Level = stricter(level, result.Enforce, result.Audit, result.Warn)
+	if result.Level == "" {
+		result.Level = defaultWarnLevel
+	}
+	enforce := stricter(defaultEnforceLevel, level, result.Enforce)
+
+	result.Violations = Check(result.Level, k8sPod)
+	result.Allowed = true
+	for _, violation := range result.Violations {
+		if levelRank[violation.Level] <= levelRank[enforce] {
+			result.Allowed = false
+			break
+		}
+	}
+	return result
+}
+
+// labelLevel 无法识别的级别按 restricted 处理
+func labelLevel(labels map[string]string, key string) string {
+	level, ok := labels[key]
+	if !ok {
+		return ""
+	}
+	if !ValidLevel(level) {
+		return LevelRestricted
+	}
+	return level
+}
+
+func stricter(levels ...string) string {
+	result := ""
+	for _, level := range levels {
+		if level == "" {
+			continue
+		}
+		if result == "" || levelRank[level] > levelRank[result] {
+			result = level
+		}
+	}
+	return result
+}
+
+// Check 返回pod不满足 level 的所有字段, 每项的级别为禁止该配置的最低级别
+func Check(level string, k8sPod *corev1.Pod) []types.PodSecurityViolation {
+	c := &checker{violations: make([]types.PodSecurityViolation, 0), seen: map[string]bool{}}
+	if levelRank[level] >= levelRank[LevelBaseline] {
+		c.baseline(k8sPod)
+	}
+	if levelRank[level] >= levelRank[LevelRestricted] {
+		c.restricted(k8sPod)
+	}
+	return c.violations
+}
+
+type checker struct {
+	violations []types.PodSecurityViolation
+	seen       map[string]bool
+}
+
+// add 同一字段的同一检查项只记录一次, 如多个容器继承的pod级配置
+func (c *checker) add(level, check, field, message string) {
+	key := check + "/" + field
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.violations = append(c.violations, types.PodSecurityViolation{
+		Level:   level,
+		Check:   check,
+		Field:   field,
+		Message: message,
+	})
+}
+
+// 容器及其字段路径, 与 TemplateDiff 一致按容器名称定位
+type containerItem struct {
+	field     string
+	container *corev1.Container
+}
+
+func containerItems(spec *corev1.PodSpec) []containerItem {
+	items := make([]containerItem, 0, len(spec.InitContainers)+len(spec.Containers))
+	for i := range spec.InitContainers {
+		items = append(items, containerItem{
+			field:     fmt.Sprintf("spec.initContainers[%s]", spec.InitContainers[i].Name),
+			container: &spec.InitContainers[i],
+		})
+	}
+	for i := range spec.Containers {
+		items = append(items, containerItem{
+			field:     fmt.Sprintf("spec.containers[%s]", spec.Containers[i].Name),
+			container: &spec.Containers[i],
+		})
+	}
+	return items
+}
+
+// baseline 允许添加的capabilities
+var baselineCapabilities = map[string]bool{
+	"AUDIT_WRITE":      true,
+	"CHOWN":            true,
+	"DAC_OVERRIDE":     true,
+	"FOWNER":           true,
+	"FSETID":           true,
+	"KILL":             true,
+	"MKNOD":            true,
+	"NET_BIND_SERVICE": true,
+	"SETFCAP":          true,
+	"SETGID":           true,
+	"SETPCAP":          true,
+	"SETUID":           true,
+	"SYS_CHROOT":       true,
+}
+
+// baseline 允许的sysctl
+var safeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+	"net.ipv4.ip_local_reserved_ports":    true,
+	"net.ipv4.tcp_keepalive_time":         true,
+	"net.ipv4.tcp_fin_timeout":            true,
+	"net.ipv4.tcp_keepalive_intvl":        true,
+	"net.ipv4.tcp_keepalive_probes":       true,
+}
+
+// baseline 允许的 SELinux type, 空表示使用默认值
+var seLinuxTypes = map[string]bool{
+	"":                   true,
+	"container_t":        true,
+	"container_init_t":   true,
+	"container_kvm_t":    true,
+	"container_engine_t": true,
+}
+
+// 已废弃的 AppArmor 注解前缀, 注解值为 runtime/default、localhost/<profile> 或 unconfined
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+func (c *checker) baseline(k8sPod *corev1.Pod) {
+	spec := &k8sPod.Spec
+	podCtx := spec.SecurityContext
+	if podCtx == nil {
+		podCtx = &corev1.PodSecurityContext{}
+	}
+
+	if podCtx.WindowsOptions != nil && podCtx.WindowsOptions.HostProcess != nil && *podCtx.WindowsOptions.HostProcess {
+		c.add(LevelBaseline, "hostProcess", "spec.securityContext.windowsOptions.hostProcess", "不允许运行 Windows HostProcess 容器")
+	}
+	if spec.HostNetwork {
+		c.add(LevelBaseline, "hostNamespaces", "spec.hostNetwork", "不允许使用宿主机网络")
+	}
+	if spec.HostPID {
+		c.add(LevelBaseline, "hostNamespaces", "spec.hostPID", "不允许使用宿主机PID命名空间")
+	}
+	if spec.HostIPC {
+		c.add(LevelBaseline, "hostNamespaces", "spec.hostIPC", "不允许使用宿主机IPC命名空间")
+	}
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			c.add(LevelBaseline, "hostPathVolumes", fmt.Sprintf("spec.volumes[%s].hostPath", volume.Name), "不允许使用 hostPath 卷")
+		}
+	}
+	c.checkAppArmor("spec.securityContext.appArmorProfile", podCtx.AppArmorProfile)
+	c.checkSELinux("spec.securityContext.seLinuxOptions", podCtx.SELinuxOptions)
+	if podCtx.SeccompProfile != nil && podCtx.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		c.add(LevelBaseline, "seccompProfile_baseline", "spec.securityContext.seccompProfile.type", "不允许使用 Unconfined 的 seccomp 配置")
+	}
+	for _, sysctl := range podCtx.Sysctls {
+		if !safeSysctls[sysctl.Name] {
+			c.add(LevelBaseline, "sysctls", fmt.Sprintf("spec.securityContext.sysctls[%s]", sysctl.Name), fmt.Sprintf("sysctl %s 不在安全列表中", sysctl.Name))
+		}
+	}
+	keys := make([]string, 0, len(k8sPod.Annotations))
+	for key := range k8sPod.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := k8sPod.Annotations[key]
+		if strings.HasPrefix(key, appArmorAnnotationPrefix) && value != "runtime/default" && !strings.HasPrefix(value, "localhost/") {
+			c.add(LevelBaseline, "appArmorProfile", fmt.Sprintf("metadata.annotations[%s]", key), "AppArmor 只能使用 runtime/default 或 localhost 配置")
+		}
+	}
+
+	for _, item := range containerItems(spec) {
+		ctx := item.container.SecurityContext
+		for i, port := range item.container.Ports {
+			if port.HostPort != 0 {
+				c.add(LevelBaseline, "hostPorts", fmt.Sprintf("%s.ports[%d].hostPort", item.field, i), "不允许使用宿主机端口")
+			}
+		}
+		if ctx == nil {
+			continue
+		}
+		field := item.field + ".securityContext"
+		if ctx.WindowsOptions != nil && ctx.WindowsOptions.HostProcess != nil && *ctx.WindowsOptions.HostProcess {
+			c.add(LevelBaseline, "hostProcess", field+".windowsOptions.hostProcess", "不允许运行 Windows HostProcess 容器")
+		}
+		if ctx.Privileged != nil && *ctx.Privileged {
+			c.add(LevelBaseline, "privileged", field+".privileged", "不允许运行特权容器")
+		}
+		if ctx.Capabilities != nil {
+			for _, capability := range ctx.Capabilities.Add {
+				if !baselineCapabilities[string(capability)] {
+					c.add(LevelBaseline, "capabilities_baseline", field+".capabilities.add", fmt.Sprintf("不允许添加 capability %s", capability))
+				}
+			}
+		}
+		c.checkAppArmor(field+".appArmorProfile", ctx.AppArmorProfile)
+		c.checkSELinux(field+".seLinuxOptions", ctx.SELinuxOptions)
+		if ctx.ProcMount != nil && *ctx.ProcMount != corev1.DefaultProcMount {
+			c.add(LevelBaseline, "procMount", field+".procMount", "procMount 只能为 Default")
+		}
+		if ctx.SeccompProfile != nil && ctx.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			c.add(LevelBaseline, "seccompProfile_baseline", field+".seccompProfile.type", "不允许使用 Unconfined 的 seccomp 配置")
+		}
+	}
+}
+
+func (c *checker) checkAppArmor(field string, profile *corev1.AppArmorProfile) {
+	if profile != nil && profile.Type == corev1.AppArmorProfileTypeUnconfined {
+		c.add(LevelBaseline, "appArmorProfile", field+".type", "不允许使用 Unconfined 的 AppArmor 配置")
+	}
+}
+
+func (c *checker) checkSELinux(field string, options *corev1.SELinuxOptions) {
+	if options == nil {
+		return
+	}
+	if !seLinuxTypes[options.Type] {
+		c.add(LevelBaseline, "seLinuxOptions", field+".type", fmt.Sprintf("不允许使用 SELinux type %s", options.Type))
+	}
+	if options.User != "" {
+		c.add(LevelBaseline, "seLinuxOptions", field+".user", "不允许设置 SELinux user")
+	}
+	if options.Role != "" {
+		c.add(LevelBaseline, "seLinuxOptions", field+".role", "不允许设置 SELinux role")
+	}
+}
+
+// restricted 允许的卷类型
+func restrictedVolume(volume corev1.Volume) bool {
+	source := volume.VolumeSource
+	return source.ConfigMap != nil || source.CSI != nil || source.DownwardAPI != nil || source.EmptyDir != nil ||
+		source.Ephemeral != nil || source.PersistentVolumeClaim != nil || source.Projected != nil || source.Secret != nil
+}
+
+func (c *checker) restricted(k8sPod *corev1.Pod) {
+	spec := &k8sPod.Spec
+	podCtx := spec.SecurityContext
+	if podCtx == nil {
+		podCtx = &corev1.PodSecurityContext{}
+	}
+
+	for _, volume := range spec.Volumes {
+		if !restrictedVolume(volume) {
+			c.add(LevelRestricted, "restrictedVolumes", fmt.Sprintf("spec.volumes[%s]", volume.Name),
+				"只允许使用 configMap、csi、downwardAPI、emptyDir、ephemeral、persistentVolumeClaim、projected、secret 卷")
+		}
+	}
+	if podCtx.RunAsUser != nil && *podCtx.RunAsUser == 0 {
+		c.add(LevelRestricted, "runAsUser", "spec.securityContext.runAsUser", "不允许以 root(0) 用户运行")
+	}
+
+	for _, item := range containerItems(spec) {
+		ctx := item.container.SecurityContext
+		if ctx == nil {
+			ctx = &corev1.SecurityContext{}
+		}
+		field := item.field + ".securityContext"
+
+		if ctx.AllowPrivilegeEscalation == nil || *ctx.AllowPrivilegeEscalation {
+			c.add(LevelRestricted, "allowPrivilegeEscalation", field+".allowPrivilegeEscalation", "allowPrivilegeEscalation 必须为 false")
+		}
+
+		// 容器级配置覆盖pod级配置, 都未设置时提示在容器上设置
+		switch {
+		case ctx.RunAsNonRoot != nil:
+			if !*ctx.RunAsNonRoot {
+				c.add(LevelRestricted, "runAsNonRoot", field+".runAsNonRoot", "runAsNonRoot 必须为 true")
+			}
+		case podCtx.RunAsNonRoot != nil:
+			if !*podCtx.RunAsNonRoot {
+				c.add(LevelRestricted, "runAsNonRoot", "spec.securityContext.runAsNonRoot", "runAsNonRoot 必须为 true")
+			}
+		default:
+			c.add(LevelRestricted, "runAsNonRoot", field+".runAsNonRoot", "runAsNonRoot 必须为 true")
+		}
+		if ctx.RunAsUser != nil && *ctx.RunAsUser == 0 {
+			c.add(LevelRestricted, "runAsUser", field+".runAsUser", "不允许以 root(0) 用户运行")
+		}
+
+		switch {
+		case ctx.SeccompProfile != nil:
+			if !restrictedSeccomp(ctx.SeccompProfile) {
+				c.add(LevelRestricted, "seccompProfile_restricted", field+".seccompProfile.type", "seccomp 只能为 RuntimeDefault 或 Localhost")
+			}
+		case podCtx.SeccompProfile != nil:
+			if !restrictedSeccomp(podCtx.SeccompProfile) {
+				c.add(LevelRestricted, "seccompProfile_restricted", "spec.securityContext.seccompProfile.type", "seccomp 只能为 RuntimeDefault 或 Localhost")
+			}
+		default:
+			c.add(LevelRestricted, "seccompProfile_restricted", field+".seccompProfile.type", "必须设置 seccomp 为 RuntimeDefault 或 Localhost")
+		}
+
+		capabilities := ctx.Capabilities
+		if capabilities == nil {
+			capabilities = &corev1.Capabilities{}
+		}
+		dropAll := false
+		for _, capability := range capabilities.Drop {
+			if capability == "ALL" {
+				dropAll = true
+			}
+		}
+		if !dropAll {
+			c.add(LevelRestricted, "capabilities_restricted", field+".capabilities.drop", "必须删除所有 capability(ALL)")
+		}
+		for _, capability := range capabilities.Add {
+			if capability != "NET_BIND_SERVICE" {
+				c.add(LevelRestricted, "capabilities_restricted", field+".capabilities.add", fmt.Sprintf("只允许添加 NET_BIND_SERVICE, 不允许添加 %s", capability))
+			}
+		}
+	}
+}
+
+func restrictedSeccomp(profile *corev1.SeccompProfile) bool {
+	return profile.Type == corev1.SeccompProfileTypeRuntimeDefault || profile.Type == corev1.SeccompProfileTypeLocalhost
+}
@@ -0,0 +1,144 @@
+package podsecurity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/xiaofan193/k8sadmin/internal/types"
+)
+
+func restrictedPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   ptr.To(true),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []corev1.Container{{
+				Name:  "nginx",
+				Image: "nginx",
+				SecurityContext: &corev1.SecurityContext{
+					Privileged:               ptr.To(false),
+					AllowPrivilegeEscalation: ptr.To(false),
+					Capabilities:             &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE"}, Drop: []corev1.Capability{"ALL"}},
+				},
+			}},
+			Volumes: []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+		},
+	}
+}
+
+func fields(violations []types.PodSecurityViolation) []string {
+	result := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		result = append(result, violation.Check+" "+violation.Field)
+	}
+	return result
+}
+
+func TestCheck(t *testing.T) {
+	assert.Empty(t, Check(LevelRestricted, restrictedPod()))
+
+	k8sPod := restrictedPod()
+	k8sPod.Annotations = map[string]string{appArmorAnnotationPrefix + "nginx": "unconfined"}
+	k8sPod.Spec.HostNetwork = true
+	k8sPod.Spec.Volumes = append(k8sPod.Spec.Volumes, corev1.Volume{Name: "host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var"}}})
+	k8sPod.Spec.SecurityContext.Sysctls = []corev1.Sysctl{{Name: "net.ipv4.tcp_syncookies"}, {Name: "kernel.msgmax"}}
+	k8sPod.Spec.InitContainers = []corev1.Container{{
+		Name:  "init",
+		Ports: []corev1.ContainerPort{{ContainerPort: 80, HostPort: 8080}},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged:   ptr.To(true),
+			Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"CHOWN", "SYS_ADMIN"}},
+		},
+	}}
+
+	baseline := []string{
+		"hostNamespaces spec.hostNetwork",
+		"hostPathVolumes spec.volumes[host].hostPath",
+		"sysctls spec.securityContext.sysctls[kernel.msgmax]",
+		"appArmorProfile metadata.annotations[container.apparmor.security.beta.kubernetes.io/nginx]",
+		"hostPorts spec.initContainers[init].ports[0].hostPort",
+		"privileged spec.initContainers[init].securityContext.privileged",
+		"capabilities_baseline spec.initContainers[init].securityContext.capabilities.add",
+	}
+	assert.Empty(t, Check(LevelPrivileged, k8sPod))
+	assert.Equal(t, baseline, fields(Check(LevelBaseline, k8sPod)))
+
+	// init 容器没有设置 restricted 要求的字段, pod级的 runAsNonRoot 和 seccomp 对其生效
+	violations := Check(LevelRestricted, k8sPod)
+	assert.Equal(t, append(baseline,
+		"restrictedVolumes spec.volumes[host]",
+		"allowPrivilegeEscalation spec.initContainers[init].securityContext.allowPrivilegeEscalation",
+		"capabilities_restricted spec.initContainers[init].securityContext.capabilities.drop",
+		"capabilities_restricted spec.initContainers[init].securityContext.capabilities.add",
+	), fields(violations))
+	assert.Equal(t, LevelBaseline, violations[0].Level)
+	assert.Equal(t, LevelRestricted, violations[len(violations)-1].Level)
+}
+
+func TestCheckRestrictedInherited(t *testing.T) {
+	k8sPod := restrictedPod()
+	k8sPod.Spec.SecurityContext = &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](0)}
+	k8sPod.Spec.Containers = append(k8sPod.Spec.Containers, *k8sPod.Spec.Containers[0].DeepCopy())
+	k8sPod.Spec.Containers[1].Name = "sidecar"
+	k8sPod.Spec.Containers[1].SecurityContext.RunAsNonRoot = ptr.To(true)
+	k8sPod.Spec.Containers[1].SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}
+
+	assert.Equal(t, []string{
+		"seccompProfile_baseline spec.containers[sidecar].securityContext.seccompProfile.type",
+		"runAsUser spec.securityContext.runAsUser",
+		"runAsNonRoot spec.containers[nginx].securityContext.runAsNonRoot",
+		"seccompProfile_restricted spec.containers[nginx].securityContext.seccompProfile.type",
+		"seccompProfile_restricted spec.containers[sidecar].securityContext.seccompProfile.type",
+	}, fields(Check(LevelRestricted, k8sPod)))
+}
+
+func TestEvaluate(t *testing.T) {
+	k8sPod := restrictedPod()
+	k8sPod.Spec.SecurityContext.RunAsNonRoot = nil
+
+	// 没有标签时按 baseline 检查
+	result := Evaluate(k8sPod, nil, "")
+	assert.Equal(t, LevelBaseline, result.Level)
+	assert.True(t, result.Allowed)
+	assert.Empty(t, result.Violations)
+
+	// 没有 enforce 标签时与准入插件一致按 privileged 强制, baseline 的违反项只作为警告
+	privileged := restrictedPod()
+	privileged.Spec.HostNetwork = true
+	result = Evaluate(privileged, nil, "")
+	assert.Equal(t, LevelBaseline, result.Level)
+	assert.Empty(t, result.Enforce)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, []string{"hostNamespaces spec.hostNetwork"}, fields(result.Violations))
+
+	result = Evaluate(privileged, map[string]string{EnforceLabel: LevelBaseline}, "")
+	assert.False(t, result.Allowed)
+
+	// warn 为 restricted 时返回违反项, 但 enforce 为 baseline 仍允许提交
+	labels := map[string]string{EnforceLabel: LevelBaseline, WarnLabel: LevelRestricted}
+	result = Evaluate(k8sPod, labels, "")
+	assert.Equal(t, LevelRestricted, result.Level)
+	assert.Equal(t, LevelRestricted, result.Warn)
+	assert.Empty(t, result.Audit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, []string{"runAsNonRoot spec.containers[nginx].securityContext.runAsNonRoot"}, fields(result.Violations))
+
+	// 只有 warn 标签时不强制
+	result = Evaluate(k8sPod, map[string]string{WarnLabel: LevelRestricted}, "")
+	assert.True(t, result.Allowed)
+
+	result = Evaluate(k8sPod, labels, LevelRestricted)
+	assert.False(t, result.Allowed)
+
+	// 无法识别的标签按 restricted 处理
+	result = Evaluate(k8sPod, map[string]string{EnforceLabel: "strict"}, "")
+	assert.Equal(t, LevelRestricted, result.Enforce)
+	assert.False(t, result.Allowed)
+}
@@ -0,0 +1,148 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+// ErrDisabled 配置中没有启用prometheus
+var ErrDisabled = errors.New("prometheus is not enabled")
+
+const defaultTimeout = 30 * time.Second
+
+// Client prometheus http api 客户端, 只实现了范围查询
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient 根据 system.prometheus 配置创建客户端, scheme 为空时使用 http
+func NewClient(conf global.Prometheus) (*Client, error) {
+	if !conf.Enable || conf.Host == "" {
+		return nil, ErrDisabled
+	}
+	scheme := conf.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	return &Client{
+		baseURL:    scheme + "://" + conf.Host,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}, nil
+}
+
+// ClusterConfig 集群使用的prometheus配置, system.prometheus.clusters 中没有该集群时使用全局配置
+func ClusterConfig(conf global.Prometheus, cluster string) global.Prometheus {
+	if clusterConf, ok := conf.Clusters[cluster]; ok {
+		return clusterConf
+	}
+	conf.Clusters = nil
+	return conf
+}
+
+// Series 一条时间序列
+type Series struct {
+	Metric map[string]string
+	Points []Point
+}
+
+type Point struct {
+	Timestamp int64 // 秒
+	Value     float64
+}
+
+// queryResponse /api/v1/query_range 的返回格式
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]interface{}  `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryRange 执行范围查询, step 为采样间隔
+func (c *Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	form := url.Values{}
+	form.Set("query", query)
+	form.Set("start", strconv.FormatInt(start.Unix(), 10))
+	form.Set("end", strconv.FormatInt(end.Unix(), 10))
+	form.Set("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/query_range?"+form.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// 查询错误时prometheus返回4xx/5xx, 但body中仍有错误信息
+	result := &queryResponse{}
+	if err = json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("prometheus response status %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("prometheus query error %s: %s", result.ErrorType, result.Error)
+	}
+	if result.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("prometheus unexpected result type %q", result.Data.ResultType)
+	}
+
+	seriesList := make([]Series, 0, len(result.Data.Result))
+	for _, item := range result.Data.Result {
+		series := Series{Metric: item.Metric, Points: make([]Point, 0, len(item.Values))}
+		for _, value := range item.Values {
+			point, err := parsePoint(value)
+			if err != nil {
+				return nil, err
+			}
+			series.Points = append(series.Points, point)
+		}
+		seriesList = append(seriesList, series)
+	}
+	return seriesList, nil
+}
+
+// parsePoint 采样点格式为 [时间戳, "值"]
+func parsePoint(value [2]interface{}) (Point, error) {
+	timestamp, ok := value[0].(float64)
+	if !ok {
+		return Point{}, fmt.Errorf("prometheus invalid timestamp %v", value[0])
+	}
+	raw, ok := value[1].(string)
+	if !ok {
+		return Point{}, fmt.Errorf("prometheus invalid value %v", value[1])
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("prometheus invalid value %q", raw)
+	}
+	return Point{Timestamp: int64(timestamp), Value: v}, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
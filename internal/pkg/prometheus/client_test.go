@@ -0,0 +1,68 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	client, err := NewClient(global.Prometheus{Enable: true, Host: strings.TrimPrefix(srv.URL, "http://")})
+	require.NoError(t, err)
+	return client
+}
+
+func TestNewClientDisabled(t *testing.T) {
+	_, err := NewClient(global.Prometheus{Host: "127.0.0.1:9090"})
+	assert.ErrorIs(t, err, ErrDisabled)
+	_, err = NewClient(global.Prometheus{Enable: true})
+	assert.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestQueryRange(t *testing.T) {
+	query := PodSelector("default", `web"0`).Queries()[MetricMemory]
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		assert.Equal(t, query, r.URL.Query().Get("query"))
+		assert.Equal(t, "100", r.URL.Query().Get("start"))
+		assert.Equal(t, "160", r.URL.Query().Get("end"))
+		assert.Equal(t, "30", r.URL.Query().Get("step"))
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{},"values":[[100,"1024"],[130,"2048.5"],[160,"NaN"]]}]}}`))
+	})
+
+	series, err := client.QueryRange(context.Background(), query, time.Unix(100, 0), time.Unix(160, 0), 30*time.Second)
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	require.Len(t, series[0].Points, 3)
+	assert.Equal(t, Point{Timestamp: 130, Value: 2048.5}, series[0].Points[1])
+	assert.Contains(t, query, `pod="web\"0"`)
+}
+
+func TestQueryRangeError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+	})
+	_, err := client.QueryRange(context.Background(), "up{", time.Unix(100, 0), time.Unix(160, 0), time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad_data")
+
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("bad gateway"))
+	})
+	_, err = client.QueryRange(context.Background(), "up", time.Unix(100, 0), time.Unix(160, 0), time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+}
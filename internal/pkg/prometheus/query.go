@@ -0,0 +1,104 @@
+package prometheus
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 指标名称
+const (
+	MetricCpu             = "cpu"             // 单位: 核
+	MetricMemory          = "memory"          // 单位: 字节, working set
+	MetricNetworkReceive  = "networkReceive"  // 单位: 字节/秒
+	MetricNetworkTransmit = "networkTransmit" // 单位: 字节/秒
+)
+
+// rate 的时间窗口, 需要大于抓取间隔的4倍
+const rateWindow = "5m"
+
+// 与 apiserver 按 generateName 生成名称的规则一致: 前缀最多58个字符, 加5个字符的随机后缀
+const (
+	maxGeneratedNamePrefix = 58
+	generatedNameSuffix    = "[a-z0-9]{5}"
+)
+
+// Selector 由标签匹配条件组成, 用于构造 cAdvisor 指标的查询语句
+type Selector struct {
+	matchers []string
+	// 节点级别使用根 cgroup 的用量, 不区分容器
+	node bool
+	// 不匹配任何序列, 不需要查询
+	empty bool
+}
+
+// PodSelector 单个pod
+func PodSelector(namespace, pod string) Selector {
+	return Selector{matchers: []string{label("namespace", "=", namespace), label("pod", "=", pod)}}
+}
+
+// DeploymentSelector deployment 的 replicaset 创建的所有pod, pod 名称为 <replicaset>-<随机后缀>
+// 只按 deployment 名称前缀匹配会包含同名前缀的其他工作负载, 需要传入 deployment 拥有的所有 replicaset
+func DeploymentSelector(namespace string, replicaSets []string) Selector {
+	if len(replicaSets) == 0 {
+		return Selector{empty: true}
+	}
+	prefixes := make([]string, 0, len(replicaSets))
+	for _, name := range replicaSets {
+		prefix := name + "-"
+		if len(prefix) > maxGeneratedNamePrefix {
+			prefix = prefix[:maxGeneratedNamePrefix]
+		}
+		prefixes = append(prefixes, regexp.QuoteMeta(prefix))
+	}
+	return Selector{matchers: []string{
+		label("namespace", "=", namespace),
+		label("pod", "=~", "(?:"+strings.Join(prefixes, "|")+")"+generatedNameSuffix),
+	}}
+}
+
+// NamespaceSelector 命名空间下的所有pod
+func NamespaceSelector(namespace string) Selector {
+	return Selector{matchers: []string{label("namespace", "=", namespace)}}
+}
+
+// NodeSelector 节点根 cgroup 的用量, 依赖 kubelet 抓取配置添加的 node 标签
+func NodeSelector(node string) Selector {
+	return Selector{matchers: []string{label("node", "=", node), label("id", "=", "/")}, node: true}
+}
+
+// WithCluster 多个集群共用一个prometheus时按集群标签过滤, labelName 为空时不过滤
+func (s Selector) WithCluster(labelName, cluster string) Selector {
+	if labelName == "" || s.empty {
+		return s
+	}
+	s.matchers = append([]string{label(labelName, "=", cluster)}, s.matchers...)
+	return s
+}
+
+// Empty 不匹配任何序列
+func (s Selector) Empty() bool {
+	return s.empty
+}
+
+// Queries 返回各指标的查询语句
+// cpu 和内存只统计业务容器(排除 pause 容器和pod级别的汇总), 网络指标只有pod级别
+func (s Selector) Queries() map[string]string {
+	all := strings.Join(s.matchers, ",")
+	containers := all
+	if !s.node {
+		containers += "," + label("container", "!=", "") + "," + label("container", "!=", "POD")
+	}
+	return map[string]string{
+		MetricCpu:             fmt.Sprintf("sum(rate(container_cpu_usage_seconds_total{%s}[%s]))", containers, rateWindow),
+		MetricMemory:          fmt.Sprintf("sum(container_memory_working_set_bytes{%s})", containers),
+		MetricNetworkReceive:  fmt.Sprintf("sum(rate(container_network_receive_bytes_total{%s}[%s]))", all, rateWindow),
+		MetricNetworkTransmit: fmt.Sprintf("sum(rate(container_network_transmit_bytes_total{%s}[%s]))", all, rateWindow),
+	}
+}
+
+// label 构造标签匹配条件, 值中的引号和反斜杠需要转义
+func label(name, op, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return fmt.Sprintf(`%s%s"%s"`, name, op, value)
+}
@@ -0,0 +1,50 @@
+package prometheus
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+func TestDeploymentSelector(t *testing.T) {
+	assert.True(t, DeploymentSelector("default", nil).Empty())
+
+	longRS := strings.Repeat("a", 60) + "-5d9c7b8f6"
+	selector := DeploymentSelector("default", []string{"web-5d9c7b8f6", "web-7c6d4b9f8", longRS})
+	query := selector.Queries()[MetricMemory]
+	match := regexp.MustCompile(`pod=~"([^"]+)"`).FindStringSubmatch(query)
+	// prometheus 的正则匹配整个标签值
+	re := regexp.MustCompile("^" + strings.ReplaceAll(match[1], `\\`, `\`) + "$")
+
+	assert.True(t, re.MatchString("web-5d9c7b8f6-x2k4z"))
+	assert.True(t, re.MatchString("web-7c6d4b9f8-b7q9m"))
+	assert.True(t, re.MatchString(strings.Repeat("a", 58)+"x2k4z"))
+	// 同名前缀的其他工作负载
+	assert.False(t, re.MatchString("web-db-0"))
+	assert.False(t, re.MatchString("web-api-5d9c7b8f6-x2k4z"))
+	assert.False(t, re.MatchString("web-5d9c7b8f6-x2k4z-1"))
+}
+
+func TestWithCluster(t *testing.T) {
+	query := PodSelector("default", "web").WithCluster("cluster", "prod").Queries()[MetricCpu]
+	assert.Contains(t, query, `{cluster="prod",namespace="default",pod="web",`)
+
+	query = PodSelector("default", "web").WithCluster("", "prod").Queries()[MetricCpu]
+	assert.NotContains(t, query, "prod")
+}
+
+func TestClusterConfig(t *testing.T) {
+	conf := global.Prometheus{
+		Enable:       true,
+		Host:         "prometheus:9090",
+		ClusterLabel: "cluster",
+		Clusters:     map[string]global.Prometheus{"prod": {Enable: true, Host: "10.0.0.10:9090"}},
+	}
+	assert.Equal(t, global.Prometheus{Enable: true, Host: "10.0.0.10:9090"}, ClusterConfig(conf, "prod"))
+	assert.Equal(t, "prometheus:9090", ClusterConfig(conf, "default").Host)
+	assert.Equal(t, "cluster", ClusterConfig(conf, "default").ClusterLabel)
+}
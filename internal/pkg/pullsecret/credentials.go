@@ -0,0 +1,53 @@
+package pullsecret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xiaofan193/k8sadmin/internal/dao"
+	"github.com/xiaofan193/k8sadmin/internal/database"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/pkg/global"
+)
+
+// LoadCredentials 汇总 system.harbor 配置和数据库中的镜像仓库凭证
+func LoadCredentials(ctx context.Context) ([]Credential, error) {
+	creds := make([]Credential, 0)
+	if global.CONF != nil {
+		harborConf := global.CONF.System.Harbor
+		if harborConf.Enable && harborConf.Host != "" {
+			creds = append(creds, Credential{Server: harborConf.Host, Username: harborConf.Username, Password: harborConf.Password})
+		}
+	}
+
+	records, err := dao.NewRegistryDao(database.GetDB()).GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		password, err := cluster.Decrypt(record.Password)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt registry %s password: %w", record.Name, err)
+		}
+		creds = append(creds, Credential{Server: record.Server, Username: record.Username, Password: password})
+	}
+	return creds, nil
+}
+
+// SyncClusters 凭证变化后更新所有已注册集群中的托管secret, 单个集群失败不影响其他集群
+func SyncClusters(ctx context.Context) map[string]error {
+	errs := make(map[string]error)
+	creds, err := LoadCredentials(ctx)
+	if err != nil {
+		for _, c := range cluster.List() {
+			errs[c.Name] = err
+		}
+		return errs
+	}
+	for _, c := range cluster.List() {
+		if _, err = Sync(ctx, c.KubeConfigSet, creds); err != nil {
+			errs[c.Name] = err
+		}
+	}
+	return errs
+}
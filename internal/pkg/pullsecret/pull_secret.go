@@ -0,0 +1,121 @@
+// Package pullsecret 根据配置的镜像仓库凭证, 在每个命名空间维护一个托管的 imagePullSecret
+package pullsecret
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ManagedSecretName 托管secret的名称, 每个命名空间一个
+	ManagedSecretName = "k8sadmin-registry"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "k8sadmin"
+)
+
+// Credential 一个镜像仓库的登录凭证
+type Credential struct {
+	Server   string
+	Username string
+	Password string
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+// DockerConfigJSON 生成 .dockerconfigjson 的内容, 同一个仓库配置多次时后面的生效
+func DockerConfigJSON(creds []Credential) ([]byte, error) {
+	config := dockerConfigJSON{Auths: make(map[string]dockerConfigEntry, len(creds))}
+	for _, cred := range creds {
+		config.Auths[cred.Server] = dockerConfigEntry{
+			Username: cred.Username,
+			Password: cred.Password,
+			Auth:     base64.StdEncoding.EncodeToString([]byte(cred.Username + ":" + cred.Password)),
+		}
+	}
+	return json.Marshal(config)
+}
+
+// Ensure 创建或更新命名空间下的托管secret, 同名但不是由 k8sadmin 创建的secret不会被修改
+func Ensure(ctx context.Context, client kubernetes.Interface, namespace string, creds []Credential) error {
+	data, err := DockerConfigJSON(creds)
+	if err != nil {
+		return err
+	}
+	secretApi := client.CoreV1().Secrets(namespace)
+	current, err := secretApi.Get(ctx, ManagedSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secretApi.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ManagedSecretName,
+				Namespace: namespace,
+				Labels:    map[string]string{managedByLabel: managedByValue},
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: data},
+		}, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if current.Labels[managedByLabel] != managedByValue {
+		return fmt.Errorf("secret %s/%s already exists and is not managed by %s", namespace, ManagedSecretName, managedByValue)
+	}
+	return update(ctx, client, current, data)
+}
+
+// Sync 更新集群中所有已创建的托管secret, 返回更新的数量
+func Sync(ctx context.Context, client kubernetes.Interface, creds []Credential) (int, error) {
+	data, err := DockerConfigJSON(creds)
+	if err != nil {
+		return 0, err
+	}
+	secretList, err := client.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: managedByLabel + "=" + managedByValue,
+	})
+	if err != nil {
+		return 0, err
+	}
+	updated := 0
+	for i := range secretList.Items {
+		secret := &secretList.Items[i]
+		if secret.Name != ManagedSecretName || bytes.Equal(secret.Data[corev1.DockerConfigJsonKey], data) {
+			continue
+		}
+		if err = update(ctx, client, secret, data); err != nil {
+			return updated, fmt.Errorf("update secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+func update(ctx context.Context, client kubernetes.Interface, secret *corev1.Secret, data []byte) error {
+	if bytes.Equal(secret.Data[corev1.DockerConfigJsonKey], data) {
+		return nil
+	}
+	secret = secret.DeepCopy()
+	secret.Data = map[string][]byte{corev1.DockerConfigJsonKey: data}
+	_, err := client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
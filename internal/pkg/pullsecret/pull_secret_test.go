@@ -0,0 +1,65 @@
+package pullsecret
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDockerConfigJSON(t *testing.T) {
+	data, err := DockerConfigJSON([]Credential{{Server: "harbor.example.com", Username: "admin", Password: "secret"}})
+	require.NoError(t, err)
+	config := dockerConfigJSON{}
+	require.NoError(t, json.Unmarshal(data, &config))
+	assert.Equal(t, "YWRtaW46c2VjcmV0", config.Auths["harbor.example.com"].Auth)
+}
+
+func TestEnsure(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ManagedSecretName, Namespace: "user-owned"},
+	})
+	creds := []Credential{{Server: "harbor.example.com", Username: "admin", Password: "secret"}}
+
+	require.NoError(t, Ensure(ctx, client, "default", creds))
+	secret, err := client.CoreV1().Secrets("default").Get(ctx, ManagedSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.SecretTypeDockerConfigJson, secret.Type)
+	assert.Equal(t, managedByValue, secret.Labels[managedByLabel])
+
+	// 凭证变化后更新
+	creds = append(creds, Credential{Server: "registry.example.com", Username: "ci", Password: "token"})
+	require.NoError(t, Ensure(ctx, client, "default", creds))
+	secret, err = client.CoreV1().Secrets("default").Get(ctx, ManagedSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, string(secret.Data[corev1.DockerConfigJsonKey]), "registry.example.com")
+
+	// 不是由 k8sadmin 创建的同名secret不修改
+	assert.Error(t, Ensure(ctx, client, "user-owned", creds))
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	old := []Credential{{Server: "harbor.example.com", Username: "admin", Password: "old"}}
+	for _, namespace := range []string{"a", "b"} {
+		require.NoError(t, Ensure(ctx, client, namespace, old))
+	}
+
+	updated, err := Sync(ctx, client, []Credential{{Server: "harbor.example.com", Username: "admin", Password: "new"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated)
+	secret, err := client.CoreV1().Secrets("b").Get(ctx, ManagedSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, string(secret.Data[corev1.DockerConfigJsonKey]), `"password":"new"`)
+
+	updated, err = Sync(ctx, client, []Credential{{Server: "harbor.example.com", Username: "admin", Password: "new"}})
+	require.NoError(t, err)
+	assert.Equal(t, 0, updated)
+}
@@ -0,0 +1,192 @@
+// Package terminal 将浏览器的websocket连接适配为 remotecommand 需要的 stdin/stdout 和终端大小队列
+package terminal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// 客户端消息类型
+const (
+	MessageStdin  = "stdin"
+	MessageResize = "resize"
+)
+
+// 记录的输入超过该长度后不再追加
+const maxTranscript = 64 * 1024
+
+const (
+	writeWait = 10 * time.Second
+	// 关闭帧的负载最多125字节, 其中2字节是状态码
+	maxCloseReason = 123
+)
+
+// CheckOrigin websocket 握手不受浏览器同源策略限制, 只接受与请求 Host 相同或在 allowed 中的 Origin, 防止其他站点借用户的令牌打开终端
+// 没有 Origin 请求头的非浏览器客户端不受限制, allowed 的格式为 scheme://host[:port]
+func CheckOrigin(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, item := range allowed {
+		if strings.EqualFold(strings.TrimSuffix(item, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Message 客户端发送的消息, 服务端输出以二进制消息原样发送
+type Message struct {
+	Type string `json:"type"` // stdin | resize
+	Data string `json:"data"` // type=stdin 时的输入
+	Cols uint16 `json:"cols"` // type=resize 时的终端列数
+	Rows uint16 `json:"rows"` // type=resize 时的终端行数
+}
+
+// Session 一次终端会话, 实现 io.Reader/io.Writer/remotecommand.TerminalSizeQueue
+type Session struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	pending []byte
+	sizeCh  chan remotecommand.TerminalSize
+	done    chan struct{}
+	once    sync.Once
+
+	record       bool
+	transcriptMu sync.Mutex
+	transcript   []byte
+}
+
+// NewSession record 为true时记录用户输入, 用于审计
+func NewSession(conn *websocket.Conn, record bool) *Session {
+	return &Session{
+		conn:   conn,
+		sizeCh: make(chan remotecommand.TerminalSize, 1),
+		done:   make(chan struct{}),
+		record: record,
+	}
+}
+
+// Read 读取客户端输入, resize 消息转给 Next
+func (s *Session) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.Close()
+			return 0, err
+		}
+		msg := Message{}
+		if err = json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		switch msg.Type {
+		case MessageStdin:
+			s.pending = []byte(msg.Data)
+			s.appendTranscript(msg.Data)
+		case MessageResize:
+			s.Resize(msg.Cols, msg.Rows)
+		}
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Write 将容器输出发送给客户端
+func (s *Session) Write(p []byte) (int, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := s.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Resize 更新终端大小, 只保留最新的一次
+func (s *Session) Resize(cols, rows uint16) {
+	if cols == 0 || rows == 0 {
+		return
+	}
+	size := remotecommand.TerminalSize{Width: cols, Height: rows}
+	for {
+		select {
+		case s.sizeCh <- size:
+			return
+		default:
+		}
+		select {
+		case <-s.sizeCh:
+		default:
+		}
+	}
+}
+
+// Next 实现 remotecommand.TerminalSizeQueue, 会话结束时返回nil
+func (s *Session) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-s.sizeCh:
+		return &size
+	case <-s.done:
+		return nil
+	}
+}
+
+func (s *Session) appendTranscript(data string) {
+	if !s.record {
+		return
+	}
+	s.transcriptMu.Lock()
+	defer s.transcriptMu.Unlock()
+	if len(s.transcript) < maxTranscript {
+		s.transcript = append(s.transcript, data...)
+	}
+}
+
+// Done 会话结束(客户端断开或调用Close)时关闭
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Transcript 记录的用户输入
+func (s *Session) Transcript() string {
+	s.transcriptMu.Lock()
+	defer s.transcriptMu.Unlock()
+	return string(s.transcript)
+}
+
+// Close 发送关闭原因并关闭连接, 可以重复调用
+func (s *Session) Close(reason ...string) {
+	s.once.Do(func() {
+		close(s.done)
+		msg := ""
+		if len(reason) > 0 {
+			msg = reason[0]
+		}
+		if len(msg) > maxCloseReason {
+			// close帧的原因必须是合法的UTF-8, 否则浏览器会认为连接异常
+			msg = strings.ToValidUTF8(msg[:maxCloseReason], "")
+		}
+		s.writeMu.Lock()
+		_ = s.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, msg), time.Now().Add(writeWait))
+		s.writeMu.Unlock()
+		_ = s.conn.Close()
+	})
+}
@@ -0,0 +1,72 @@
+package terminal
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	result := make(chan *Session, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		session := NewSession(conn, true)
+		buf := make([]byte, 3)
+		n, _ := io.ReadFull(session, buf)
+		_, _ = session.Write(buf[:n])
+		result <- session
+	}))
+	defer server.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	assert.NoError(t, client.WriteJSON(Message{Type: MessageResize, Cols: 120, Rows: 40}))
+	assert.NoError(t, client.WriteJSON(Message{Type: MessageStdin, Data: "ls"}))
+	assert.NoError(t, client.WriteJSON(Message{Type: MessageStdin, Data: "\r"}))
+
+	msgType, data, err := client.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, msgType)
+	assert.Equal(t, "ls\r", string(data))
+
+	session := <-result
+	size := session.Next()
+	assert.Equal(t, uint16(120), size.Width)
+	assert.Equal(t, uint16(40), size.Height)
+	assert.Equal(t, "ls\r", session.Transcript())
+
+	session.Close("bye")
+	assert.Nil(t, session.Next())
+	_, _, err = client.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+}
+
+func TestCheckOrigin(t *testing.T) {
+	newRequest := func(origin string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://k8sadmin.local:8082/api/v1/k8s/default/pod/default/web/exec", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return r
+	}
+	allowed := []string{"https://console.example.com/"}
+
+	assert.True(t, CheckOrigin(newRequest(""), nil))
+	assert.True(t, CheckOrigin(newRequest("http://K8sadmin.local:8082"), nil))
+	assert.True(t, CheckOrigin(newRequest("https://console.example.com"), allowed))
+	assert.False(t, CheckOrigin(newRequest("https://console.example.com"), nil))
+	assert.False(t, CheckOrigin(newRequest("https://evil.example.com"), allowed))
+	assert.False(t, CheckOrigin(newRequest("http://k8sadmin.local"), nil))
+	assert.False(t, CheckOrigin(newRequest("null"), allowed))
+}
@@ -7,9 +7,9 @@ import (
 
 func initDaemonSetRouter(g *gin.RouterGroup) {
 	deployApiGroup := resouces.NewDaemonsethandler()
-	g.POST("/daemonset", deployApiGroup.CreateOrUpdateDaemonset)
-	g.POST("/daemonset/:namespace:/:name", deployApiGroup.GetDaemonsetDetail)
-	g.POST("/daemonset/:namespace:", deployApiGroup.GetDaemonsetList)
-	g.POST("/daemonset/:namespace:", deployApiGroup.DeleteDaemonset)
+	g.POST("/daemonset", deployApiGroup.CreateOrUpdateDaemonset)            // [post] /api/v1/k8s/:cluster/daemonset
+	g.GET("/daemonset/:namespace/:name", deployApiGroup.GetDaemonsetDetail) // [get] /api/v1/k8s/:cluster/daemonset/:namespace/:name
+	g.GET("/daemonset/:namespace", deployApiGroup.GetDaemonsetList)         // [get] /api/v1/k8s/:cluster/daemonset/:namespace
+	g.DELETE("/daemonset/:namespace/:name", deployApiGroup.DeleteDaemonset) // [delete] /api/v1/k8s/:cluster/daemonset/:namespace/:name
 
 }
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/xiaofan193/k8sadmin/internal/handler"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+)
+
+func init() {
+	ApiV1RouterFns = append(ApiV1RouterFns, func(group *gin.RouterGroup) {
+		auditRouter(group, handler.NewAuditLogHandler())
+	})
+}
+
+func auditRouter(group *gin.RouterGroup, h handler.AuditLogHandler) {
+	g := group.Group("/audit")
+
+	// All the following routes use jwt authentication and are only for administrators
+	g.Use(auth.Middleware(), permission.RequireAdmin())
+
+	g.GET("/list", h.List)     // [get] /api/v1/audit/list
+	g.GET("/export", h.Export) // [get] /api/v1/audit/export
+}
@@ -0,0 +1,23 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/xiaofan193/k8sadmin/internal/handler"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+)
+
+func init() {
+	ApiV1RouterFns = append(ApiV1RouterFns, func(group *gin.RouterGroup) {
+		authRouter(group, handler.NewAuthHandler())
+	})
+}
+
+func authRouter(group *gin.RouterGroup, h handler.AuthHandler) {
+	g := group.Group("/auth")
+
+	g.POST("/login", h.Login)                                        // [post] /api/v1/auth/login
+	g.POST("/refresh", h.Refresh)                                    // [post] /api/v1/auth/refresh
+	g.POST("/logout", auth.PasswordMiddleware(), h.Logout)           // [post] /api/v1/auth/logout
+	g.POST("/password", auth.PasswordMiddleware(), h.ChangePassword) // [post] /api/v1/auth/password
+}
@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/xiaofan193/k8sadmin/internal/handler"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+)
+
+func init() {
+	ApiV1RouterFns = append(ApiV1RouterFns, func(group *gin.RouterGroup) {
+		clusterRouter(group, handler.NewClusterHandler())
+	})
+}
+
+func clusterRouter(group *gin.RouterGroup, h handler.ClusterHandler) {
+	g := group.Group("/cluster")
+
+	// All the following routes use jwt authentication and are only for administrators
+	g.Use(auth.Middleware(), permission.RequireAdmin())
+
+	g.POST("/", h.Create)          // [post] /api/v1/cluster
+	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/cluster/:id
+	g.GET("/list", h.List)         // [get] /api/v1/cluster/list
+	g.POST("/test", h.Test)        // [post] /api/v1/cluster/test
+	g.GET("/:id/test", h.TestByID) // [get] /api/v1/cluster/:id/test
+}
@@ -7,9 +7,16 @@ import (
 
 func initDeploymentRouter(g *gin.RouterGroup) {
 	deployApiGroup := resouces.NewDeploymentandler()
-	g.POST("/deployment", deployApiGroup.CreateOrUpdateDeployment)
-	g.POST("/deployment/:namespace:/:name", deployApiGroup.GetDeploymentDetail)
-	g.POST("/deployment/:namespace:", deployApiGroup.GetDeploymentList)
-	g.POST("/deployment/:namespace:", deployApiGroup.DeleteDeployment)
+	g.POST("/deployment", deployApiGroup.CreateOrUpdateDeployment)                          // [post] /api/v1/k8s/:cluster/deployment
+	g.GET("/deployment/:namespace/:name", deployApiGroup.GetDeploymentDetail)               // [get] /api/v1/k8s/:cluster/deployment/:namespace/:name
+	g.GET("/deployment/:namespace", deployApiGroup.GetDeploymentList)                       // [get] /api/v1/k8s/:cluster/deployment/:namespace
+	g.DELETE("/deployment/:namespace/:name", deployApiGroup.DeleteDeployment)               // [delete] /api/v1/k8s/:cluster/deployment/:namespace/:name
+	g.PUT("/deployment/:namespace/:name/scale", deployApiGroup.ScaleDeployment)             // [put] /api/v1/k8s/:cluster/deployment/:namespace/:name/scale
+	g.PUT("/deployment/:namespace/:name/restart", deployApiGroup.RestartDeployment)         // [put] /api/v1/k8s/:cluster/deployment/:namespace/:name/restart
+	g.PUT("/deployment/:namespace/:name/pause", deployApiGroup.PauseDeployment)             // [put] /api/v1/k8s/:cluster/deployment/:namespace/:name/pause
+	g.PUT("/deployment/:namespace/:name/resume", deployApiGroup.ResumeDeployment)           // [put] /api/v1/k8s/:cluster/deployment/:namespace/:name/resume
+	g.GET("/deployment/:namespace/:name/history", deployApiGroup.GetDeploymentHistory)      // [get] /api/v1/k8s/:cluster/deployment/:namespace/:name/history
+	g.PUT("/deployment/:namespace/:name/rollback", deployApiGroup.RollbackDeployment)       // [put] /api/v1/k8s/:cluster/deployment/:namespace/:name/rollback
+	g.GET("/deployment/:namespace/:name/status", deployApiGroup.GetDeploymentRolloutStatus) // [get] /api/v1/k8s/:cluster/deployment/:namespace/:name/status
 
 }
@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/xiaofan193/k8sadmin/internal/handler"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+)
+
+func init() {
+	ApiV1RouterFns = append(ApiV1RouterFns, func(group *gin.RouterGroup) {
+		harborRouter(group, handler.NewHarborHandler())
+	})
+}
+
+func harborRouter(group *gin.RouterGroup, h handler.HarborHandler) {
+	g := group.Group("/harbor")
+
+	// All the following routes use jwt authentication
+	g.Use(auth.Middleware())
+
+	g.GET("/project", h.ListProjects)                               // [get] /api/v1/harbor/project
+	g.GET("/project/:project/repository", h.ListRepositories)       // [get] /api/v1/harbor/project/:project/repository
+	g.GET("/project/:project/artifact", h.ListArtifacts)            // [get] /api/v1/harbor/project/:project/artifact
+	g.GET("/project/:project/vulnerability", h.ListVulnerabilities) // [get] /api/v1/harbor/project/:project/vulnerability
+}
@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/xiaofan193/k8sadmin/internal/handler"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+)
+
+func init() {
+	ApiV1RouterFns = append(ApiV1RouterFns, func(group *gin.RouterGroup) {
+		impersonationRouter(group, handler.NewImpersonationHandler())
+	})
+}
+
+func impersonationRouter(group *gin.RouterGroup, h handler.ImpersonationHandler) {
+	g := group.Group("/impersonation")
+
+	// All the following routes use jwt authentication and are only for administrators
+	g.Use(auth.Middleware(), permission.RequireAdmin())
+
+	g.POST("/", h.Create)          // [post] /api/v1/impersonation
+	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/impersonation/:id
+	g.GET("/list", h.List)         // [get] /api/v1/impersonation/list
+}
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xiaofan193/k8sadmin/internal/handler/resouces"
+)
+
+func initJobRouter(g *gin.RouterGroup) {
+	jh := resouces.NewJobHandler()
+	g.POST("/job", jh.CreateOrUpdateJob)               // [post] /api/v1/k8s/:cluster/job
+	g.GET("/job/:namespace/:name", jh.GetJobDetail)    // [get] /api/v1/k8s/:cluster/job/:namespace/:name
+	g.GET("/job/:namespace/:name/pods", jh.GetJobPods) // [get] /api/v1/k8s/:cluster/job/:namespace/:name/pods
+	g.GET("/job/:namespace", jh.GetJobList)            // [get] /api/v1/k8s/:cluster/job/:namespace
+	g.DELETE("/job/:namespace/:name", jh.DeleteJob)    // [delete] /api/v1/k8s/:cluster/job/:namespace/:name
+
+	ch := resouces.NewCronJobHandler()
+	g.POST("/cronjob", ch.CreateOrUpdateCronJob)                   // [post] /api/v1/k8s/:cluster/cronjob
+	g.GET("/cronjob/:namespace/:name", ch.GetCronJobDetail)        // [get] /api/v1/k8s/:cluster/cronjob/:namespace/:name
+	g.GET("/cronjob/:namespace", ch.GetCronJobList)                // [get] /api/v1/k8s/:cluster/cronjob/:namespace
+	g.DELETE("/cronjob/:namespace/:name", ch.DeleteCronJob)        // [delete] /api/v1/k8s/:cluster/cronjob/:namespace/:name
+	g.PUT("/cronjob/:namespace/:name/suspend", ch.SuspendCronJob)  // [put] /api/v1/k8s/:cluster/cronjob/:namespace/:name/suspend
+	g.PUT("/cronjob/:namespace/:name/resume", ch.ResumeCronJob)    // [put] /api/v1/k8s/:cluster/cronjob/:namespace/:name/resume
+	g.POST("/cronjob/:namespace/:name/trigger", ch.TriggerCronJob) // [post] /api/v1/k8s/:cluster/cronjob/:namespace/:name/trigger
+	g.GET("/cronjob/:namespace/:name/jobs", ch.GetCronJobHistory)  // [get] /api/v1/k8s/:cluster/cronjob/:namespace/:name/jobs
+}
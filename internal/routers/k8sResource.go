@@ -3,6 +3,11 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/xiaofan193/k8sadmin/internal/handler/resouces"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/audit"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/impersonation"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
 )
 
 func init() {
@@ -12,59 +17,113 @@ func init() {
 }
 
 func kubernetsResoucesRouter(group *gin.RouterGroup) {
-	g := group.Group("/k8s")
+	// All the following routes target the cluster selected by the :cluster path parameter,
+	// the cluster loaded from the local kubeconfig is registered as "default".
+	g := group.Group("/k8s/:cluster")
 
-	// All the following routes use jwt authentication, you also can use middleware.Auth(middleware.WithExtraVerify(fn))
-	//g.Use(middleware.Auth())
+	// All the following routes use jwt authentication, authenticate before selecting the cluster.
+	// websocket and SSE requests can not set headers, pass the access token by the token query parameter.
+	g.Use(auth.Middleware())
+	// 记录变更和exec操作, 在权限检查之前使用, 被拒绝的操作同样记录
+	g.Use(audit.Middleware(g.BasePath()))
+	// 按路由推导资源类型和动作, 检查当前用户在命名空间中的权限
+	g.Use(permission.Middleware(g.BasePath()))
+	g.Use(cluster.Select())
+	// 用户映射了k8s身份时以该身份访问集群, 由集群的RBAC鉴权
+	g.Use(impersonation.Middleware())
 
-	// If jwt authentication is not required for all routes, authentication middleware can be added
-	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
 	h := resouces.NewResourceHandler()
-	g.POST("/pod", h.CreateOrUpdatePod)            // [post] /api/v1/k8s/pod
-	g.GET("/pod/:namespace", h.GetPodList)         // [get] /api/v1/k8s/pod/:namespace
-	g.GET("/pod/:namespace/:name", h.GetPodDetail) // [get] /api/v1/k8s/pod/:namespace
-	g.DELETE("/pod/:namespace/:name", h.DeletePod) // [delete] /api/v1/k8s/pod/:namepace/:name
-	g.GET("/namespace", h.GetNamespaceList)        // [post] /api/v1/k8s/namespace
+	g.POST("/pod", h.CreateOrUpdatePod)                                 // [post] /api/v1/k8s/:cluster/pod
+	g.GET("/pod/:namespace", h.GetPodList)                              // [get] /api/v1/k8s/:cluster/pod/:namespace
+	g.GET("/pod/:namespace/:name", h.GetPodDetail)                      // [get] /api/v1/k8s/:cluster/pod/:namespace
+	g.DELETE("/pod/:namespace/:name", h.DeletePod)                      // [delete] /api/v1/k8s/:cluster/pod/:namepace/:name
+	g.GET("/pod/:namespace/:name/logs", h.GetPodLogs)                   // [get] /api/v1/k8s/:cluster/pod/:namespace/:name/logs
+	g.GET("/pod/:namespace/:name/exec", resouces.NewExecHandler().Exec) // [get] /api/v1/k8s/:cluster/pod/:namespace/:name/exec
+	g.GET("/namespace", h.GetNamespaceList)                             // [post] /api/v1/k8s/:cluster/namespace
+
+	// 当前用户的权限
+	g.POST("/permission/check", resouces.NewPermissionHandler().CheckPermission) // [post] /api/v1/k8s/:cluster/permission/check
+
+	// 任意资源的YAML/JSON
+	g.POST("/apply", resouces.NewApplyHandler().Apply) // [post] /api/v1/k8s/:cluster/apply
+
+	// 按pod安全标准检查pod定义, 不提交
+	g.POST("/lint/pod", resouces.NewLintHandler().LintPod) // [post] /api/v1/k8s/:cluster/lint/pod
 
 	// node调度
 
 	nh := resouces.NewNodeHandler()
-	g.GET("/node", nh.GetNodeList)           // [get] /api/v1/k8s/node
-	g.GET("/node/:name", nh.GetNodeDetail)   // [get] /api/v1/k8s/node/:name
-	g.PUT("/node/label", nh.UpdateNodeLabel) // [put] /api/v1/k8s/node/label
-	g.PUT("/node/taint", nh.UpdateNodeTaint) // [put] /api/v1/k8s/node/taint
+	g.GET("/node", nh.GetNodeList)                 // [get] /api/v1/k8s/:cluster/node
+	g.GET("/node/:name", nh.GetNodeDetail)         // [get] /api/v1/k8s/:cluster/node/:name
+	g.PUT("/node/label", nh.UpdateNodeLabel)       // [put] /api/v1/k8s/:cluster/node/label
+	g.PUT("/node/taint", nh.UpdateNodeTaint)       // [put] /api/v1/k8s/:cluster/node/taint
+	g.PUT("/node/:name/cordon", nh.CordonNode)     // [put] /api/v1/k8s/:cluster/node/:name/cordon
+	g.PUT("/node/:name/uncordon", nh.UncordonNode) // [put] /api/v1/k8s/:cluster/node/:name/uncordon
+	g.POST("/node/:name/drain", nh.DrainNode)      // [post] /api/v1/k8s/:cluster/node/:name/drain
+	g.GET("/node/:name/drain", nh.GetDrainTask)    // [get] /api/v1/k8s/:cluster/node/:name/drain
+	g.DELETE("/node/:name/drain", nh.CancelDrain)  // [delete] /api/v1/k8s/:cluster/node/:name/drain
 
 	// ConfigMap
 	cm := resouces.NewConfigMapHandler()
-	g.POST("/configmap", cm.CreateOrUpdateConfigMap)            // [post] /api/v1/k8s/configmap
-	g.GET("/configmap/:namespace", cm.GetConfigMapList)         // [get] /api/v1/k8s/configmap/:namespace
-	g.GET("/configmap/:namespace/:name", cm.GetConfigMapDetail) // [get] /api/v1/k8s/configmap/:namespace/:name
-	g.DELETE("/configmap/:namespace/:name", cm.DeleteConfigMap) // [delete] /api/v1/k8s/configmap/:namespace/:name
+	g.POST("/configmap", cm.CreateOrUpdateConfigMap)            // [post] /api/v1/k8s/:cluster/configmap
+	g.GET("/configmap/:namespace", cm.GetConfigMapList)         // [get] /api/v1/k8s/:cluster/configmap/:namespace
+	g.GET("/configmap/:namespace/:name", cm.GetConfigMapDetail) // [get] /api/v1/k8s/:cluster/configmap/:namespace/:name
+	g.DELETE("/configmap/:namespace/:name", cm.DeleteConfigMap) // [delete] /api/v1/k8s/:cluster/configmap/:namespace/:name
 
 	//  Secret
 	sh := resouces.NewSecretHandler()
-	g.POST("/secret", sh.CreateOrUpdateSecret)            // [post] /api/v1/k8s/secret
-	g.GET("/secret/:namespace", sh.GetSecretList)         // [get] /api/v1/k8s/secret/:namespace
-	g.GET("/secret/:namespace/:name", sh.GetSecretDetail) // [get] /api/v1/k8s/secret/:namespace/:name
-	g.DELETE("/secret/:namespace/:name", sh.DeleteSecret) // [delete] /api/v1/k8s/secret/:namespace/:name
+	g.POST("/secret", sh.CreateOrUpdateSecret)            // [post] /api/v1/k8s/:cluster/secret
+	g.GET("/secret/:namespace", sh.GetSecretList)         // [get] /api/v1/k8s/:cluster/secret/:namespace
+	g.GET("/secret/:namespace/:name", sh.GetSecretDetail) // [get] /api/v1/k8s/:cluster/secret/:namespace/:name
+	g.DELETE("/secret/:namespace/:name", sh.DeleteSecret) // [delete] /api/v1/k8s/:cluster/secret/:namespace/:name
 	// pv
 	pv := resouces.NewPvHandler()
-	g.POST("/pv", pv.CreatePv)         // [post] /api/v1/k8s/pv
-	g.GET("/pv/list", pv.GetPvList)    // [post] /api/v1/k8s/pv/list
-	g.DELETE("/pv/:name", pv.DeletePV) // [post] /api/v1/k8s/pv/:name
+	g.POST("/pv", pv.CreatePv)         // [post] /api/v1/k8s/:cluster/pv
+	g.GET("/pv/list", pv.GetPvList)    // [post] /api/v1/k8s/:cluster/pv/list
+	g.DELETE("/pv/:name", pv.DeletePV) // [post] /api/v1/k8s/:cluster/pv/:name
 	// pvc
-	g.POST("pvc", pv.CreatePVC)                     // [post] /api/v1/k8s/pvc
-	g.GET("/pvc/:namespace", pv.GetPVCList)         // [get] /api/v1/k8s/pvc/:namespace
-	g.DELETE("/pvc/:namespace/:name", pv.DeletePVC) // [post] /api/v1/k8s/pvc/:namespace/:name
+	g.POST("pvc", pv.CreatePVC)                     // [post] /api/v1/k8s/:cluster/pvc
+	g.GET("/pvc/:namespace", pv.GetPVCList)         // [get] /api/v1/k8s/:cluster/pvc/:namespace
+	g.DELETE("/pvc/:namespace/:name", pv.DeletePVC) // [post] /api/v1/k8s/:cluster/pvc/:namespace/:name
 
 	// StorageClass
-	g.POST("/sc", pv.CreateSC)         // [post] /api/v1/k8s/sc
-	g.GET("/sc/list", pv.GetSCList)    // [get] /api/v1/k8s/sc/list
-	g.DELETE("/sc/:name", pv.DeleteSC) // [delete] /api/v1/k8s/sc/:name
+	g.POST("/sc", pv.CreateSC)         // [post] /api/v1/k8s/:cluster/sc
+	g.GET("/sc/list", pv.GetSCList)    // [get] /api/v1/k8s/:cluster/sc/list
+	g.DELETE("/sc/:name", pv.DeleteSC) // [delete] /api/v1/k8s/:cluster/sc/:name
+
+	// pod调度优先级
+	g.GET("/priorityclass/list", resouces.NewPriorityClassHandler().GetPriorityClassList) // [get] /api/v1/k8s/:cluster/priorityclass/list
+
+	// 导出去掉服务端字段后可以重新apply的YAML
+	ex := resouces.NewExportHandler()
+	g.GET("/pod/:namespace/:name/yaml", ex.GetYAML("pod"))                 // [get] /api/v1/k8s/:cluster/pod/:namespace/:name/yaml
+	g.GET("/deployment/:namespace/:name/yaml", ex.GetYAML("deployment"))   // [get] /api/v1/k8s/:cluster/deployment/:namespace/:name/yaml
+	g.GET("/daemonset/:namespace/:name/yaml", ex.GetYAML("daemonset"))     // [get] /api/v1/k8s/:cluster/daemonset/:namespace/:name/yaml
+	g.GET("/statefulset/:namespace/:name/yaml", ex.GetYAML("statefulset")) // [get] /api/v1/k8s/:cluster/statefulset/:namespace/:name/yaml
+	g.GET("/job/:namespace/:name/yaml", ex.GetYAML("job"))                 // [get] /api/v1/k8s/:cluster/job/:namespace/:name/yaml
+	g.GET("/cronjob/:namespace/:name/yaml", ex.GetYAML("cronjob"))         // [get] /api/v1/k8s/:cluster/cronjob/:namespace/:name/yaml
+	g.GET("/svc/:namespace/:name/yaml", ex.GetYAML("svc"))                 // [get] /api/v1/k8s/:cluster/svc/:namespace/:name/yaml
+	g.GET("/ingress/:namespace/:name/yaml", ex.GetYAML("ingress"))         // [get] /api/v1/k8s/:cluster/ingress/:namespace/:name/yaml
+	g.GET("/ingroute/:namespace/:name/yaml", ex.GetYAML("ingroute"))       // [get] /api/v1/k8s/:cluster/ingroute/:namespace/:name/yaml
+	g.GET("/configmap/:namespace/:name/yaml", ex.GetYAML("configmap"))     // [get] /api/v1/k8s/:cluster/configmap/:namespace/:name/yaml
+	g.GET("/secret/:namespace/:name/yaml", ex.GetYAML("secret"))           // [get] /api/v1/k8s/:cluster/secret/:namespace/:name/yaml
+	g.GET("/sa/:namespace/:name/yaml", ex.GetYAML("sa"))                   // [get] /api/v1/k8s/:cluster/sa/:namespace/:name/yaml
+	g.GET("/pvc/:namespace/:name/yaml", ex.GetYAML("pvc"))                 // [get] /api/v1/k8s/:cluster/pvc/:namespace/:name/yaml
+	g.GET("/role/:namespace/:name/yaml", ex.GetYAML("role"))               // [get] /api/v1/k8s/:cluster/role/:namespace/:name/yaml
+	g.GET("/role/binding/:name/yaml", ex.GetYAML("rolebinding"))           // [get] /api/v1/k8s/:cluster/role/binding/:name/yaml?namespace=
+	g.GET("/node/:name/yaml", ex.GetYAML("node"))                          // [get] /api/v1/k8s/:cluster/node/:name/yaml
+	g.GET("/pv/:name/yaml", ex.GetYAML("pv"))                              // [get] /api/v1/k8s/:cluster/pv/:name/yaml
+	g.GET("/sc/:name/yaml", ex.GetYAML("sc"))                              // [get] /api/v1/k8s/:cluster/sc/:name/yaml
+	g.GET("/export/:namespace", ex.ExportNamespace)                        // [get] /api/v1/k8s/:cluster/export/:namespace
 
 	initRBACRouter(g)
 	initSvcRouter(g)
 	initIngressRouter(g)
 	initDeploymentRouter(g)
+	initDaemonSetRouter(g)
+	initStatefulSetRouter(g)
+	initJobRouter(g)
+	initMetricsRouter(g)
+	initWatchRouter(g)
 
 }
@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xiaofan193/k8sadmin/internal/handler/resouces"
+)
+
+func initMetricsRouter(g *gin.RouterGroup) {
+	mh := resouces.NewMetricsHandler()
+	g.GET("/metrics/pod/:namespace/:name", mh.GetPodMetrics)               // [get] /api/v1/k8s/:cluster/metrics/pod/:namespace/:name
+	g.GET("/metrics/deployment/:namespace/:name", mh.GetDeploymentMetrics) // [get] /api/v1/k8s/:cluster/metrics/deployment/:namespace/:name
+	g.GET("/metrics/node/:name", mh.GetNodeMetrics)                        // [get] /api/v1/k8s/:cluster/metrics/node/:name
+	g.GET("/metrics/namespace/:namespace", mh.GetNamespaceMetrics)         // [get] /api/v1/k8s/:cluster/metrics/namespace/:namespace
+}
@@ -0,0 +1,36 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/xiaofan193/k8sadmin/internal/handler"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+)
+
+func init() {
+	ApiV1RouterFns = append(ApiV1RouterFns, func(group *gin.RouterGroup) {
+		permissionRouter(group, handler.NewPermissionHandler())
+	})
+}
+
+func permissionRouter(group *gin.RouterGroup, h handler.PermissionHandler) {
+	g := group.Group("/permission")
+
+	// All the following routes use jwt authentication and are only for administrators
+	g.Use(auth.Middleware(), permission.RequireAdmin())
+
+	g.POST("/role", h.CreateRole)       // [post] /api/v1/permission/role
+	g.PUT("/role/:id", h.UpdateRole)    // [put] /api/v1/permission/role/:id
+	g.DELETE("/role/:id", h.DeleteRole) // [delete] /api/v1/permission/role/:id
+	g.GET("/role/list", h.ListRoles)    // [get] /api/v1/permission/role/list
+
+	g.POST("/team", h.CreateTeam)                // [post] /api/v1/permission/team
+	g.DELETE("/team/:id", h.DeleteTeam)          // [delete] /api/v1/permission/team/:id
+	g.PUT("/team/:id/members", h.SetTeamMembers) // [put] /api/v1/permission/team/:id/members
+	g.GET("/team/list", h.ListTeams)             // [get] /api/v1/permission/team/list
+
+	g.POST("/binding", h.CreateBinding)       // [post] /api/v1/permission/binding
+	g.DELETE("/binding/:id", h.DeleteBinding) // [delete] /api/v1/permission/binding/:id
+	g.GET("/binding/list", h.ListBindings)    // [get] /api/v1/permission/binding/list
+}
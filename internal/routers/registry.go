@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/xiaofan193/k8sadmin/internal/handler"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
+)
+
+func init() {
+	ApiV1RouterFns = append(ApiV1RouterFns, func(group *gin.RouterGroup) {
+		registryRouter(group, handler.NewRegistryHandler())
+	})
+}
+
+func registryRouter(group *gin.RouterGroup, h handler.RegistryHandler) {
+	g := group.Group("/registry")
+
+	// All the following routes use jwt authentication and are only for administrators
+	g.Use(auth.Middleware(), permission.RequireAdmin())
+
+	g.POST("/", h.Create)          // [post] /api/v1/registry
+	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/registry/:id
+	g.PUT("/:id", h.UpdateByID)    // [put] /api/v1/registry/:id
+	g.GET("/list", h.List)         // [get] /api/v1/registry/list
+	g.POST("/sync", h.Sync)        // [post] /api/v1/registry/sync
+}
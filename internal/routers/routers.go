@@ -4,6 +4,7 @@ package routers
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/xiaofan193/k8sadmin/docs"
 	"github.com/xiaofan193/k8sadmin/internal/config"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/cluster"
 )
 
 var (
@@ -44,11 +46,11 @@ func NewRouter() *gin.Engine {
 	r.Use(middleware.RequestID())
 
 	// logger middleware, to print simple messages, replace middleware.Logging with middleware.SimpleLog
-	r.Use(middleware.Logging(
+	r.Use(skipStream(middleware.Logging(
 		middleware.WithLog(logger.Get()),
 		middleware.WithRequestIDFromContext(),
 		middleware.WithIgnoreRoutes("/metrics"), // ignore path
-	))
+	)))
 
 	// metrics middleware
 	if config.Get().App.EnableMetrics {
@@ -80,6 +82,7 @@ func NewRouter() *gin.Engine {
 
 	r.GET("/health", handlerfunc.CheckHealth)
 	r.GET("/ping", handlerfunc.Ping)
+	r.GET("/ready", cluster.CheckReady) // informer cache readiness
 	r.GET("/codes", handlerfunc.ListCodes)
 
 	if config.Get().App.Env != "prod" {
@@ -99,6 +102,23 @@ func NewRouter() *gin.Engine {
 	return r
 }
 
+// skipStream 日志中间件会缓存完整的响应体, 长连接(SSE、websocket)和pod日志的请求不经过该中间件
+func skipStream(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isStreamRequest(c.Request) {
+			c.Next()
+			return
+		}
+		handler(c)
+	}
+}
+
+func isStreamRequest(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream") ||
+		strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.HasSuffix(r.URL.Path, "/logs")
+}
+
 func registerRouters(r *gin.Engine, groupPath string, routerFns []func(*gin.RouterGroup), handlers ...gin.HandlerFunc) {
 	rg := r.Group(groupPath, handlers...)
 	for _, fn := range routerFns {
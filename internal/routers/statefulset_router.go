@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xiaofan193/k8sadmin/internal/handler/resouces"
+)
+
+func initStatefulSetRouter(g *gin.RouterGroup) {
+	sh := resouces.NewStatefulSetHandler()
+	g.POST("/statefulset", sh.CreateOrUpdateStatefulSet)            // [post] /api/v1/k8s/:cluster/statefulset
+	g.GET("/statefulset/:namespace/:name", sh.GetStatefulSetDetail) // [get] /api/v1/k8s/:cluster/statefulset/:namespace/:name
+	g.GET("/statefulset/:namespace", sh.GetStatefulSetList)         // [get] /api/v1/k8s/:cluster/statefulset/:namespace
+	g.DELETE("/statefulset/:namespace/:name", sh.DeleteStatefulSet) // [delete] /api/v1/k8s/:cluster/statefulset/:namespace/:name
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/xiaofan193/k8sadmin/internal/handler"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/auth"
+	"github.com/xiaofan193/k8sadmin/internal/pkg/permission"
 )
 
 func init() {
@@ -15,11 +17,8 @@ func init() {
 func userRouter(group *gin.RouterGroup, h handler.UserHandler) {
 	g := group.Group("/user")
 
-	// All the following routes use jwt authentication, you also can use middleware.Auth(middleware.WithExtraVerify(fn))
-	//g.Use(middleware.Auth())
-
-	// If jwt authentication is not required for all routes, authentication middleware can be added
-	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
+	// All the following routes use jwt authentication and are only for administrators
+	g.Use(auth.Middleware(), permission.RequireAdmin())
 
 	g.POST("/", h.Create)          // [post] /api/v1/user
 	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/user/:id
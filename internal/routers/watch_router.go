@@ -0,0 +1,12 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xiaofan193/k8sadmin/internal/handler/resouces"
+)
+
+func initWatchRouter(g *gin.RouterGroup) {
+	wh := resouces.NewWatchHandler()
+	g.GET("/watch/:kind", wh.Watch)            // [get] /api/v1/k8s/:cluster/watch/:kind
+	g.GET("/watch/:kind/:namespace", wh.Watch) // [get] /api/v1/k8s/:cluster/watch/:kind/:namespace
+}
@@ -0,0 +1,35 @@
+package types
+
+const (
+	ApplyResultCreated    = "created"
+	ApplyResultConfigured = "configured"
+	ApplyResultUnchanged  = "unchanged"
+	ApplyResultFailed     = "failed"
+)
+
+// ApplyRequest 请求体为多文档YAML或JSON, 参数通过查询参数传递
+type ApplyRequest struct {
+	Namespace string `form:"namespace"` // 命名空间级资源没有指定命名空间时使用, 默认为 default
+	DryRun    bool   `form:"dryRun"`    // 只校验, 不保存
+	Force     bool   `form:"force"`     // 字段被其他manager管理时强制接管
+}
+
+// ApplyResult 每个对象的执行结果
+type ApplyResult struct {
+	Index      int    `json:"index"` // 对象在请求中的序号, 从0开始
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Result     string `json:"result"` // created | configured | unchanged | failed, dryRun 时已存在的对象总是 configured
+	Error      string `json:"error,omitempty"`
+}
+
+type ApplyReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		DryRun bool           `json:"dryRun"`
+		List   []*ApplyResult `json:"list"`
+	} `json:"data"` // return data
+}
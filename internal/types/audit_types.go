@@ -0,0 +1,65 @@
+package types
+
+import (
+	"time"
+)
+
+// AuditLogFilter 审计日志的查询条件, 空值表示不过滤, 时间戳单位为秒
+type AuditLogFilter struct {
+	Username  string `form:"username"`
+	Cluster   string `form:"cluster"`
+	Namespace string `form:"namespace"`
+	Kind      string `form:"kind"` // 与路由中的资源名称一致, 如 pod、deployment
+	Name      string `form:"name"`
+	Verb      string `form:"verb" binding:"omitempty,oneof=edit delete exec"`
+	Result    string `form:"result" binding:"omitempty,oneof=success failure"`
+	RequestID string `form:"requestID"`
+	Start     int64  `form:"start" binding:"min=0"` // 开始时间(包含)
+	End       int64  `form:"end" binding:"min=0"`   // 结束时间(不包含)
+}
+
+// ListAuditLogsRequest request params, 按时间倒序
+type ListAuditLogsRequest struct {
+	AuditLogFilter
+	Page  int `form:"page" binding:"omitempty,min=1"`           // 页码, 从1开始
+	Limit int `form:"limit" binding:"omitempty,min=1,max=1000"` // 每页数量, 默认20
+}
+
+// ExportAuditLogsRequest request params, 最多导出最近的10000条
+type ExportAuditLogsRequest struct {
+	AuditLogFilter
+	Format string `form:"format" binding:"omitempty,oneof=csv json"` // 默认csv
+}
+
+// AuditLogObjDetail detail
+type AuditLogObjDetail struct {
+	ID         uint64     `json:"id"`
+	RequestID  string     `json:"requestID"`
+	UserID     uint64     `json:"userID"`
+	Username   string     `json:"username"`
+	Cluster    string     `json:"cluster"`
+	Namespace  string     `json:"namespace"`
+	Kind       string     `json:"kind"`
+	Name       string     `json:"name"`
+	Verb       string     `json:"verb"`
+	Method     string     `json:"method"`
+	Path       string     `json:"path"`
+	ClientIP   string     `json:"clientIP"`
+	Body       string     `json:"body"` // 敏感字段已脱敏
+	Result     string     `json:"result"`
+	StatusCode int        `json:"statusCode"`
+	Code       int        `json:"code"`
+	Message    string     `json:"message"`
+	Latency    int64      `json:"latency"` // 毫秒
+	CreatedAt  *time.Time `json:"createdAt"`
+}
+
+// ListAuditLogsReply only for api docs
+type ListAuditLogsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		AuditLogs []AuditLogObjDetail `json:"auditLogs"`
+		Total     int64               `json:"total"`
+	} `json:"data"` // return data
+}
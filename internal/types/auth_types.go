@@ -0,0 +1,42 @@
+package types
+
+// LoginRequest 用户名密码登录
+type LoginRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshTokenRequest 用 refresh token 换取新的 access token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// ChangePasswordRequest 修改当前用户的密码
+type ChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=6,max=72,nefield=OldPassword"`
+}
+
+// TokenObj 请求时在 Authorization 请求头中携带 "Bearer <accessToken>"
+type TokenObj struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresAt    int64  `json:"expiresAt"` // access token 过期时间戳(秒)
+}
+
+// LoginReply only for api docs
+type LoginReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		TokenObj
+		User UserObjDetail `json:"user"`
+	} `json:"data"` // return data
+}
+
+// RefreshTokenReply only for api docs
+type RefreshTokenReply struct {
+	Code int      `json:"code"` // return code
+	Msg  string   `json:"msg"`  // return information description
+	Data TokenObj `json:"data"` // return data
+}
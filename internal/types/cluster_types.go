@@ -0,0 +1,75 @@
+package types
+
+import (
+	"time"
+)
+
+// CreateClusterRequest request params
+type CreateClusterRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description" binding:""`
+	// kubeconfig | token
+	AuthType string `json:"authType" binding:"required,oneof=kubeconfig token"`
+	// authType=kubeconfig 时必填, kubeconfig 文件内容
+	KubeConfig string `json:"kubeConfig" binding:""`
+	// authType=token 时必填, apiserver 地址, 如 https://192.168.1.10:6443
+	Server string `json:"server" binding:""`
+	Token  string `json:"token" binding:""`
+	// apiserver CA 证书(PEM), 为空时需要开启 insecure
+	CAData   string `json:"caData" binding:""`
+	Insecure bool   `json:"insecure" binding:""`
+}
+
+// ClusterObjDetail detail, credentials are never returned
+type ClusterObjDetail struct {
+	ID uint64 `json:"id"` // convert to uint64 id
+
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	AuthType    string     `json:"authType"`
+	Server      string     `json:"server"`
+	Connected   bool       `json:"connected"`   // whether the cluster is registered in the running server
+	CacheSynced bool       `json:"cacheSynced"` // whether the informer cache has finished the initial sync
+	CreatedAt   *time.Time `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+}
+
+// ClusterVersion connectivity test result
+type ClusterVersion struct {
+	GitVersion string `json:"gitVersion"`
+	Platform   string `json:"platform"`
+}
+
+// CreateClusterReply only for api docs
+type CreateClusterReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID uint64 `json:"id"` // id
+	} `json:"data"` // return data
+}
+
+// DeleteClusterByIDReply only for api docs
+type DeleteClusterByIDReply struct {
+	Code int      `json:"code"` // return code
+	Msg  string   `json:"msg"`  // return information description
+	Data struct{} `json:"data"` // return data
+}
+
+// ListClustersReply only for api docs
+type ListClustersReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Clusters []ClusterObjDetail `json:"clusters"`
+	} `json:"data"` // return data
+}
+
+// TestClusterReply only for api docs
+type TestClusterReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Version ClusterVersion `json:"version"`
+	} `json:"data"` // return data
+}
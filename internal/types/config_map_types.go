@@ -34,7 +34,15 @@ type CreateOrUpdateConfigMapReply struct {
 type GetConfigMapDetailORListRequest struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
-	Keyword   string `json:"keyword"`
+}
+
+type ListConfigMapReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*ConfigMapRes `json:"list"`
+		ListMeta
+	} `json:"data"` // return data
 }
 
 type DeleteConfigMapRequest struct {
@@ -41,5 +41,6 @@ type DaemonsetListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*DaemonSetRes
+		ListMeta
 	} `json:"data"` // return data
 }
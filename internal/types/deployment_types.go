@@ -39,5 +39,74 @@ type DeploymentListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*DeploymentRes
+		ListMeta
+	} `json:"data"` // return data
+}
+
+type DeploymentScaleRequest struct {
+	Replicas int32 `json:"replicas" binding:"min=0"`
+}
+
+// DeploymentRollbackRequest 回滚到指定版本, 0 表示上一个版本
+type DeploymentRollbackRequest struct {
+	Revision int64 `json:"revision" binding:"min=0"`
+}
+
+// TemplateChange 两个版本之间pod模板的变化, 值为json格式
+type TemplateChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// DeploymentRevision 由replicaset记录的历史版本
+type DeploymentRevision struct {
+	Revision    int64            `json:"revision"`
+	ReplicaSet  string           `json:"replicaSet"`
+	Images      []string         `json:"images"`
+	ChangeCause string           `json:"changeCause"` // kubernetes.io/change-cause 注解
+	Replicas    int32            `json:"replicas"`    // 该版本当前的副本数
+	Current     bool             `json:"current"`     // 是否是当前版本
+	Changes     []TemplateChange `json:"changes"`     // 相对上一个版本的变化, 最早的版本为空
+	Age         int64            `json:"age"`
+}
+
+// DeploymentCondition deployment 的状态条件
+type DeploymentCondition struct {
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason"`
+	Message        string `json:"message"`
+	LastUpdateTime int64  `json:"lastUpdateTime"`
+}
+
+// DeploymentRolloutStatus 发布进度, 与 kubectl rollout status 的判断方式相同
+type DeploymentRolloutStatus struct {
+	Revision    int64                 `json:"revision"`
+	Replicas    int32                 `json:"replicas"`    // 期望副本数
+	Updated     int32                 `json:"updated"`     // 已更新到最新版本的副本数
+	Ready       int32                 `json:"ready"`       // 就绪副本数
+	Available   int32                 `json:"available"`   // 可用副本数
+	Unavailable int32                 `json:"unavailable"` // 不可用副本数
+	Paused      bool                  `json:"paused"`
+	Complete    bool                  `json:"complete"` // 发布是否完成
+	Stuck       bool                  `json:"stuck"`    // 是否超过 progressDeadlineSeconds 仍未完成
+	Message     string                `json:"message"`
+	Conditions  []DeploymentCondition `json:"conditions"`
+}
+
+type DeploymentHistoryReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*DeploymentRevision
+	} `json:"data"` // return data
+}
+
+type DeploymentRolloutStatusReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Status *DeploymentRolloutStatus
 	} `json:"data"` // return data
 }
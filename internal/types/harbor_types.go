@@ -0,0 +1,109 @@
+package types
+
+// HarborListRequest harbor 列表接口的分页参数
+type HarborListRequest struct {
+	Page     int    `form:"page" json:"page" binding:"omitempty,min=1"`                 // 页码, 从1开始
+	PageSize int    `form:"pageSize" json:"pageSize" binding:"omitempty,min=1,max=100"` // 每页数量, 默认20
+	Name     string `form:"name" json:"name"`                                           // 名称模糊匹配
+}
+
+// HarborArtifactRequest repository 可以带项目前缀, 多级仓库名中包含 /, 所以通过query传递
+type HarborArtifactRequest struct {
+	HarborListRequest
+	Repository string `form:"repository" json:"repository" binding:"required"`
+}
+
+// HarborVulnerabilityRequest reference 为 tag 或 digest
+type HarborVulnerabilityRequest struct {
+	Repository string `form:"repository" json:"repository" binding:"required"`
+	Reference  string `form:"reference" json:"reference" binding:"required"`
+}
+
+type HarborProject struct {
+	ProjectId    int64  `json:"projectId"`
+	Name         string `json:"name"`
+	Public       bool   `json:"public"`
+	RepoCount    int64  `json:"repoCount"`
+	CreationTime int64  `json:"creationTime"`
+}
+
+type HarborRepository struct {
+	Name          string `json:"name"`     // 不带项目前缀的仓库名
+	FullName      string `json:"fullName"` // 带项目前缀的仓库名, 如 library/nginx
+	Description   string `json:"description"`
+	ArtifactCount int64  `json:"artifactCount"`
+	PullCount     int64  `json:"pullCount"`
+	UpdateTime    int64  `json:"updateTime"`
+}
+
+type HarborTag struct {
+	Name     string `json:"name"`
+	PushTime int64  `json:"pushTime"`
+	Image    string `json:"image"` // 完整镜像地址, 可以直接用于 Container.Image
+}
+
+// HarborScanSummary 漏洞扫描概要, Severities 为各级别的漏洞数, 如 Critical: 1
+type HarborScanSummary struct {
+	Status     string         `json:"status"`   // Pending | Running | Success | Error ...
+	Severity   string         `json:"severity"` // 最高的漏洞级别
+	Total      int            `json:"total"`
+	Fixable    int            `json:"fixable"`
+	Severities map[string]int `json:"severities"`
+	EndTime    int64          `json:"endTime"`
+}
+
+// HarborArtifact 一个镜像制品, 没有tag时只能通过 digest 引用
+type HarborArtifact struct {
+	Digest   string             `json:"digest"`
+	Size     int64              `json:"size"` // 单位: 字节
+	PushTime int64              `json:"pushTime"`
+	PullTime int64              `json:"pullTime"`
+	Image    string             `json:"image"` // digest 形式的镜像地址
+	Tags     []*HarborTag       `json:"tags"`
+	Scan     *HarborScanSummary `json:"scan"` // 没有扫描时为null
+}
+
+type HarborVulnerability struct {
+	ID          string   `json:"id"`
+	Package     string   `json:"package"`
+	Version     string   `json:"version"`
+	FixVersion  string   `json:"fixVersion"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	Links       []string `json:"links"`
+}
+
+type HarborProjectListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List  []*HarborProject `json:"list"`
+		Total int              `json:"total"`
+	} `json:"data"` // return data
+}
+
+type HarborRepositoryListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List  []*HarborRepository `json:"list"`
+		Total int                 `json:"total"`
+	} `json:"data"` // return data
+}
+
+type HarborArtifactListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List  []*HarborArtifact `json:"list"`
+		Total int               `json:"total"`
+	} `json:"data"` // return data
+}
+
+type HarborVulnerabilityListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*HarborVulnerability `json:"list"`
+	} `json:"data"` // return data
+}
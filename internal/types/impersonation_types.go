@@ -0,0 +1,49 @@
+package types
+
+import (
+	"time"
+)
+
+// ImpersonationServiceAccount 模拟的 serviceaccount
+type ImpersonationServiceAccount struct {
+	Namespace string `json:"namespace" binding:"required"`
+	Name      string `json:"name" binding:"required"`
+}
+
+// CreateImpersonationRequest 把用户映射为k8s用户/用户组或serviceaccount, username 和 serviceAccount 必须且只能设置一个
+// serviceaccount 的用户组由apiserver自动补充, 不能再指定 groups
+type CreateImpersonationRequest struct {
+	UserID         uint64                       `json:"userID" binding:"required"`
+	Cluster        string                       `json:"cluster" binding:"max=64"` // 默认为 *, 对所有集群生效
+	Username       string                       `json:"username" binding:"max=255"`
+	Groups         []string                     `json:"groups" binding:"dive,required"`
+	ServiceAccount *ImpersonationServiceAccount `json:"serviceAccount"`
+}
+
+// ImpersonationObjDetail detail
+type ImpersonationObjDetail struct {
+	ID        uint64     `json:"id"`
+	UserID    uint64     `json:"userID"`
+	Cluster   string     `json:"cluster"`
+	Username  string     `json:"username"`
+	Groups    []string   `json:"groups"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+// CreateImpersonationReply only for api docs
+type CreateImpersonationReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID uint64 `json:"id"` // id
+	} `json:"data"` // return data
+}
+
+// ListImpersonationsReply only for api docs
+type ListImpersonationsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Impersonations []ImpersonationObjDetail `json:"impersonations"`
+	} `json:"data"` // return data
+}
@@ -40,6 +40,7 @@ type IngressListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*IngressRes
+		types.ListMeta
 	} `json:"data"` // return data
 }
 
@@ -74,6 +75,12 @@ type IngressRoute struct {
 	Metadata        metav1.ObjectMeta `json:"metadata"`
 	Spec            IngressRouteSpec  `json:"spec"`
 }
+
+// GetObjectMeta 实现 metav1.ObjectMetaAccessor, 用于列表过滤和分页
+func (r *IngressRoute) GetObjectMeta() metav1.Object {
+	return &r.Metadata
+}
+
 type IngressRouteList struct {
 	Items           []IngressRoute `json:"items"`
 	metav1.TypeMeta `json:",inline"`
@@ -87,6 +94,11 @@ type Middleware struct {
 	Metadata        metav1.ObjectMeta `json:"metadata"`
 }
 
+// GetObjectMeta 实现 metav1.ObjectMetaAccessor, 用于列表过滤和分页
+func (m *Middleware) GetObjectMeta() metav1.Object {
+	return &m.Metadata
+}
+
 type MiddlewareList struct {
 	Items           []Middleware `json:"items"`
 	metav1.TypeMeta `json:",inline"`
@@ -116,6 +128,7 @@ type IngressRouteResListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		Data []*IngressRouteRes
+		types.ListMeta
 	} `json:"data"` // return data
 }
 
@@ -124,5 +137,6 @@ type IngressRouteMiddlewareListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		Data []string
+		types.ListMeta
 	} `json:"data"` // return data
 }
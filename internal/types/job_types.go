@@ -0,0 +1,161 @@
+package types
+
+// JobSpec job 和 cronjob 共用的执行参数
+type JobSpec struct {
+	Completions             int32  `json:"completions" binding:"min=0"` // 需要成功完成的pod数, 0 表示1
+	Parallelism             int32  `json:"parallelism" binding:"min=0"` // 同时运行的pod数, 0 表示1
+	BackoffLimit            *int32 `json:"backoffLimit"`                // 失败重试次数, 不传为6
+	ActiveDeadlineSeconds   *int64 `json:"activeDeadlineSeconds"`       // 最长运行时间(秒), 超时后job失败
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished"`     // 结束后多少秒自动删除, 不传表示不删除
+}
+
+type JobBase struct {
+	Name      string        `json:"name" binding:"required"`
+	Namespace string        `json:"namespace" binding:"required"`
+	Labels    []ListMapItem `json:"labels"`
+	JobSpec
+}
+
+// JobRequest 创建或更新job, 模板中的 restartPolicy 只能为 Never 或 OnFailure, 不传时为 Never
+// job 创建后只能修改 labels/parallelism/activeDeadlineSeconds/ttlSecondsAfterFinished
+type JobRequest struct {
+	Base     *JobBase `json:"base" binding:"required"`
+	Template *Pod     `json:"template" binding:"required"`
+}
+
+type JobResponse struct {
+	Base     *JobBase `json:"base"`
+	Template *Pod     `json:"template"`
+}
+
+// job 状态
+const (
+	JobStatusRunning   = "Running"
+	JobStatusComplete  = "Complete"
+	JobStatusFailed    = "Failed"
+	JobStatusSuspended = "Suspended"
+)
+
+type JobRes struct {
+	Name           string       `json:"name"`
+	Namespace      string       `json:"namespace"`
+	Status         string       `json:"status"` // Running | Complete | Failed | Suspended
+	Completions    int32        `json:"completions"`
+	Active         int32        `json:"active"`         // 运行中的pod数
+	Succeeded      int32        `json:"succeeded"`      // 成功的pod数
+	Failed         int32        `json:"failed"`         // 失败的pod数
+	CronJob        string       `json:"cronJob"`        // 所属的cronjob, 手动创建的job为空
+	Manual         bool         `json:"manual"`         // 是否是从cronjob手动触发的
+	StartTime      int64        `json:"startTime"`      // 开始时间, 未开始为0
+	CompletionTime int64        `json:"completionTime"` // 完成时间, 未完成为0
+	Age            int64        `json:"age"`
+	Pods           []*JobPodRes `json:"pods,omitempty"` // 执行记录中返回每个pod的结果
+}
+
+// JobPodRes job 中每个pod的执行结果
+type JobPodRes struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Node     string `json:"node"`
+	ExitCode int32  `json:"exitCode"` // 容器退出码, 未退出为0
+	Reason   string `json:"reason"`   // 退出原因, 如 Completed, Error, OOMKilled
+	Age      int64  `json:"age"`
+}
+
+type JobDetailReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Job *JobResponse
+	} `json:"data"` // return data
+}
+
+type JobListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*JobRes
+		ListMeta
+	} `json:"data"` // return data
+}
+
+type JobPodsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*JobPodRes
+	} `json:"data"` // return data
+}
+
+type CronJobBase struct {
+	Name      string        `json:"name" binding:"required"`
+	Namespace string        `json:"namespace" binding:"required"`
+	Labels    []ListMapItem `json:"labels"`
+	Schedule  string        `json:"schedule" binding:"required"` // cron 表达式, 如 */5 * * * *
+	TimeZone  string        `json:"timeZone"`                    // 时区, 如 Asia/Shanghai, 为空时使用 kube-controller-manager 的时区
+	// Allow | Forbid | Replace, 上一次还未结束时是否允许再次执行
+	ConcurrencyPolicy          string `json:"concurrencyPolicy" binding:"omitempty,oneof=Allow Forbid Replace"`
+	Suspend                    bool   `json:"suspend"`
+	StartingDeadlineSeconds    *int64 `json:"startingDeadlineSeconds"`
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit"` // 保留的成功job数, 不传为3
+	FailedJobsHistoryLimit     *int32 `json:"failedJobsHistoryLimit"`     // 保留的失败job数, 不传为1
+	JobSpec
+}
+
+type CronJobRequest struct {
+	Base     *CronJobBase `json:"base" binding:"required"`
+	Template *Pod         `json:"template" binding:"required"`
+}
+
+type CronJobResponse struct {
+	Base     *CronJobBase `json:"base"`
+	Template *Pod         `json:"template"`
+}
+
+type CronJobRes struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	Schedule           string `json:"schedule"`
+	Suspend            bool   `json:"suspend"`
+	Active             int    `json:"active"`             // 运行中的job数
+	Succeeded          int    `json:"succeeded"`          // 保留的执行记录中成功的job数
+	Failed             int    `json:"failed"`             // 保留的执行记录中失败的job数
+	LastScheduleTime   int64  `json:"lastScheduleTime"`   // 最近一次调度时间
+	LastSuccessfulTime int64  `json:"lastSuccessfulTime"` // 最近一次成功时间
+	Age                int64  `json:"age"`
+}
+
+type CronJobDetailReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CronJob *CronJobResponse
+	} `json:"data"` // return data
+}
+
+type CronJobListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*CronJobRes
+		ListMeta
+	} `json:"data"` // return data
+}
+
+// CronJobHistoryReply cronjob 的执行记录, 按创建时间倒序
+type CronJobHistoryReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*JobRes
+	} `json:"data"` // return data
+}
+
+// TriggerCronJobReply 手动触发创建的job
+type TriggerCronJobReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Name string `json:"name"`
+	} `json:"data"` // return data
+}
@@ -0,0 +1,19 @@
+package types
+
+// ListQuery 列表接口通用查询参数, 所有 /api/v1/k8s/:cluster/* 列表接口都支持
+// page/limit 与 continue 二选一, limit 为0时不分页
+type ListQuery struct {
+	Keyword       string `form:"keyword" json:"keyword"`                                // 名称模糊匹配
+	Page          int    `form:"page" json:"page" binding:"omitempty,min=1"`            // 页码, 从1开始
+	Limit         int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"` // 每页数量
+	Sort          string `form:"sort" json:"sort"`                                      // 排序, 格式 field:asc|desc, field 支持 name,namespace,age
+	LabelSelector string `form:"labelSelector" json:"labelSelector"`                    // 标签选择器, 如 app=nginx,env!=prod
+	FieldSelector string `form:"fieldSelector" json:"fieldSelector"`                    // 字段选择器, 如 metadata.name=nginx,status.phase=Running, 使用资源不支持的字段时返回400
+	Continue      string `form:"continue" json:"continue"`                              // 上一页返回的continue token
+}
+
+// ListMeta 列表接口分页信息
+type ListMeta struct {
+	Total    int    `json:"total"`    // 过滤后的总数
+	Continue string `json:"continue"` // 下一页的continue token, 为空表示没有更多数据
+}
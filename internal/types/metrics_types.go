@@ -0,0 +1,28 @@
+package types
+
+// MetricsRequest 监控数据的时间范围, 时间戳单位为秒
+type MetricsRequest struct {
+	Start int64 `form:"start" binding:"min=0"` // 开始时间, 默认为结束时间前1小时
+	End   int64 `form:"end" binding:"min=0"`   // 结束时间, 默认为当前时间
+	Step  int64 `form:"step" binding:"min=0"`  // 采样间隔(秒), 默认按时间范围取约120个点, 最小15秒
+}
+
+type MetricsPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// MetricsSeries 一项指标的时间序列, 没有数据时 points 为空
+type MetricsSeries struct {
+	Name   string         `json:"name"` // cpu | memory | networkReceive | networkTransmit
+	Unit   string         `json:"unit"` // core | byte | byte/s
+	Points []MetricsPoint `json:"points"`
+}
+
+type MetricsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*MetricsSeries
+	} `json:"data"` // return data
+}
@@ -16,4 +16,104 @@ type Node struct {
 	ContainerRuntime string         `json:"containerRuntime"`
 	Labels           []ListMapItem  `json:"labels"`
 	Taints           []corev1.Taint `json:"taints"`
+	//是否不可调度
+	Unschedulable bool            `json:"unschedulable"`
+	Conditions    []NodeCondition `json:"conditions"`
+	//资源容量与分配情况, watch 事件中不返回
+	Resources *NodeResources `json:"resources,omitempty"`
+	//节点上的pod, 只在详情中返回
+	Pods []*NodePod `json:"pods,omitempty"`
+}
+
+// NodeCondition 节点状态, 如 Ready、MemoryPressure、DiskPressure、PIDPressure
+type NodeCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason"`
+	Message            string `json:"message"`
+	LastTransitionTime int64  `json:"lastTransitionTime"`
+}
+
+// NodeResource 单项资源的容量与分配情况, cpu 单位为m, 内存和临时存储单位为Mi, pods 为个数
+type NodeResource struct {
+	Capacity        int64   `json:"capacity"`
+	Allocatable     int64   `json:"allocatable"`     // 可分配给pod的部分, 为 capacity 减去系统预留
+	Requests        int64   `json:"requests"`        // 节点上未结束的pod的 requests 之和
+	Limits          int64   `json:"limits"`          // 节点上未结束的pod的 limits 之和, 未设置 limits 的容器不计入
+	RequestsPercent float64 `json:"requestsPercent"` // requests 占 allocatable 的百分比
+	LimitsPercent   float64 `json:"limitsPercent"`   // 超过100表示超卖
+}
+
+type NodeResources struct {
+	Cpu              NodeResource `json:"cpu"`
+	Memory           NodeResource `json:"memory"`
+	EphemeralStorage NodeResource `json:"ephemeralStorage"`
+	Pods             NodeResource `json:"pods"` // requests 和 limits 都为未结束的pod数
+}
+
+// NodePod 节点上的pod及其资源配额, cpu 单位为m, 内存单位为Mi
+type NodePod struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Phase      string `json:"phase"`
+	CpuRequest int64  `json:"cpuRequest"`
+	CpuLimit   int64  `json:"cpuLimit"`
+	MemRequest int64  `json:"memRequest"`
+	MemLimit   int64  `json:"memLimit"`
+	Age        int64  `json:"age"`
+}
+
+// NodeDrainRequest 驱逐参数, 与 kubectl drain 的参数含义相同
+type NodeDrainRequest struct {
+	DeleteEmptyDirData bool   `json:"deleteEmptyDirData"`             // 是否驱逐使用 emptyDir 的pod, 数据会丢失
+	Force              bool   `json:"force"`                          // 是否驱逐没有控制器管理的pod, 驱逐后不会重建
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds"`             // pod 优雅退出时间, 不传使用pod自己的配置
+	TimeoutSeconds     int64  `json:"timeoutSeconds" binding:"min=0"` // 整体超时时间, 0 为600秒
+}
+
+// 驱逐任务状态
+const (
+	DrainStatusRunning   = "Running"
+	DrainStatusSucceeded = "Succeeded"
+	DrainStatusFailed    = "Failed"
+	DrainStatusCanceled  = "Canceled"
+)
+
+// 单个pod的驱逐状态
+const (
+	DrainPodPending  = "Pending"
+	DrainPodEvicting = "Evicting" // 已提交驱逐, 等待pod删除
+	DrainPodEvicted  = "Evicted"
+	DrainPodSkipped  = "Skipped" // daemonset 和静态pod 不驱逐
+	DrainPodFailed   = "Failed"
+)
+
+type NodeDrainPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`   // Pending | Evicting | Evicted | Skipped | Failed
+	Reason    string `json:"reason"`   // 跳过、失败或等待的原因, 如被 PodDisruptionBudget 阻止
+	Attempts  int    `json:"attempts"` // 驱逐请求次数
+}
+
+// NodeDrainTask 驱逐任务, 在后台执行, 通过查询接口获取进度
+type NodeDrainTask struct {
+	ID        string          `json:"id"`
+	Node      string          `json:"node"`
+	Status    string          `json:"status"` // Running | Succeeded | Failed | Canceled
+	Message   string          `json:"message"`
+	Total     int             `json:"total"`   // 需要驱逐的pod数, 不包含跳过的pod
+	Evicted   int             `json:"evicted"` // 已驱逐的pod数
+	Failed    int             `json:"failed"`  // 驱逐失败的pod数
+	Pods      []*NodeDrainPod `json:"pods"`
+	StartTime int64           `json:"startTime"`
+	EndTime   int64           `json:"endTime"` // 未结束为0
+}
+
+type NodeDrainReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Task *NodeDrainTask `json:"task"`
+	} `json:"data"` // return data
 }
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"time"
+)
+
+// PermissionRule 授权规则, 各字段为 * 时匹配全部
+// kinds 与路由中的资源名称一致, 如 pod、deployment、secret、svc、sa、rolebinding、node、pv、sc
+// 集群级资源(node/pv/sc)只有 namespaces 包含 * 的规则才能匹配
+type PermissionRule struct {
+	Cluster    string   `json:"cluster" binding:"required"`
+	Namespaces []string `json:"namespaces" binding:"required,min=1,dive,required"`
+	Kinds      []string `json:"kinds" binding:"required,min=1,dive,required"`
+	Verbs      []string `json:"verbs" binding:"required,min=1,dive,oneof=* view edit delete exec secret-read"`
+}
+
+// CreateAppRoleRequest request params, 修改时同样使用该结构, rules 整体替换
+type CreateAppRoleRequest struct {
+	Name        string           `json:"name" binding:"required,max=64"`
+	Description string           `json:"description" binding:"max=255"`
+	Rules       []PermissionRule `json:"rules" binding:"dive"`
+}
+
+// AppRoleObjDetail detail
+type AppRoleObjDetail struct {
+	ID          uint64           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Rules       []PermissionRule `json:"rules"`
+	CreatedAt   *time.Time       `json:"createdAt"`
+	UpdatedAt   *time.Time       `json:"updatedAt"`
+}
+
+// CreateTeamRequest request params
+type CreateTeamRequest struct {
+	Name        string `json:"name" binding:"required,max=64"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// SetTeamMembersRequest 整体替换团队成员
+type SetTeamMembersRequest struct {
+	UserIDs []uint64 `json:"userIDs" binding:"dive,min=1"`
+}
+
+// TeamObjDetail detail
+type TeamObjDetail struct {
+	ID          uint64     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	UserIDs     []uint64   `json:"userIDs"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+}
+
+// CreateAppRoleBindingRequest 把角色授予用户或团队, userID 和 teamID 必须且只能设置一个
+type CreateAppRoleBindingRequest struct {
+	RoleID uint64 `json:"roleID" binding:"required"`
+	UserID uint64 `json:"userID"`
+	TeamID uint64 `json:"teamID"`
+}
+
+// AppRoleBindingObjDetail detail
+type AppRoleBindingObjDetail struct {
+	ID        uint64     `json:"id"`
+	RoleID    uint64     `json:"roleID"`
+	UserID    uint64     `json:"userID"`
+	TeamID    uint64     `json:"teamID"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+// PermissionCheckItem 集群级资源的 namespace 为空
+type PermissionCheckItem struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind" binding:"required"`
+	Verb      string `json:"verb" binding:"required,oneof=view edit delete exec secret-read"`
+}
+
+// CheckPermissionRequest 批量检查当前用户在集群中的权限
+type CheckPermissionRequest struct {
+	Items []PermissionCheckItem `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+type PermissionCheckResult struct {
+	PermissionCheckItem
+	Allowed bool `json:"allowed"`
+}
+
+// CreatePermissionObjReply only for api docs
+type CreatePermissionObjReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID uint64 `json:"id"` // id
+	} `json:"data"` // return data
+}
+
+// ListAppRolesReply only for api docs
+type ListAppRolesReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Roles []AppRoleObjDetail `json:"roles"`
+	} `json:"data"` // return data
+}
+
+// ListTeamsReply only for api docs
+type ListTeamsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Teams []TeamObjDetail `json:"teams"`
+	} `json:"data"` // return data
+}
+
+// ListAppRoleBindingsReply only for api docs
+type ListAppRoleBindingsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Bindings []AppRoleBindingObjDetail `json:"bindings"`
+	} `json:"data"` // return data
+}
+
+// CheckPermissionReply only for api docs
+type CheckPermissionReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Admin bool                     `json:"admin"` // 管理员拥有全部权限
+		Items []*PermissionCheckResult `json:"items"`
+	} `json:"data"` // return data
+}
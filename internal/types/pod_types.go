@@ -1,12 +1,17 @@
 package types
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 type Base struct {
 	//名字
 	Name string `json:"name"`
 	//标签
 	Labels []ListMapItem `json:"labels"`
+	//注解, 更新时不传表示保持不变
+	Annotations []ListMapItem `json:"annotations"`
 	//命名空间
 	Namespace string `json:"namespace"`
 	//重启策略 Always | Never | On-Failure
@@ -24,13 +29,30 @@ type NodeSelectorTermExpressions struct {
 	Operator corev1.NodeSelectorOperator `json:"operator"`
 	Value    string                      `json:"value"`
 }
+
+// KeyToPath configMap/secret 中的key挂载为指定的文件
+type KeyToPath struct {
+	Key string `json:"key"`
+	//相对挂载目录的文件路径
+	Path string `json:"path"`
+	//文件权限, 如 0644(十进制420), 不传时使用 defaultMode
+	Mode *int32 `json:"mode"`
+}
 type ConfigMapRefVolume struct {
 	Name     string `json:"name"`
 	Optional bool   `json:"optional"`
+	//只挂载指定的key, 不传时挂载所有key
+	Items []KeyToPath `json:"items"`
+	//文件默认权限, 不传时为 0644
+	DefaultMode *int32 `json:"defaultMode"`
 }
 type SecretRefVolume struct {
 	Name     string `json:"name"`
 	Optional bool   `json:"optional"`
+	//只挂载指定的key, 不传时挂载所有key
+	Items []KeyToPath `json:"items"`
+	//文件默认权限, 不传时为 0644
+	DefaultMode *int32 `json:"defaultMode"`
 }
 type NodeScheduling struct {
 	//nodeName nodeSelector nodeAffinity
@@ -38,16 +60,88 @@ type NodeScheduling struct {
 	NodeName     string                        `json:"nodeName"`
 	NodeSelector []ListMapItem                 `json:"nodeSelector"`
 	NodeAffinity []NodeSelectorTermExpressions `json:"nodeAffinity"`
+	//优先调度的节点, 与 type 无关, 可以和 nodeSelector、nodeAffinity 同时使用
+	PreferredNodeAffinity []PreferredNodeAffinity `json:"preferredNodeAffinity"`
+	//与匹配的pod调度到同一拓扑域
+	PodAffinity PodAffinity `json:"podAffinity"`
+	//与匹配的pod调度到不同拓扑域
+	PodAntiAffinity PodAffinity `json:"podAntiAffinity"`
+	//在拓扑域之间均匀分布
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints"`
+	//优先级, 不传时使用 globalDefault 的 PriorityClass
+	PriorityClassName string `json:"priorityClassName"`
+	//调度器, 不传时为 default-scheduler
+	SchedulerName string `json:"schedulerName"`
+}
+
+// PreferredNodeAffinity 按权重优先调度到匹配的节点
+type PreferredNodeAffinity struct {
+	//权重 1~100
+	Weight           int32                         `json:"weight"`
+	MatchExpressions []NodeSelectorTermExpressions `json:"matchExpressions"`
+}
+
+// LabelSelectorExpression 多个值用逗号分隔, 与 NodeSelectorTermExpressions 一致
+type LabelSelectorExpression struct {
+	Key      string                       `json:"key"`
+	Operator metav1.LabelSelectorOperator `json:"operator"`
+	Value    string                       `json:"value"`
+}
+type LabelSelector struct {
+	MatchLabels      []ListMapItem             `json:"matchLabels"`
+	MatchExpressions []LabelSelectorExpression `json:"matchExpressions"`
+}
+
+// PodAffinityTerm 匹配的pod所在的拓扑域
+type PodAffinityTerm struct {
+	LabelSelector LabelSelector `json:"labelSelector"`
+	//匹配哪些命名空间的pod, 不传时为pod所在的命名空间
+	Namespaces []string `json:"namespaces"`
+	//拓扑域对应的节点标签, 如 kubernetes.io/hostname、topology.kubernetes.io/zone
+	TopologyKey string `json:"topologyKey"`
+}
+type WeightedPodAffinityTerm struct {
+	//权重 1~100
+	Weight int32 `json:"weight"`
+	PodAffinityTerm
+}
+type PodAffinity struct {
+	//必须满足
+	Required []PodAffinityTerm `json:"required"`
+	//尽量满足
+	Preferred []WeightedPodAffinityTerm `json:"preferred"`
+}
+
+type TopologySpreadConstraint struct {
+	//拓扑域之间pod数量的最大差值
+	MaxSkew     int32  `json:"maxSkew"`
+	TopologyKey string `json:"topologyKey"`
+	//无法满足时 DoNotSchedule | ScheduleAnyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable"`
+	LabelSelector     LabelSelector                        `json:"labelSelector"`
+	//最少的拓扑域数量, 只能与 DoNotSchedule 同时使用
+	MinDomains *int32 `json:"minDomains"`
 }
 type Volume struct {
 	Name string `json:"name"`
-	//emptydir | configMap | secret | hostPath | downward | pvc
+	//emptyDir | configMap | secret | hostPath | downwardAPI | pvc | projected | nfs | csi | ephemeral
 	Type               string             `json:"type"`
+	EmptyDirVolume     EmptyDirVolume     `json:"emptyDirVolume"`
 	ConfigMapRefVolume ConfigMapRefVolume `json:"configMapRefVolume"`
 	SecretRefVolume    SecretRefVolume    `json:"secretRefVolume"`
 	HostPathVolume     HostPathVolume     `json:"hostPathVolume"`
 	DownwardAPIVolume  DownwardAPIVolume  `json:"downwardAPIVolume"`
 	PVCVolume          PVCVolume          `json:"PVCVolume"`
+	ProjectedVolume    ProjectedVolume    `json:"projectedVolume"`
+	NFSVolume          NFSVolume          `json:"nfsVolume"`
+	CSIVolume          CSIVolume          `json:"csiVolume"`
+	EphemeralVolume    EphemeralVolume    `json:"ephemeralVolume"`
+}
+type EmptyDirVolume struct {
+	//存储介质, 为空时使用节点磁盘, Memory 为 tmpfs
+	Medium corev1.StorageMedium `json:"medium"`
+	//容量上限, 如 1Gi, 为空时不限制
+	SizeLimit string `json:"sizeLimit"`
 }
 type HostPathVolume struct {
 	Type corev1.HostPathType `json:"type"`
@@ -67,6 +161,54 @@ type PVCVolume struct {
 	//pvc name
 	Name string `json:"name"`
 }
+
+// ProjectedVolume 把多个来源投射到同一个目录
+type ProjectedVolume struct {
+	Sources []ProjectedVolumeSource `json:"sources"`
+	//文件默认权限, 不传时为 0644
+	DefaultMode *int32 `json:"defaultMode"`
+}
+type ProjectedVolumeSource struct {
+	//configMap | secret | downwardAPI | serviceAccountToken
+	Type                string                        `json:"type"`
+	ConfigMap           ConfigMapRefVolume            `json:"configMap"`
+	Secret              SecretRefVolume               `json:"secret"`
+	DownwardAPI         DownwardAPIVolume             `json:"downwardAPI"`
+	ServiceAccountToken ServiceAccountTokenProjection `json:"serviceAccountToken"`
+}
+type ServiceAccountTokenProjection struct {
+	//token 的受众, 为空时为 apiserver
+	Audience string `json:"audience"`
+	//有效期(秒), 不传时为 3600
+	ExpirationSeconds *int64 `json:"expirationSeconds"`
+	//相对挂载目录的文件路径
+	Path string `json:"path"`
+}
+type NFSVolume struct {
+	Server   string `json:"server"`
+	Path     string `json:"path"`
+	ReadOnly bool   `json:"readOnly"`
+}
+
+// CSIVolume 内联的CSI临时卷
+type CSIVolume struct {
+	Driver   string `json:"driver"`
+	ReadOnly bool   `json:"readOnly"`
+	FSType   string `json:"fsType"`
+	//驱动的参数
+	VolumeAttributes []ListMapItem `json:"volumeAttributes"`
+	//传给驱动的secret名称
+	NodePublishSecretRef string `json:"nodePublishSecretRef"`
+}
+
+// EphemeralVolume 随pod创建和删除的pvc
+type EphemeralVolume struct {
+	Labels           []ListMapItem                       `json:"labels"`
+	AccessModes      []corev1.PersistentVolumeAccessMode `json:"accessModes"`
+	StorageClassName string                              `json:"storageClassName"`
+	//容量, 如 1Gi
+	Storage string `json:"storage"`
+}
 type DnsConfig struct {
 	Nameservers []string `json:"nameservers"`
 }
@@ -115,6 +257,10 @@ type VolumeMount struct {
 	MountPath string `json:"mountPath"`
 	//是否只读
 	ReadOnly bool `json:"readOnly"`
+	//只挂载卷中的子路径
+	SubPath string `json:"subPath"`
+	//子路径, 可以引用环境变量, 如 $(POD_NAME), 与 subPath 互斥
+	SubPathExpr string `json:"subPathExpr"`
 }
 type ProbeTime struct {
 	//初始化时间 初始化若干秒之后才开始探针
@@ -160,6 +306,49 @@ type ContainerProbe struct {
 	TcpSocket ProbeTcpSocket `json:"tcpSocket"`
 	ProbeTime
 }
+
+// SeccompProfile type 为 RuntimeDefault | Unconfined | Localhost, Localhost 时 localhostProfile 为节点上的配置文件路径
+type SeccompProfile struct {
+	Type             string `json:"type"`
+	LocalhostProfile string `json:"localhostProfile"`
+}
+
+// AppArmorProfile type 为 RuntimeDefault | Unconfined | Localhost, Localhost 时 localhostProfile 为节点上加载的配置名称
+type AppArmorProfile struct {
+	Type             string `json:"type"`
+	LocalhostProfile string `json:"localhostProfile"`
+}
+
+// PodSecurityContext pod级安全配置, 对所有容器生效, 容器级配置优先; 不传的字段不设置
+type PodSecurityContext struct {
+	RunAsUser    *int64 `json:"runAsUser"`
+	RunAsGroup   *int64 `json:"runAsGroup"`
+	RunAsNonRoot *bool  `json:"runAsNonRoot"`
+	//挂载卷的属组
+	FSGroup         *int64           `json:"fsGroup"`
+	SeccompProfile  *SeccompProfile  `json:"seccompProfile"`
+	AppArmorProfile *AppArmorProfile `json:"appArmorProfile"`
+}
+
+type Capabilities struct {
+	//如 NET_ADMIN
+	Add []string `json:"add"`
+	//如 ALL
+	Drop []string `json:"drop"`
+}
+
+// ContainerSecurityContext 容器级安全配置, 特权模式使用 Container.Privileged; 不传的字段不设置
+type ContainerSecurityContext struct {
+	RunAsUser                *int64           `json:"runAsUser"`
+	RunAsGroup               *int64           `json:"runAsGroup"`
+	RunAsNonRoot             *bool            `json:"runAsNonRoot"`
+	ReadOnlyRootFilesystem   *bool            `json:"readOnlyRootFilesystem"`
+	AllowPrivilegeEscalation *bool            `json:"allowPrivilegeEscalation"`
+	Capabilities             *Capabilities    `json:"capabilities"`
+	SeccompProfile           *SeccompProfile  `json:"seccompProfile"`
+	AppArmorProfile          *AppArmorProfile `json:"appArmorProfile"`
+}
+
 type Container struct {
 	//容器名称
 	Name string `json:"name"`
@@ -181,6 +370,8 @@ type Container struct {
 	EnvsFrom []EnvVarFromResource `json:"envsFrom"`
 	//是否开启模式
 	Privileged bool `json:"privileged"`
+	//安全配置
+	SecurityContext *ContainerSecurityContext `json:"securityContext"`
 	//容器申请配额
 	Resources Resources `json:"resources"`
 	//容器卷挂载
@@ -195,6 +386,8 @@ type Container struct {
 type Pod struct {
 	//基础定义信息
 	Base Base `json:"base"`
+	//pod 最长运行时间(秒), 已设置时只能减小
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds"`
 	//pod 容忍
 	Tolerations    []corev1.Toleration `json:"tolerations"`
 	NodeScheduling NodeScheduling      `json:"nodeScheduling"`
@@ -202,6 +395,10 @@ type Pod struct {
 	Volumes []Volume `json:"volumes"`
 	//网络相关
 	NetWorking NetWorking `json:"netWorking"`
+	//pod级安全配置
+	SecurityContext *PodSecurityContext `json:"securityContext"`
+	//镜像拉取凭证(secret名称), 不传时新建使用托管的 k8sadmin-registry, 更新保持不变
+	ImagePullSecrets []string `json:"imagePullSecrets"`
 	///init containers
 	InitContainers []Container `json:"initContainers"`
 	//containers
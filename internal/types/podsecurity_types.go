@@ -0,0 +1,42 @@
+package types
+
+// PodSecurityQuery 创建或更新工作负载时的pod安全标准检查参数
+type PodSecurityQuery struct {
+	// 是否在提交前按pod安全标准检查, 不满足强制级别时拒绝提交
+	PodSecurityCheck bool `form:"podSecurityCheck" json:"podSecurityCheck"`
+	// 检查级别 privileged/baseline/restricted, 为空时使用命名空间 pod-security.kubernetes.io/* 标签
+	PodSecurityLevel string `form:"podSecurityLevel" json:"podSecurityLevel"`
+}
+
+// LintPodRequest pod安全标准检查的查询参数
+type LintPodRequest struct {
+	// 检查级别 privileged/baseline/restricted, 为空时使用命名空间 pod-security.kubernetes.io/* 标签
+	Level string `form:"level" json:"level"`
+}
+
+// PodSecurityViolation 不满足pod安全标准的字段
+type PodSecurityViolation struct {
+	Level   string `json:"level"`   // 违反的最低级别, baseline 或 restricted
+	Check   string `json:"check"`   // 检查项, 如 hostNamespaces、runAsNonRoot
+	Field   string `json:"field"`   // 字段路径, 如 spec.containers[nginx].securityContext.privileged
+	Message string `json:"message"` // 说明
+}
+
+// PodSecurityResult pod安全标准检查结果
+type PodSecurityResult struct {
+	Level      string                 `json:"level"`   // 实际检查的级别
+	Enforce    string                 `json:"enforce"` // 命名空间的 enforce 标签, 未设置时为空
+	Audit      string                 `json:"audit"`   // 命名空间的 audit 标签, 未设置时为空
+	Warn       string                 `json:"warn"`    // 命名空间的 warn 标签, 未设置时为空
+	Allowed    bool                   `json:"allowed"` // 是否满足强制级别, 没有 enforce 标签且未指定级别时按 privileged 强制
+	Violations []PodSecurityViolation `json:"violations"`
+}
+
+// LintPodReply pod安全标准检查结果
+type LintPodReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Result *PodSecurityResult `json:"result"`
+	} `json:"data"` // return data
+}
@@ -0,0 +1,25 @@
+package types
+
+import corev1 "k8s.io/api/core/v1"
+
+type PriorityClassRes struct {
+	Name   string        `json:"name"`
+	Labels []ListMapItem `json:"labels"`
+	//优先级, 越大越优先
+	Value int32 `json:"value"`
+	//没有指定 priorityClassName 的pod使用该优先级
+	GlobalDefault bool `json:"globalDefault"`
+	//抢占策略 PreemptLowerPriority | Never
+	PreemptionPolicy corev1.PreemptionPolicy `json:"preemptionPolicy"`
+	Description      string                  `json:"description"`
+	Age              int64                   `json:"age"`
+}
+
+type PriorityClassListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*PriorityClassRes `json:"list"`
+		ListMeta
+	} `json:"data"` // return data
+}
@@ -74,5 +74,6 @@ type PersistentVolumeResListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*PersistentVolumeRes
+		ListMeta
 	} `json:"data"` // return data
 }
@@ -36,5 +36,6 @@ type PersistentVolumeClaimResListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*PersistentVolumeClaimRes
+		ListMeta
 	} `json:"data"` // return data
 }
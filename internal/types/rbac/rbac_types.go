@@ -26,6 +26,7 @@ type ServiceAccountReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*ServiceAccount
+		types.ListMeta
 	} `json:"data"` // return data
 }
 
@@ -55,6 +56,7 @@ type RoleResListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*Role
+		types.ListMeta
 	} `json:"data"` // return data
 }
 
@@ -85,3 +87,12 @@ type RoleBindingRes struct {
 	RoleRef string `json:"roleRef"`
 	Age     int64  `json:"age"`
 }
+
+type RoleBindingResListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*RoleBindingRes
+		types.ListMeta
+	} `json:"data"` // return data
+}
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"time"
+)
+
+// CreateRegistryRequest request params
+type CreateRegistryRequest struct {
+	Name string `json:"name" binding:"required"`
+	// 镜像地址中的仓库域名, 如 registry.example.com:5000, docker hub 为 https://index.docker.io/v1/
+	Server   string `json:"server" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// UpdateRegistryByIDRequest request params, empty fields are not modified
+type UpdateRegistryByIDRequest struct {
+	ID uint64 `json:"id" binding:""` // uint64 id
+
+	Server   string `json:"server"`
+	Username string `json:"username"`
+	Password string `json:"password"` // 修改后所有集群中的托管secret会在后台更新
+}
+
+// RegistryObjDetail detail, password is never returned
+type RegistryObjDetail struct {
+	ID uint64 `json:"id"` // convert to uint64 id
+
+	Name      string     `json:"name"`
+	Server    string     `json:"server"`
+	Username  string     `json:"username"`
+	Source    string     `json:"source"` // config: system.harbor 配置, 不能删除 | db: 通过接口添加
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+// CreateRegistryReply only for api docs
+type CreateRegistryReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID uint64 `json:"id"` // id
+	} `json:"data"` // return data
+}
+
+// DeleteRegistryByIDReply only for api docs
+type DeleteRegistryByIDReply struct {
+	Code int      `json:"code"` // return code
+	Msg  string   `json:"msg"`  // return information description
+	Data struct{} `json:"data"` // return data
+}
+
+// UpdateRegistryByIDReply only for api docs
+type UpdateRegistryByIDReply struct {
+	Code int      `json:"code"` // return code
+	Msg  string   `json:"msg"`  // return information description
+	Data struct{} `json:"data"` // return data
+}
+
+// ListRegistriesReply only for api docs
+type ListRegistriesReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Registries []RegistryObjDetail `json:"registries"`
+	} `json:"data"` // return data
+}
+
+// SyncRegistryReply only for api docs
+type SyncRegistryReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Failed map[string]string `json:"failed"` // 同步失败的集群及原因
+	} `json:"data"` // return data
+}
@@ -6,9 +6,51 @@ type CreateOrUpdatePodReply struct {
 	Code int    `json:"code"` // return code
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
+		Result *PodUpdateResult `json:"result"`
+		// 请求了pod安全标准检查时返回
+		PodSecurity *PodSecurityResult `json:"podSecurity,omitempty"`
 	} `json:"data"` // return data
 }
 
+// CreateOrUpdateWorkloadReply 创建或更新 deployment、daemonset 的返回
+type CreateOrUpdateWorkloadReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		// 请求了pod安全标准检查时返回
+		PodSecurity *PodSecurityResult `json:"podSecurity,omitempty"`
+	} `json:"data"` // return data
+}
+
+// CreateOrUpdatePodRequest 更新pod的查询参数
+type CreateOrUpdatePodRequest struct {
+	// 变更包含不可原地更新的字段时, 是否允许删除后重建pod, 为false时返回变更列表和错误
+	Recreate bool `form:"recreate" json:"recreate"`
+	PodSecurityQuery
+}
+
+// pod 创建或更新的方式
+const (
+	PodActionCreated   = "created"
+	PodActionUpdated   = "updated"
+	PodActionRecreated = "recreated"
+	PodActionUnchanged = "unchanged"
+)
+
+// PodUpdateResult 创建或更新pod的结果
+type PodUpdateResult struct {
+	Action  string           `json:"action"` // created | updated | recreated | unchanged
+	Changes []PodFieldChange `json:"changes"`
+}
+
+// PodFieldChange 变化的字段, Old/New 为json格式
+type PodFieldChange struct {
+	Field   string `json:"field"` // 如 metadata.labels, spec.containers[nginx].image
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	Mutable bool   `json:"mutable"` // 是否可以原地更新, false 表示需要重建pod
+}
+
 type DeletePodByNameReply struct {
 	Code int      `json:"code"` // return code
 	Msg  string   `json:"msg"`  // return information description
@@ -36,6 +78,7 @@ type ListNamespacesReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		Namespaces []*Namespace `json:"namespaces"`
+		ListMeta
 	} `json:"data"` // return data
 }
 
@@ -44,6 +87,7 @@ type ListPodsReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		PodList []*PodListItem `json:"podList"`
+		ListMeta
 	} `json:"data"` // return data
 }
 type GetPodDetailReply struct {
@@ -56,7 +100,6 @@ type GetPodDetailReply struct {
 
 type GetPodListRequest struct {
 	Namespace string `json:"namespace"`
-	Keyword   string `json:"keyword"`
 	NodeName  string `json:"nodeName"`
 }
 
@@ -70,9 +113,27 @@ type DeletedPodRequest struct {
 	Name      string `json:"name"`
 }
 
-// node 请求列表请求
-type NodeListRequest struct {
-	KeyWord string `json:"keyWord"`
+// GetPodLogsRequest 日志查询参数, namespace 和 name 来自路径
+type GetPodLogsRequest struct {
+	Namespace    string `form:"-" json:"namespace"`
+	Name         string `form:"-" json:"name"`
+	Container    string `form:"container" json:"container"`                                 // 容器名, 单容器pod可不传; download 模式不传时下载所有容器
+	Follow       bool   `form:"follow" json:"follow"`                                       // 持续输出新日志, download 模式忽略
+	TailLines    *int64 `form:"tailLines" json:"tailLines" binding:"omitempty,min=0"`       // 只返回最后N行
+	SinceSeconds *int64 `form:"sinceSeconds" json:"sinceSeconds" binding:"omitempty,min=1"` // 只返回最近N秒
+	Previous     bool   `form:"previous" json:"previous"`                                   // 上一次重启前容器的日志
+	Timestamps   bool   `form:"timestamps" json:"timestamps"`                               // 每行前加上时间戳
+	Download     bool   `form:"download" json:"download"`                                   // 以附件下载
+}
+
+// PodExecRequest 终端参数, namespace 和 name 来自路径
+type PodExecRequest struct {
+	Namespace string `form:"-" json:"namespace"`
+	Name      string `form:"-" json:"name"`
+	Container string `form:"container" json:"container"`                                   // 容器名, 单容器pod可不传
+	Shell     string `form:"shell" json:"shell" binding:"omitempty,oneof=sh bash ash zsh"` // 不传时优先使用bash, 没有则使用sh
+	Cols      uint16 `form:"cols" json:"cols"`                                             // 初始终端列数
+	Rows      uint16 `form:"rows" json:"rows"`                                             // 初始终端行数
 }
 
 type NodeDetailRequest struct {
@@ -92,6 +153,7 @@ type ListNodeReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		Nodes []*Node `json:"nodes"`
+		ListMeta
 	} `json:"data"` // return data
 }
 type UpdatedLabelRequest struct {
@@ -53,6 +53,7 @@ type StorageClassListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*StorageClassRes `json:"list"`
+		ListMeta
 	} `json:"data"` // return data
 }
 type DeleteStorageClassReply struct {
@@ -29,6 +29,7 @@ type ListSecretItemReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*Secret `json:"list"`
+		ListMeta
 	} `json:"data"` // return data
 }
 
@@ -0,0 +1,66 @@
+package types
+
+type StatefulSetBase struct {
+	Name        string        `json:"name" binding:"required"`
+	Namespace   string        `json:"namespace" binding:"required"`
+	Replicas    int32         `json:"replicas" binding:"min=0"`
+	Labels      []ListMapItem `json:"labels"`
+	Selector    []ListMapItem `json:"selector"`
+	ServiceName string        `json:"serviceName"` // 管理pod网络标识的headless service
+	// OrderedReady | Parallel, 创建后不能修改
+	PodManagementPolicy string `json:"podManagementPolicy" binding:"omitempty,oneof=OrderedReady Parallel"`
+	// 滚动更新分区, 只更新序号大于等于该值的pod, 用于灰度发布
+	Partition int32 `json:"partition" binding:"min=0"`
+}
+
+type StatefulSetRequest struct {
+	Base     *StatefulSetBase `json:"base" binding:"required"`
+	Template *Pod             `json:"template" binding:"required"`
+	// 每个副本独立的pvc模板, namespace 和 selector 不生效, storageClassName 为空时使用默认存储类; 创建后不能修改
+	VolumeClaimTemplates []PersistentVolumeClaimRequest `json:"volumeClaimTemplates"`
+}
+
+type StatefulSetResponse struct {
+	Base                 *StatefulSetBase               `json:"base"`
+	Template             *Pod                           `json:"template"`
+	VolumeClaimTemplates []PersistentVolumeClaimRequest `json:"volumeClaimTemplates"`
+}
+
+// StatefulSetOrdinal 按序号排列的pod状态
+type StatefulSetOrdinal struct {
+	Ordinal int    `json:"ordinal"`
+	Name    string `json:"name"`
+	Phase   string `json:"phase"`   // pod 状态, pod 不存在时为空
+	Ready   bool   `json:"ready"`   // 所有容器是否就绪
+	Updated bool   `json:"updated"` // 是否已经是最新版本
+}
+
+type StatefulSetRes struct {
+	Name        string                `json:"name"`
+	Namespace   string                `json:"namespace"`
+	Replicas    int32                 `json:"replicas"`  // 期望副本数
+	Ready       int32                 `json:"ready"`     // 就绪副本数
+	Current     int32                 `json:"current"`   // 当前版本的副本数
+	Updated     int32                 `json:"updated"`   // 已更新到最新版本的副本数
+	Partition   int32                 `json:"partition"` // 滚动更新分区
+	ServiceName string                `json:"serviceName"`
+	Ordinals    []*StatefulSetOrdinal `json:"ordinals"`
+	Age         int64                 `json:"age"`
+}
+
+type StatefulSetDetailReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		StatefulSet *StatefulSetResponse
+	} `json:"data"` // return data
+}
+
+type StatefulSetListReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		List []*StatefulSetRes
+		ListMeta
+	} `json:"data"` // return data
+}
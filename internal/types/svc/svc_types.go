@@ -52,5 +52,6 @@ type ServerResListReply struct {
 	Msg  string `json:"msg"`  // return information description
 	Data struct {
 		List []*ServiceRes
+		types.ListMeta
 	} `json:"data"` // return data
 }
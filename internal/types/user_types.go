@@ -12,23 +12,33 @@ var _ time.Time
 
 // CreateUserRequest request params
 type CreateUserRequest struct {
-	Name string `json:"name" binding:""`
+	Name     string `json:"name" binding:"required,max=100"`
+	Password string `json:"password" binding:"required,min=6,max=72"` // bcrypt 最多处理72字节
+	Status   int    `json:"status" binding:"omitempty,oneof=1 2"`     // 1:启用 2:禁用, 默认启用
 }
 
 // UpdateUserByIDRequest request params
 type UpdateUserByIDRequest struct {
 	ID uint64 `json:"id" binding:""` // uint64 id
 
-	Name string `json:"name" binding:""`
+	Name     string `json:"name" binding:"omitempty,max=100"`
+	Password string `json:"password" binding:"omitempty,min=6,max=72"` // 为空时不修改, 修改后该用户已签发的令牌失效
+	Status   int    `json:"status" binding:"omitempty,oneof=1 2"`      // 1:启用 2:禁用, 禁用后已签发的令牌立即失效
+	Type     int    `json:"type" binding:"omitempty,oneof=1 2"`        // 1:管理员 2:普通用户
 }
 
 // UserObjDetail detail
 type UserObjDetail struct {
 	ID uint64 `json:"id"` // convert to uint64 id
 
-	Name      string     `json:"name"`
-	CreatedAt *time.Time `json:"createdAt"`
-	UpdatedAt *time.Time `json:"updatedAt"`
+	Name               string     `json:"name"`
+	Status             int        `json:"status"`
+	Type               int        `json:"type"`               // 1:管理员 2:普通用户
+	MustChangePassword bool       `json:"mustChangePassword"` // 为true时需要先调用 /api/v1/auth/password 修改密码
+	LastLoginAt        *time.Time `json:"lastLoginAt"`
+	LastLoginIP        string     `json:"lastLoginIP"`
+	CreatedAt          *time.Time `json:"createdAt"`
+	UpdatedAt          *time.Time `json:"updatedAt"`
 }
 
 // CreateUserReply only for api docs
@@ -0,0 +1,25 @@
+package types
+
+// WatchRequest 资源watch参数
+type WatchRequest struct {
+	// 从该版本之后开始推送, 为空时先推送当前所有对象(ADDED)和列表版本的 BOOKMARK, 断线重连时也可以使用 Last-Event-ID 请求头
+	ResourceVersion string `form:"resourceVersion" json:"resourceVersion"`
+	LabelSelector   string `form:"labelSelector" json:"labelSelector"` // 标签选择器, 如 app=nginx
+	FieldSelector   string `form:"fieldSelector" json:"fieldSelector"` // 字段选择器, 由apiserver过滤, 如 spec.nodeName=node1
+}
+
+// WatchEvent 推送给客户端的事件, Object 与对应列表接口的元素结构一致
+// Type: ADDED | MODIFIED | DELETED | BOOKMARK | ERROR
+// 收到 code=410 的 ERROR 事件说明 resourceVersion 已过期, 需要重新请求列表接口后再watch
+type WatchEvent struct {
+	Type            string      `json:"type"`
+	Kind            string      `json:"kind"`
+	ResourceVersion string      `json:"resourceVersion"`
+	Object          interface{} `json:"object,omitempty"`
+}
+
+// WatchError ERROR 事件的 Object
+type WatchError struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
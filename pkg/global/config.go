@@ -11,12 +11,41 @@ type Prometheus struct {
 	Enable bool   `yaml:"enable" json:"enable"`
 	Host   string `json:"host" yaml:"host"`
 	Scheme string `json:"scheme" yaml:"scheme"`
+	// 多个集群共用一个prometheus时区分集群的标签名, 查询时按 <clusterLabel>="<集群名称>" 过滤, 为空时不过滤
+	ClusterLabel string `json:"clusterLabel" yaml:"clusterLabel"`
+	// 按集群名称单独配置的prometheus, 未配置的集群使用上面的配置
+	Clusters map[string]Prometheus `json:"clusters" yaml:"clusters"`
+}
+
+// Jwt 登录令牌配置, 有效期为0时使用默认值
+type Jwt struct {
+	SignKey       string `json:"signKey" yaml:"signKey"`
+	AccessExpire  int    `json:"accessExpire" yaml:"accessExpire"`   // access token 有效期, 单位: 分钟
+	RefreshExpire int    `json:"refreshExpire" yaml:"refreshExpire"` // refresh token 有效期, 单位: 小时
+}
+
+// Admin 启动时该用户不存在则自动创建, 用于首次登录
+type Admin struct {
+	Name     string `json:"name" yaml:"name"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// Impersonation 用户映射了k8s身份时总是以该身份访问集群
+// Required 为true时没有映射的普通用户不能访问集群
+type Impersonation struct {
+	Required bool `json:"required" yaml:"required"`
 }
 type System struct {
-	Addr        string     `json:"addr" yaml:"addr"`
-	Provisioner string     `json:"provisioner" yaml:"provisioner"`
-	Harbor      Harbor     `json:"harbor" yaml:"harbor"`
-	Prometheus  Prometheus `json:"prometheus" yaml:"prometheus"`
+	Addr               string        `json:"addr" yaml:"addr"`
+	Provisioner        string        `json:"provisioner" yaml:"provisioner"`
+	EncryptKey         string        `json:"encryptKey" yaml:"encryptKey"`
+	ExecTranscript     bool          `json:"execTranscript" yaml:"execTranscript"`
+	ExecAllowedOrigins []string      `json:"execAllowedOrigins" yaml:"execAllowedOrigins"` // 允许打开终端的页面来源, 与请求 Host 相同的来源总是允许
+	Harbor             Harbor        `json:"harbor" yaml:"harbor"`
+	Prometheus         Prometheus    `json:"prometheus" yaml:"prometheus"`
+	Jwt                Jwt           `json:"jwt" yaml:"jwt"`
+	Admin              Admin         `json:"admin" yaml:"admin"`
+	Impersonation      Impersonation `json:"impersonation" yaml:"impersonation"`
 }
 
 type Server struct {
@@ -2,10 +2,12 @@ package global
 
 import (
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/xiaofan193/k8sadmin/pkg/harbor"
 )
 
 var (
 	CONF                *Server
 	GlobalKubeConfigSet *kubernetes.Clientset
-	//HarborClient        *harbor.Harbor
+	HarborClient        *harbor.Harbor
 )
@@ -0,0 +1,267 @@
+// Package harbor harbor v2.0 api 客户端, 只实现了镜像选择需要的只读接口
+package harbor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound 项目、仓库或制品不存在
+var ErrNotFound = errors.New("harbor resource not found")
+
+const (
+	defaultTimeout  = 30 * time.Second
+	defaultPageSize = 20
+
+	// 扫描报告的类型, 请求时需要通过 X-Accept-Vulnerabilities 声明
+	vulnerabilityMimeType       = "application/vnd.security.vulnerability.report; version=1.1"
+	harborVulnerabilityMimeType = "application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0"
+)
+
+// Harbor 使用 basic auth 访问 harbor api
+type Harbor struct {
+	host       string
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewHarbor scheme 为空时使用 https
+func NewHarbor(scheme, host, username, password string) *Harbor {
+	if scheme == "" {
+		scheme = "https"
+	}
+	return &Harbor{
+		host:       host,
+		baseURL:    scheme + "://" + host + "/api/v2.0",
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Host 镜像地址中的仓库域名
+func (h *Harbor) Host() string {
+	return h.host
+}
+
+// Image 拼接完整的镜像地址, reference 为 tag 或 digest
+func (h *Harbor) Image(repository, reference string) string {
+	if strings.HasPrefix(reference, "sha256:") {
+		return h.host + "/" + repository + "@" + reference
+	}
+	return h.host + "/" + repository + ":" + reference
+}
+
+// ListOptions 分页参数, Name 为名称模糊匹配
+type ListOptions struct {
+	Page     int
+	PageSize int
+	Name     string
+}
+
+type Project struct {
+	ProjectID    int64             `json:"project_id"`
+	Name         string            `json:"name"`
+	RepoCount    int64             `json:"repo_count"`
+	Metadata     map[string]string `json:"metadata"`
+	CreationTime time.Time         `json:"creation_time"`
+	UpdateTime   time.Time         `json:"update_time"`
+}
+
+// Public metadata 中的值为字符串
+func (p *Project) Public() bool {
+	return p.Metadata["public"] == "true"
+}
+
+// Repository Name 包含项目名, 如 library/nginx
+type Repository struct {
+	ID            int64     `json:"id"`
+	ProjectID     int64     `json:"project_id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	ArtifactCount int64     `json:"artifact_count"`
+	PullCount     int64     `json:"pull_count"`
+	CreationTime  time.Time `json:"creation_time"`
+	UpdateTime    time.Time `json:"update_time"`
+}
+
+type Tag struct {
+	Name     string    `json:"name"`
+	PushTime time.Time `json:"push_time"`
+}
+
+type Artifact struct {
+	Digest       string                   `json:"digest"`
+	Type         string                   `json:"type"`
+	Size         int64                    `json:"size"`
+	PushTime     time.Time                `json:"push_time"`
+	PullTime     time.Time                `json:"pull_time"`
+	Tags         []Tag                    `json:"tags"`
+	ScanOverview map[string]*ScanOverview `json:"scan_overview"`
+}
+
+// Scan 返回漏洞扫描概要, 没有扫描时为nil
+func (a *Artifact) Scan() *ScanOverview {
+	for _, mime := range []string{vulnerabilityMimeType, harborVulnerabilityMimeType} {
+		if overview, ok := a.ScanOverview[mime]; ok {
+			return overview
+		}
+	}
+	return nil
+}
+
+type ScanOverview struct {
+	ScanStatus string       `json:"scan_status"`
+	Severity   string       `json:"severity"`
+	StartTime  time.Time    `json:"start_time"`
+	EndTime    time.Time    `json:"end_time"`
+	Summary    *ScanSummary `json:"summary"`
+}
+
+// ScanSummary Summary 为各级别的漏洞数, 如 Critical: 1
+type ScanSummary struct {
+	Total   int            `json:"total"`
+	Fixable int            `json:"fixable"`
+	Summary map[string]int `json:"summary"`
+}
+
+type Vulnerability struct {
+	ID          string   `json:"id"`
+	Package     string   `json:"package"`
+	Version     string   `json:"version"`
+	FixVersion  string   `json:"fix_version"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	Links       []string `json:"links"`
+}
+
+type vulnerabilityReport struct {
+	Severity        string           `json:"severity"`
+	Vulnerabilities []*Vulnerability `json:"vulnerabilities"`
+}
+
+type errorResponse struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// ListProjects 返回当前用户可见的项目和总数
+func (h *Harbor) ListProjects(ctx context.Context, opts ListOptions) ([]*Project, int, error) {
+	projects := make([]*Project, 0)
+	total, err := h.get(ctx, "/projects", listQuery(opts), &projects)
+	return projects, total, err
+}
+
+func (h *Harbor) ListRepositories(ctx context.Context, project string, opts ListOptions) ([]*Repository, int, error) {
+	repositories := make([]*Repository, 0)
+	total, err := h.get(ctx, "/projects/"+url.PathEscape(project)+"/repositories", listQuery(opts), &repositories)
+	return repositories, total, err
+}
+
+// ListArtifacts repository 可以带项目前缀, 返回带 tag 和扫描概要的制品
+func (h *Harbor) ListArtifacts(ctx context.Context, project, repository string, opts ListOptions) ([]*Artifact, int, error) {
+	query := listQuery(opts)
+	query.Set("with_tag", "true")
+	query.Set("with_scan_overview", "true")
+	artifacts := make([]*Artifact, 0)
+	total, err := h.get(ctx, repositoryPath(project, repository)+"/artifacts", query, &artifacts)
+	return artifacts, total, err
+}
+
+// GetVulnerabilities 制品的漏洞列表, reference 为 tag 或 digest
+func (h *Harbor) GetVulnerabilities(ctx context.Context, project, repository, reference string) ([]*Vulnerability, error) {
+	reports := make(map[string]*vulnerabilityReport)
+	path := repositoryPath(project, repository) + "/artifacts/" + url.PathEscape(reference) + "/additions/vulnerabilities"
+	if _, err := h.get(ctx, path, nil, &reports); err != nil {
+		return nil, err
+	}
+	for _, mime := range []string{vulnerabilityMimeType, harborVulnerabilityMimeType} {
+		if report, ok := reports[mime]; ok && report != nil {
+			return report.Vulnerabilities, nil
+		}
+	}
+	return make([]*Vulnerability, 0), nil
+}
+
+// repositoryPath 仓库名中的 / 需要编码两次
+func repositoryPath(project, repository string) string {
+	repository = strings.TrimPrefix(repository, project+"/")
+	return "/projects/" + url.PathEscape(project) + "/repositories/" + url.PathEscape(url.PathEscape(repository))
+}
+
+func listQuery(opts ListOptions) url.Values {
+	query := url.Values{}
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultPageSize
+	}
+	query.Set("page", strconv.Itoa(opts.Page))
+	query.Set("page_size", strconv.Itoa(opts.PageSize))
+	if opts.Name != "" {
+		query.Set("q", "name=~"+opts.Name)
+	}
+	return query
+}
+
+// get 请求并解析返回, 列表接口的总数在 X-Total-Count 中
+func (h *Harbor) get(ctx context.Context, path string, query url.Values, out interface{}) (int, error) {
+	rawURL := h.baseURL + path
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.SetBasicAuth(h.username, h.password)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Accept-Vulnerabilities", vulnerabilityMimeType+", "+harborVulnerabilityMimeType)
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, responseError(resp.StatusCode, body)
+	}
+	if err = json.Unmarshal(body, out); err != nil {
+		return 0, fmt.Errorf("harbor invalid response: %v", err)
+	}
+	total, _ := strconv.Atoi(resp.Header.Get("X-Total-Count"))
+	return total, nil
+}
+
+func responseError(statusCode int, body []byte) error {
+	result := &errorResponse{}
+	if err := json.Unmarshal(body, result); err == nil && len(result.Errors) > 0 {
+		return fmt.Errorf("harbor response status %d: %s %s", statusCode, result.Errors[0].Code, result.Errors[0].Message)
+	}
+	message := string(body)
+	if len(message) > 200 {
+		message = message[:200] + "..."
+	}
+	return fmt.Errorf("harbor response status %d: %s", statusCode, message)
+}
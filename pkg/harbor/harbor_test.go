@@ -0,0 +1,97 @@
+package harbor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHarbor(t *testing.T, handler http.HandlerFunc) *Harbor {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewHarbor("http", strings.TrimPrefix(srv.URL, "http://"), "admin", "secret")
+}
+
+func TestListProjects(t *testing.T) {
+	client := newTestHarbor(t, func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "admin", username)
+		assert.Equal(t, "secret", password)
+		assert.Equal(t, "/api/v2.0/projects", r.URL.Path)
+		assert.Equal(t, "2", r.URL.Query().Get("page"))
+		assert.Equal(t, "20", r.URL.Query().Get("page_size"))
+		assert.Equal(t, "name=~lib", r.URL.Query().Get("q"))
+		w.Header().Set("X-Total-Count", "21")
+		_, _ = w.Write([]byte(`[{"project_id":1,"name":"library","repo_count":3,"metadata":{"public":"true"},"creation_time":"2024-05-01T08:00:00.000Z"}]`))
+	})
+
+	projects, total, err := client.ListProjects(context.Background(), ListOptions{Page: 2, Name: "lib"})
+	require.NoError(t, err)
+	assert.Equal(t, 21, total)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "library", projects[0].Name)
+	assert.True(t, projects[0].Public())
+	assert.Equal(t, int64(1714550400), projects[0].CreationTime.Unix())
+}
+
+func TestListArtifacts(t *testing.T) {
+	client := newTestHarbor(t, func(w http.ResponseWriter, r *http.Request) {
+		// 多级仓库名中的 / 编码两次
+		assert.Equal(t, "/api/v2.0/projects/library/repositories/tools%252Fbusybox/artifacts", r.URL.EscapedPath())
+		assert.Equal(t, "true", r.URL.Query().Get("with_scan_overview"))
+		assert.Contains(t, r.Header.Get("X-Accept-Vulnerabilities"), vulnerabilityMimeType)
+		_, _ = w.Write([]byte(`[{"digest":"sha256:abc","size":1024,"push_time":"2024-05-01T08:00:00Z",
+			"tags":[{"name":"1.36","push_time":"2024-05-01T08:00:00Z"}],
+			"scan_overview":{"application/vnd.security.vulnerability.report; version=1.1":
+				{"scan_status":"Success","severity":"High","summary":{"total":3,"fixable":1,"summary":{"High":1,"Low":2}}}}}]`))
+	})
+
+	artifacts, _, err := client.ListArtifacts(context.Background(), "library", "library/tools/busybox", ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	require.Len(t, artifacts[0].Tags, 1)
+	assert.Equal(t, "1.36", artifacts[0].Tags[0].Name)
+	scan := artifacts[0].Scan()
+	require.NotNil(t, scan)
+	assert.Equal(t, "High", scan.Severity)
+	assert.Equal(t, 2, scan.Summary.Summary["Low"])
+	assert.Equal(t, client.Host()+"/library/tools/busybox:1.36", client.Image("library/tools/busybox", "1.36"))
+	assert.Equal(t, client.Host()+"/library/tools/busybox@sha256:abc", client.Image("library/tools/busybox", "sha256:abc"))
+}
+
+func TestGetVulnerabilities(t *testing.T) {
+	client := newTestHarbor(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2.0/projects/library/repositories/nginx/artifacts/1.25/additions/vulnerabilities", r.URL.Path)
+		_, _ = w.Write([]byte(`{"application/vnd.security.vulnerability.report; version=1.1":{"severity":"Critical",
+			"vulnerabilities":[{"id":"CVE-2024-0001","package":"openssl","version":"3.0.0","fix_version":"3.0.1","severity":"Critical"}]}}`))
+	})
+
+	vulnerabilities, err := client.GetVulnerabilities(context.Background(), "library", "nginx", "1.25")
+	require.NoError(t, err)
+	require.Len(t, vulnerabilities, 1)
+	assert.Equal(t, "CVE-2024-0001", vulnerabilities[0].ID)
+	assert.Equal(t, "3.0.1", vulnerabilities[0].FixVersion)
+}
+
+func TestHarborError(t *testing.T) {
+	client := newTestHarbor(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"errors":[{"code":"UNAUTHORIZED","message":"unauthorized"}]}`))
+	})
+
+	_, _, err := client.ListRepositories(context.Background(), "missing", ListOptions{})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, _, err = client.ListProjects(context.Background(), ListOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "UNAUTHORIZED")
+}